@@ -0,0 +1,419 @@
+package connect
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// AccessEvaluationOptions - Options of EvaluateAccess and AccessMatrix
+type AccessEvaluationOptions struct {
+	// TimeRanges restricts access policy groups to time range groups, keyed by ID of AccessPolicyGroup.
+	// Rules of the server have no time condition, a service of restricted policy is denied outside of the range.
+	TimeRanges map[KId]KId
+}
+
+// AccessPolicySnapshot - Access policies, IP address groups and time ranges read from the server
+type AccessPolicySnapshot struct {
+	Groups      AccessPolicyGroupList   `json:"groups"`
+	Rules       AccessPolicyRuleList    `json:"rules"`
+	IpAddresses IpAddressEntryList      `json:"ipAddresses"`
+	TimeRanges  TimeRangeEntryList      `json:"timeRanges"`
+	Options     AccessEvaluationOptions `json:"options"`
+}
+
+// AccessDecision - Result of evaluation of one login with explanation of the decision
+type AccessDecision struct {
+	User          string                         `json:"user"`
+	Service       ServiceType                    `json:"service"`
+	ClientIp      string                         `json:"clientIp"`
+	Time          time.Time                      `json:"time"`
+	Allowed       bool                           `json:"allowed"`
+	PolicyGroupId KId                            `json:"policyGroupId"` // applied access policy group
+	PolicyGroup   string                         `json:"policyGroup"`
+	RuleId        KId                            `json:"ruleId"` // matched rule, empty if the group has no rule for the service
+	RuleType      AccessPolicyConnectionRuleType `json:"ruleType"`
+	IpGroup       string                         `json:"ipGroup"`   // IP address group of ServiceIpAllowed/Denied rule
+	IpMatched     bool                           `json:"ipMatched"` // client address is in IpGroup
+	IpPath        StringList                     `json:"ipPath"`    // nested groups down to the entry which contains the address, e.g. "Office", "VPN: 10.0.0.0/255.0.0.0"
+	TimeRange     string                         `json:"timeRange"` // time range group restricting the policy group
+	TimeMatched   bool                           `json:"timeMatched"`
+	TimePath      StringList                     `json:"timePath"`    // nested groups and the entry which contains the time
+	Explanation   StringList                     `json:"explanation"` // human readable steps of the evaluation
+}
+
+// AccessPolicySnapshot - Read access policy groups and rules, IP address groups and time ranges.
+//	options - nil means defaults
+func (s *ServerConnection) AccessPolicySnapshot(options *AccessEvaluationOptions) (*AccessPolicySnapshot, error) {
+	snapshot := &AccessPolicySnapshot{}
+	if options != nil {
+		snapshot.Options = *options
+	}
+	var err error
+	if snapshot.Groups, err = s.AccessPolicyGetGroupList(); err != nil {
+		return nil, err
+	}
+	if snapshot.Rules, _, err = s.AccessPolicyGet(SearchQuery{}); err != nil {
+		return nil, err
+	}
+	if snapshot.IpAddresses, _, err = s.IpAddressGroupsGet(SearchQuery{}); err != nil {
+		return nil, err
+	}
+	if snapshot.TimeRanges, _, err = s.TimeRangesGet(SearchQuery{}); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// EvaluateAccess - Find out whether the user may log in to the service from the address at the time.
+//	user - login name or email address of the user
+//	service - service of the login
+//	clientIp - address of the client
+//	at - time of the login, in time zone of the server
+//	options - nil means defaults
+// Return
+//	decision - result with matched policy group, rule, IP address group and time range
+func (s *ServerConnection) EvaluateAccess(user string, service ServiceType, clientIp string, at time.Time, options *AccessEvaluationOptions) (*AccessDecision, error) {
+	if !service.Valid() {
+		return nil, fmt.Errorf("unknown service %q", service)
+	}
+	ip := net.ParseIP(clientIp)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %q", clientIp)
+	}
+	indexes, err := s.newUserIndexList()
+	if err != nil {
+		return nil, err
+	}
+	index := indexes.forAddress(user)
+	if index == nil && len(indexes) == 1 && !strings.Contains(user, "@") {
+		index = indexes[0]
+	}
+	if index == nil {
+		return nil, fmt.Errorf("domain of user %s not found", user)
+	}
+	found, ok := index.lookup(user)
+	if !ok {
+		return nil, fmt.Errorf("user %s not found", user)
+	}
+	snapshot, err := s.AccessPolicySnapshot(options)
+	if err != nil {
+		return nil, err
+	}
+	decision := snapshot.Evaluate(found, service, ip, at)
+	decision.User = index.primaryAddress(found)
+	return &decision, nil
+}
+
+// Evaluate - Evaluate the login against the snapshot.
+// User without access policy gets the default group. Service without rule in the group is denied.
+//	user - user with AccessPolicy
+//	service - service of the login
+//	clientIp - address of the client
+//	at - time of the login, in time zone of the server
+func (p *AccessPolicySnapshot) Evaluate(user User, service ServiceType, clientIp net.IP, at time.Time) AccessDecision {
+	decision := AccessDecision{User: user.LoginName, Service: service, ClientIp: clientIp.String(), Time: at, Explanation: StringList{}}
+	explain := func(format string, a ...interface{}) {
+		decision.Explanation = append(decision.Explanation, fmt.Sprintf(format, a...))
+	}
+	group, ok := p.policyGroup(user.AccessPolicy.Id)
+	if !ok {
+		explain("access policy group %s not found", user.AccessPolicy.Id)
+		return decision
+	}
+	decision.PolicyGroupId, decision.PolicyGroup = group.Id, group.Name
+	if user.AccessPolicy.Id == "" {
+		explain("user has no access policy, default group %s applies", group.Name)
+	} else {
+		explain("user has access policy group %s", group.Name)
+	}
+	var rule *AccessPolicyRule
+	for i := range p.Rules {
+		if p.Rules[i].GroupId == group.Id && p.Rules[i].Service == service {
+			rule = &p.Rules[i]
+			break
+		}
+	}
+	if rule == nil {
+		explain("group %s has no rule for %s, access is denied", group.Name, service)
+		return decision
+	}
+	decision.RuleId, decision.RuleType = rule.Id, rule.Rule.Type
+	switch rule.Rule.Type {
+	case ServiceAllowed:
+		decision.Allowed = true
+		explain("rule %s allows %s from any address", rule.Id, service)
+	case ServiceDenied:
+		explain("rule %s denies %s", rule.Id, service)
+	case ServiceIpAllowed, ServiceIpDenied:
+		decision.IpGroup = p.ipGroupName(rule.Rule.GroupId)
+		decision.IpPath, decision.IpMatched = p.ipGroupContains(rule.Rule.GroupId, clientIp, map[KId]bool{})
+		in := "is not in"
+		if decision.IpMatched {
+			in = "is in"
+		}
+		explain("address %s %s IP address group %s", clientIp, in, decision.IpGroup)
+		decision.Allowed = decision.IpMatched == (rule.Rule.Type == ServiceIpAllowed)
+		if decision.Allowed {
+			explain("rule %s (%s) allows %s", rule.Id, rule.Rule.Type, service)
+		} else {
+			explain("rule %s (%s) denies %s", rule.Id, rule.Rule.Type, service)
+		}
+	default:
+		explain("rule %s has unknown type %s, access is denied", rule.Id, rule.Rule.Type)
+	}
+	timeGroupId, restricted := p.Options.TimeRanges[group.Id]
+	if !decision.Allowed || !restricted {
+		return decision
+	}
+	decision.TimeRange = p.timeGroupName(timeGroupId)
+	decision.TimePath, decision.TimeMatched = p.timeGroupContains(timeGroupId, at, map[KId]bool{})
+	if decision.TimeMatched {
+		explain("time %s is in time range %s", at.Format("Mon 2006-01-02 15:04"), decision.TimeRange)
+	} else {
+		decision.Allowed = false
+		explain("time %s is not in time range %s, access is denied", at.Format("Mon 2006-01-02 15:04"), decision.TimeRange)
+	}
+	return decision
+}
+
+// policyGroup returns the group with given id or the default group for empty id
+func (p *AccessPolicySnapshot) policyGroup(id KId) (AccessPolicyGroup, bool) {
+	for _, group := range p.Groups {
+		if id == "" && group.IsDefault || id != "" && group.Id == id {
+			return group, true
+		}
+	}
+	return AccessPolicyGroup{}, false
+}
+
+func (p *AccessPolicySnapshot) ipGroupName(id KId) string {
+	for _, entry := range p.IpAddresses {
+		if entry.GroupId == id {
+			return entry.GroupName
+		}
+	}
+	return string(id)
+}
+
+func (p *AccessPolicySnapshot) timeGroupName(id KId) string {
+	for _, entry := range p.TimeRanges {
+		if entry.GroupId == id {
+			return entry.GroupName
+		}
+	}
+	return string(id)
+}
+
+// ipGroupContains checks enabled entries of the group including child groups, visited guards against cycles
+func (p *AccessPolicySnapshot) ipGroupContains(id KId, ip net.IP, visited map[KId]bool) (StringList, bool) {
+	if visited[id] {
+		return nil, false
+	}
+	visited[id] = true
+	for _, entry := range p.IpAddresses {
+		if entry.GroupId != id || !entry.Enabled {
+			continue
+		}
+		if entry.Type == ChildGroup {
+			if path, ok := p.ipGroupContains(entry.ChildGroupId, ip, visited); ok {
+				return append(StringList{entry.GroupName}, path...), true
+			}
+			continue
+		}
+		if description, ok := ipEntryContains(entry, ip); ok {
+			return StringList{entry.GroupName + ": " + description}, true
+		}
+	}
+	return nil, false
+}
+
+// ipEntryContains matches the address with one entry; host names are not resolved
+func ipEntryContains(entry IpAddressEntry, ip net.IP) (string, bool) {
+	switch entry.Type {
+	case Host:
+		host := net.ParseIP(entry.Host)
+		return entry.Host, host != nil && host.Equal(ip)
+	case Network:
+		network, mask := net.ParseIP(string(entry.Addr1)), net.ParseIP(string(entry.Addr2))
+		if network == nil || mask == nil {
+			return "", false
+		}
+		if network.To4() != nil {
+			network, mask = network.To4(), mask.To4()
+		}
+		ipNet := net.IPNet{IP: network.Mask(net.IPMask(mask)), Mask: net.IPMask(mask)}
+		return string(entry.Addr1) + "/" + string(entry.Addr2), ipNet.Contains(ip)
+	case Range:
+		from, to := net.ParseIP(string(entry.Addr1)), net.ParseIP(string(entry.Addr2))
+		if from == nil || to == nil || (from.To4() == nil) != (ip.To4() == nil) {
+			return "", false
+		}
+		ip16 := ip.To16()
+		return string(entry.Addr1) + "-" + string(entry.Addr2), bytes.Compare(ip16, from.To16()) >= 0 && bytes.Compare(ip16, to.To16()) <= 0
+	case IpPrefix:
+		_, ipNet, err := net.ParseCIDR(entry.Host)
+		return entry.Host, err == nil && ipNet.Contains(ip)
+	case ThisMachine:
+		return "this machine", ip.IsLoopback()
+	}
+	return "", false
+}
+
+// timeGroupContains checks enabled entries of the time range group including child groups
+func (p *AccessPolicySnapshot) timeGroupContains(id KId, at time.Time, visited map[KId]bool) (StringList, bool) {
+	if visited[id] {
+		return nil, false
+	}
+	visited[id] = true
+	for _, entry := range p.TimeRanges {
+		if entry.GroupId != id || !entry.Enabled {
+			continue
+		}
+		if entry.Type == TimeRangeChildGroup {
+			if path, ok := p.timeGroupContains(entry.ChildGroupId, at, visited); ok {
+				return append(StringList{entry.GroupName}, path...), true
+			}
+			continue
+		}
+		if description, ok := timeEntryContains(entry, at); ok {
+			return StringList{entry.GroupName + ": " + description}, true
+		}
+	}
+	return nil, false
+}
+
+// timeEntryContains matches the time with one entry; the end of the range is exclusive and daily range may pass midnight
+func timeEntryContains(entry TimeRangeEntry, at time.Time) (string, bool) {
+	minutes := at.Hour()*60 + at.Minute()
+	from, to := entry.FromTime.Hour*60+entry.FromTime.Min, entry.ToTime.Hour*60+entry.ToTime.Min
+	span := fmt.Sprintf("%02d:%02d-%02d:%02d", entry.FromTime.Hour, entry.FromTime.Min, entry.ToTime.Hour, entry.ToTime.Min)
+	switch entry.Type {
+	case TimeRangeDaily:
+		day := dayType(at.Weekday())
+		if from > to && minutes < to {
+			// the range started on the previous day
+			day = dayType((at.Weekday() + 6) % 7)
+		}
+		if len(entry.Days) > 0 && !containsDay(entry.Days, day) {
+			return "", false
+		}
+		if from <= to {
+			return span, minutes >= from && minutes < to
+		}
+		return span, minutes >= from || minutes < to
+	case TimeRangeWeekly:
+		week := 7 * 24 * 60
+		start, end := dayIndex(entry.FromDay)*24*60+from, dayIndex(entry.ToDay)*24*60+to
+		now := dayIndex(dayType(at.Weekday()))*24*60 + minutes
+		description := fmt.Sprintf("%s %02d:%02d-%s %02d:%02d", entry.FromDay, entry.FromTime.Hour, entry.FromTime.Min, entry.ToDay, entry.ToTime.Hour, entry.ToTime.Min)
+		return description, (now-start+week)%week < (end-start+week)%week
+	case TimeRangeAbsolute:
+		start, end := dateTime(entry.FromDate, entry.FromTime, at.Location()), dateTime(entry.ToDate, entry.ToTime, at.Location())
+		return start.Format("2006-01-02 15:04") + " - " + end.Format("2006-01-02 15:04"), !at.Before(start) && at.Before(end)
+	}
+	return "", false
+}
+
+var weekDays = []DayType{Monday, Tuesday, Wednesday, Thursday, Friday, Saturday, Sunday}
+
+// dayType converts the weekday of time package
+func dayType(day time.Weekday) DayType {
+	return weekDays[(day+6)%7]
+}
+
+// dayIndex returns 0 for Monday and 6 for Sunday
+func dayIndex(day DayType) int {
+	for i, d := range weekDays {
+		if d == day {
+			return i
+		}
+	}
+	return 0
+}
+
+func containsDay(days DayList, day DayType) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// AccessMatrixRow - Decisions of one user for all services
+type AccessMatrixRow struct {
+	User        string           `json:"user"`
+	PolicyGroup string           `json:"policyGroup"`
+	Decisions   []AccessDecision `json:"decisions"` // in order of AccessMatrix.Services
+}
+
+// AccessMatrix - Decisions of all users and services for one address and time
+type AccessMatrix struct {
+	ClientIp string            `json:"clientIp"`
+	Time     time.Time         `json:"time"`
+	Services []ServiceType     `json:"services"`
+	Rows     []AccessMatrixRow `json:"rows"`
+}
+
+// AccessMatrix - Evaluate access of all users of all domains to all services.
+//	clientIp - address of the client
+//	at - time of the login, in time zone of the server
+//	options - nil means defaults
+func (s *ServerConnection) AccessMatrix(clientIp string, at time.Time, options *AccessEvaluationOptions) (*AccessMatrix, error) {
+	ip := net.ParseIP(clientIp)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %q", clientIp)
+	}
+	indexes, err := s.newUserIndexList()
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := s.AccessPolicySnapshot(options)
+	if err != nil {
+		return nil, err
+	}
+	matrix := &AccessMatrix{ClientIp: ip.String(), Time: at, Services: ServiceTypeValues, Rows: []AccessMatrixRow{}}
+	for _, index := range indexes {
+		for _, user := range index.users {
+			row := AccessMatrixRow{User: index.primaryAddress(user)}
+			for _, service := range matrix.Services {
+				decision := snapshot.Evaluate(user, service, ip, at)
+				decision.User = row.User
+				row.PolicyGroup = decision.PolicyGroup
+				row.Decisions = append(row.Decisions, decision)
+			}
+			matrix.Rows = append(matrix.Rows, row)
+		}
+	}
+	return matrix, nil
+}
+
+// WriteAccessMatrix - Write the matrix as text table with a column for each service
+//	w - output
+//	matrix - evaluated matrix
+func WriteAccessMatrix(w io.Writer, matrix *AccessMatrix) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Access from %s at %s\n", matrix.ClientIp, matrix.Time.Format("Mon 2006-01-02 15:04"))
+	header := []string{"USER", "POLICY"}
+	for _, service := range matrix.Services {
+		header = append(header, strings.ToUpper(strings.TrimPrefix(string(service), "Service")))
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range matrix.Rows {
+		line := []string{row.User, row.PolicyGroup}
+		for _, decision := range row.Decisions {
+			if decision.Allowed {
+				line = append(line, "allow")
+			} else {
+				line = append(line, "deny")
+			}
+		}
+		fmt.Fprintln(tw, strings.Join(line, "\t"))
+	}
+	return tw.Flush()
+}
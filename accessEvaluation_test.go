@@ -0,0 +1,132 @@
+package connect
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testAccessSnapshot() *AccessPolicySnapshot {
+	return &AccessPolicySnapshot{
+		Groups: AccessPolicyGroupList{{Id: "p1", Name: "Default", IsDefault: true}, {Id: "p2", Name: "Office only"}},
+		Rules: AccessPolicyRuleList{
+			{Id: "r1", GroupId: "p1", Service: ServiceIMAP, Rule: AccessPolicyConnectionRule{Type: ServiceAllowed}},
+			{Id: "r2", GroupId: "p1", Service: ServicePOP3, Rule: AccessPolicyConnectionRule{Type: ServiceIpDenied, GroupId: "g1"}},
+			{Id: "r3", GroupId: "p2", Service: ServiceIMAP, Rule: AccessPolicyConnectionRule{Type: ServiceIpAllowed, GroupId: "g1"}},
+		},
+		IpAddresses: IpAddressEntryList{
+			{GroupId: "g1", GroupName: "Office", Type: Host, Host: "192.0.2.1", Enabled: true},
+			{GroupId: "g1", GroupName: "Office", Type: ChildGroup, ChildGroupId: "g2", Enabled: true},
+			{GroupId: "g2", GroupName: "VPN", Type: ChildGroup, ChildGroupId: "g1", Enabled: true},
+			{GroupId: "g2", GroupName: "VPN", Type: Network, Addr1: "10.8.0.0", Addr2: "255.255.0.0", Enabled: true},
+			{GroupId: "g2", GroupName: "VPN", Type: Range, Addr1: "172.16.0.10", Addr2: "172.16.0.20", Enabled: false},
+		},
+		TimeRanges: TimeRangeEntryList{
+			{GroupId: "t1", GroupName: "Working hours", Type: TimeRangeChildGroup, ChildGroupId: "t2", Enabled: true},
+			{GroupId: "t2", GroupName: "Weekdays", Type: TimeRangeDaily, Enabled: true, FromTime: Time{Hour: 8}, ToTime: Time{Hour: 17},
+				Days: DayList{Monday, Tuesday, Wednesday, Thursday, Friday}},
+		},
+		Options: AccessEvaluationOptions{TimeRanges: map[KId]KId{"p2": "t1"}},
+	}
+}
+
+func TestAccessPolicyEvaluate(t *testing.T) {
+	snapshot := testAccessSnapshot()
+	monday := time.Date(2024, 3, 4, 9, 30, 0, 0, time.UTC)
+	office := User{LoginName: "jdoe", AccessPolicy: IdEntity{Id: "p2"}}
+	for _, c := range []struct {
+		user    User
+		service ServiceType
+		ip      string
+		at      time.Time
+		allowed bool
+		last    string
+	}{
+		{User{LoginName: "all"}, ServiceIMAP, "203.0.113.1", monday, true, "rule r1 allows ServiceIMAP from any address"},
+		{User{LoginName: "all"}, ServicePOP3, "10.8.3.4", monday, false, "rule r2 (ServiceIpDenied) denies ServicePOP3"},
+		{User{LoginName: "all"}, ServiceEWS, "10.8.3.4", monday, false, "group Default has no rule for ServiceEWS, access is denied"},
+		{office, ServiceIMAP, "10.8.3.4", monday, true, "time Mon 2024-03-04 09:30 is in time range Working hours"},
+		{office, ServiceIMAP, "172.16.0.15", monday, false, "rule r3 (ServiceIpAllowed) denies ServiceIMAP"},
+		{office, ServiceIMAP, "192.0.2.1", monday.AddDate(0, 0, 5), false, "time Sat 2024-03-09 09:30 is not in time range Working hours, access is denied"},
+	} {
+		decision := snapshot.Evaluate(c.user, c.service, net.ParseIP(c.ip), c.at)
+		if decision.Allowed != c.allowed || decision.Explanation[len(decision.Explanation)-1] != c.last {
+			t.Errorf("%s %s %s: unexpected decision %v %q", c.user.LoginName, c.service, c.ip, decision.Allowed, decision.Explanation)
+		}
+	}
+	decision := snapshot.Evaluate(office, ServiceIMAP, net.ParseIP("10.8.3.4"), monday)
+	if strings.Join(decision.IpPath, "|") != "Office|VPN: 10.8.0.0/255.255.0.0" || strings.Join(decision.TimePath, "|") != "Working hours|Weekdays: 08:00-17:00" {
+		t.Errorf("unexpected path %q %q", decision.IpPath, decision.TimePath)
+	}
+}
+
+func TestTimeEntryContains(t *testing.T) {
+	night := TimeRangeEntry{Type: TimeRangeDaily, FromTime: Time{Hour: 22}, ToTime: Time{Hour: 6}, Days: DayList{Friday}}
+	weekend := TimeRangeEntry{Type: TimeRangeWeekly, FromDay: Friday, FromTime: Time{Hour: 18}, ToDay: Monday, ToTime: Time{Hour: 6}}
+	holiday := TimeRangeEntry{Type: TimeRangeAbsolute, FromDate: Date{Year: 2024, Month: 11, Day: 24}, ToDate: Date{Year: 2024, Month: 11, Day: 27}}
+	for _, c := range []struct {
+		entry    TimeRangeEntry
+		at       time.Time
+		expected bool
+	}{
+		{night, time.Date(2024, 3, 8, 23, 0, 0, 0, time.UTC), true},
+		{night, time.Date(2024, 3, 9, 5, 59, 0, 0, time.UTC), true},
+		{night, time.Date(2024, 3, 8, 5, 0, 0, 0, time.UTC), false},
+		{weekend, time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC), true},
+		{weekend, time.Date(2024, 3, 11, 6, 0, 0, 0, time.UTC), false},
+		{holiday, time.Date(2024, 12, 26, 12, 0, 0, 0, time.UTC), true},
+		{holiday, time.Date(2024, 12, 27, 0, 0, 0, 0, time.UTC), false},
+	} {
+		if _, ok := timeEntryContains(c.entry, c.at); ok != c.expected {
+			t.Errorf("%s %s: expected %v", c.entry.Type, c.at.Format(time.RFC1123), c.expected)
+		}
+	}
+}
+
+func TestAccessMatrix(t *testing.T) {
+	snapshot := testAccessSnapshot()
+	conn, fake := newFakeConnection(t, map[string]fakeHandler{
+		"Domains.get": result(map[string]interface{}{"list": DomainList{{Id: "d1", Name: "example.com"}}}),
+		"Users.get": result(map[string]interface{}{"list": UserList{
+			{Id: "u1", LoginName: "all"},
+			{Id: "u2", LoginName: "jdoe", AccessPolicy: IdEntity{Id: "p2"}},
+		}}),
+		"AccessPolicy.getGroupList": result(map[string]interface{}{"groups": snapshot.Groups}),
+		"AccessPolicy.get":          result(map[string]interface{}{"list": snapshot.Rules}),
+		"IpAddressGroups.get":       result(map[string]interface{}{"list": snapshot.IpAddresses}),
+		"TimeRanges.get":            result(map[string]interface{}{"list": snapshot.TimeRanges}),
+	})
+	saturday := time.Date(2024, 3, 9, 9, 30, 0, 0, time.UTC)
+	decision, err := conn.EvaluateAccess("JDoe@example.com", ServiceIMAP, "10.8.3.4", saturday, &snapshot.Options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decision.Allowed || decision.User != "jdoe@example.com" || decision.PolicyGroup != "Office only" || !decision.IpMatched || decision.TimeMatched {
+		t.Errorf("unexpected decision %+v", decision)
+	}
+	if _, err = conn.EvaluateAccess("nobody@example.com", ServiceIMAP, "10.8.3.4", saturday, nil); err == nil || err.Error() != "user nobody@example.com not found" {
+		t.Errorf("unexpected error %v", err)
+	}
+	matrix, err := conn.AccessMatrix("192.0.2.1", saturday.AddDate(0, 0, -4), &snapshot.Options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fake.called("AccessPolicy.get") != 2 || len(matrix.Rows) != 2 || len(matrix.Rows[1].Decisions) != len(ServiceTypeValues) {
+		t.Fatalf("unexpected matrix %+v", matrix)
+	}
+	b := &bytes.Buffer{}
+	if err = WriteAccessMatrix(b, matrix); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"Access from 192.0.2.1 at Tue 2024-03-05 09:30",
+		"USER              POLICY       ACTIVESYNC  EWS   IMAP   KOFF  POP3  WEBDAV  WEBMAIL  XMPP",
+		"all@example.com   Default      deny        deny  allow  deny  deny  deny    deny     deny",
+		"jdoe@example.com  Office only  deny        deny  allow  deny  deny  deny    deny     deny",
+	}
+	if strings.TrimSpace(b.String()) != strings.Join(expected, "\n") {
+		t.Errorf("unexpected text\n%s", b)
+	}
+}
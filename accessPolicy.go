@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 type ServiceType string
 
 const (
@@ -78,7 +76,7 @@ func (s *ServerConnection) AccessPolicyCreate(rules AccessPolicyRuleList) (Error
 			Result CreateResultList `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, errors.Result.Result, err
 }
 
@@ -101,7 +99,7 @@ func (s *ServerConnection) AccessPolicyCreateGroupList(groups AccessPolicyGroupL
 			Result CreateResultList `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, errors.Result.Result, err
 }
 
@@ -125,7 +123,7 @@ func (s *ServerConnection) AccessPolicyGet(query SearchQuery) (AccessPolicyRuleL
 			TotalItems int                  `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -142,7 +140,7 @@ func (s *ServerConnection) AccessPolicyGetGroupList() (AccessPolicyGroupList, er
 			Groups AccessPolicyGroupList `json:"groups"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &groups)
+	err = s.unmarshal(data, &groups)
 	return groups.Result.Groups, err
 }
 
@@ -159,7 +157,7 @@ func (s *ServerConnection) AccessPolicyGetServiceList() (ServiceTypeInfoList, er
 			Services ServiceTypeInfoList `json:"services"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &services)
+	err = s.unmarshal(data, &services)
 	return services.Result.Services, err
 }
 
@@ -180,7 +178,7 @@ func (s *ServerConnection) AccessPolicyRemove(ruleIds KIdList) (ErrorList, error
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -201,7 +199,7 @@ func (s *ServerConnection) AccessPolicyRemoveGroupList(groupIds KIdList) (ErrorL
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -222,7 +220,7 @@ func (s *ServerConnection) AccessPolicySet(rules AccessPolicyRuleList) (ErrorLis
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -243,6 +241,6 @@ func (s *ServerConnection) AccessPolicySetGroupList(groups AccessPolicyGroupList
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
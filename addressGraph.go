@@ -0,0 +1,423 @@
+package connect
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// AddressNodeKind - Kind of node in the address-resolution graph
+type AddressNodeKind string
+
+const (
+	AddressEmail        AddressNodeKind = "AddressEmail"        // local email address, expanded to entities which own it
+	AddressDomainAlias  AddressNodeKind = "AddressDomainAlias"  // address in a domain alias, rewritten to the domain name
+	AddressExternal     AddressNodeKind = "AddressExternal"     // address outside of local domains, mail leaves the server
+	AddressUser         AddressNodeKind = "AddressUser"         // user mailbox
+	AddressGroup        AddressNodeKind = "AddressGroup"        // group with email address
+	AddressMailingList  AddressNodeKind = "AddressMailingList"  // mailing list
+	AddressAlias        AddressNodeKind = "AddressAlias"        // alias
+	AddressPublicFolder AddressNodeKind = "AddressPublicFolder" // mail public folder
+)
+
+// AddressNode - Entity or address in the address-resolution graph
+type AddressNode struct {
+	Kind     AddressNodeKind `json:"kind"`
+	Id       KId             `json:"id"`       // global identification of entity, empty for addresses
+	DomainId KId             `json:"domainId"` // domain of entity or local address
+	Name     string          `json:"name"`     // login name, group, ML, alias or public folder name
+	Address  string          `json:"address"`  // lower-cased email address of the node
+}
+
+// AddressExpansion - Node of an expansion tree of an address
+type AddressExpansion struct {
+	Node     AddressNode         `json:"node"`
+	Reason   string              `json:"reason"`             // why mail for the parent gets to this node
+	Delivers bool                `json:"delivers"`           // the node stores mail (mailbox, public folder) or sends it out of the server
+	Loop     bool                `json:"loop,omitempty"`     // address is already on the path from the root, expansion stops here
+	Dangling bool                `json:"dangling,omitempty"` // local address or public folder which does not exist
+	Children []*AddressExpansion `json:"children,omitempty"`
+}
+
+// AddressExpansionList - List of expansion nodes
+type AddressExpansionList []*AddressExpansion
+
+// Recipients returns sorted distinct final recipients of the expansion: mailbox addresses,
+// public folder names and external addresses
+func (e *AddressExpansion) Recipients() StringList {
+	found := map[string]bool{}
+	e.walk(func(node *AddressExpansion) {
+		if !node.Delivers {
+			return
+		}
+		switch node.Node.Kind {
+		case AddressPublicFolder:
+			found[node.Node.Name] = true
+		default:
+			found[node.Node.Address] = true
+		}
+	})
+	recipients := make(StringList, 0, len(found))
+	for recipient := range found {
+		recipients = append(recipients, recipient)
+	}
+	sort.Strings(recipients)
+	return recipients
+}
+
+func (e *AddressExpansion) walk(visit func(node *AddressExpansion)) {
+	visit(e)
+	for _, child := range e.Children {
+		child.walk(visit)
+	}
+}
+
+// AddressGraph - Snapshot of all local addresses and entities which receive mail for them.
+// Mailing list members are loaded lazily on first expansion of the list.
+type AddressGraph struct {
+	s         *ServerConnection
+	indexes   userIndexList
+	owners    map[string][]AddressNode // exact address -> owners
+	wildcards []Alias                  // aliases with ? or * in name
+	aliases   map[KId]Alias
+	groups    map[KId]Group
+	mls       map[KId]Ml
+	folders   map[KId]PublicFolder
+	mlMembers map[KId]UserOrEmailList
+}
+
+// NewAddressGraph - Load users, groups, mailing lists, aliases and mail public folders of all domains.
+// Return
+//	graph - address-resolution graph
+func (s *ServerConnection) NewAddressGraph() (*AddressGraph, error) {
+	indexes, err := s.newUserIndexList()
+	if err != nil {
+		return nil, err
+	}
+	g := &AddressGraph{
+		s:         s,
+		indexes:   indexes,
+		owners:    map[string][]AddressNode{},
+		aliases:   map[KId]Alias{},
+		groups:    map[KId]Group{},
+		mls:       map[KId]Ml{},
+		folders:   map[KId]PublicFolder{},
+		mlMembers: map[KId]UserOrEmailList{},
+	}
+	for _, index := range indexes {
+		domain := index.domain
+		for _, user := range index.users {
+			node := AddressNode{Kind: AddressUser, Id: user.Id, DomainId: domain.Id, Name: user.LoginName, Address: index.primaryAddress(user)}
+			g.addOwner(index, user.LoginName, node)
+			for _, address := range user.EmailAddresses {
+				g.addOwner(index, address, node)
+			}
+		}
+		groups, _, err := s.GroupsGet(SearchQuery{}, domain.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, group := range groups {
+			g.groups[group.Id] = group
+			if len(group.EmailAddresses) == 0 {
+				continue
+			}
+			node := AddressNode{Kind: AddressGroup, Id: group.Id, DomainId: domain.Id, Name: group.Name, Address: index.qualify(group.EmailAddresses[0])}
+			for _, address := range group.EmailAddresses {
+				g.addOwner(index, address, node)
+			}
+		}
+		mls, _, err := s.MailingListsGet(SearchQuery{}, domain.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, ml := range mls {
+			g.mls[ml.Id] = ml
+			g.addOwner(index, ml.Name, AddressNode{Kind: AddressMailingList, Id: ml.Id, DomainId: domain.Id, Name: ml.Name, Address: index.qualify(ml.Name)})
+		}
+		aliases, _, err := s.AliasesGet(SearchQuery{}, domain.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, alias := range aliases {
+			g.aliases[alias.Id] = alias
+			if strings.ContainsAny(alias.Name, "?*") {
+				g.wildcards = append(g.wildcards, alias)
+				continue
+			}
+			g.addOwner(index, alias.Name, g.aliasNode(alias))
+		}
+		folders, err := s.AliasesGetMailPublicFolderList(domain.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, folder := range folders {
+			g.folders[folder.Id] = folder
+		}
+	}
+	return g, nil
+}
+
+// Owners returns entities which own the address. Aliases shadow other entities with the same address,
+// wildcard aliases are used only if no entity owns the address exactly.
+func (g *AddressGraph) Owners(address string) []AddressNode {
+	address = g.canonical(address)
+	owners := g.owners[address]
+	var aliases []AddressNode
+	for _, owner := range owners {
+		if owner.Kind == AddressAlias {
+			aliases = append(aliases, owner)
+		}
+	}
+	if len(aliases) > 0 {
+		return aliases
+	}
+	if len(owners) > 0 {
+		return owners
+	}
+	local, domain := splitAddress(address)
+	for _, alias := range g.wildcards {
+		index := g.indexes.forDomain(alias.DomainId)
+		if index == nil || strings.ToLower(index.domain.Name) != domain {
+			continue
+		}
+		if ok, _ := filepath.Match(strings.ToLower(alias.Name), local); ok {
+			aliases = append(aliases, g.aliasNode(alias))
+		}
+	}
+	return aliases
+}
+
+// Expand - Build the expansion tree of the address through aliases, groups and mailing lists.
+//	address - email address to expand
+// Return
+//	expansion - tree with the address in the root and final recipients in leaves
+func (g *AddressGraph) Expand(address string) (*AddressExpansion, error) {
+	return g.expandAddress(strings.ToLower(strings.TrimSpace(address)), "requested address", nil)
+}
+
+// IsLocal returns true if the address belongs to one of local domains or their aliases
+func (g *AddressGraph) IsLocal(address string) bool {
+	return g.indexes.forAddress(address) != nil
+}
+
+func (g *AddressGraph) expandAddress(address, reason string, trail []string) (*AddressExpansion, error) {
+	index := g.indexes.forAddress(address)
+	if index == nil {
+		return &AddressExpansion{
+			Node:     AddressNode{Kind: AddressExternal, Address: address, Name: address},
+			Reason:   reason,
+			Delivers: true,
+		}, nil
+	}
+	canonical := g.canonical(address)
+	expansion := &AddressExpansion{
+		Node:   AddressNode{Kind: AddressEmail, DomainId: index.domain.Id, Name: address, Address: canonical},
+		Reason: reason,
+	}
+	for _, visited := range trail {
+		if visited == canonical {
+			expansion.Loop = true
+			return expansion, nil
+		}
+	}
+	if _, domain := splitAddress(address); domain != "" && domain != strings.ToLower(index.domain.Name) {
+		child, err := g.expandAddress(canonical, fmt.Sprintf("%s is an alias of domain %s", domain, index.domain.Name), trail)
+		if err != nil {
+			return nil, err
+		}
+		expansion.Node.Kind = AddressDomainAlias
+		expansion.Node.Address = address
+		expansion.Children = append(expansion.Children, child)
+		return expansion, nil
+	}
+	trail = append(trail, canonical)
+	owners := g.Owners(canonical)
+	if len(owners) == 0 {
+		expansion.Dangling = true
+		return expansion, nil
+	}
+	for _, owner := range owners {
+		child, err := g.expandNode(owner, g.ownerReason(owner, canonical), trail)
+		if err != nil {
+			return nil, err
+		}
+		expansion.Children = append(expansion.Children, child)
+	}
+	return expansion, nil
+}
+
+func (g *AddressGraph) expandNode(node AddressNode, reason string, trail []string) (*AddressExpansion, error) {
+	expansion := &AddressExpansion{Node: node, Reason: reason}
+	switch node.Kind {
+	case AddressUser:
+		index, user, ok := g.indexes.user(node.Id)
+		if !ok {
+			expansion.Dangling = true
+			break
+		}
+		forwarding := user.EmailForwarding
+		expansion.Delivers = forwarding.Mode != UForwardYes || len(forwarding.EmailAddresses) == 0
+		if forwarding.Mode == UForwardNone {
+			break
+		}
+		address := index.primaryAddress(user)
+		for _, target := range forwarding.EmailAddresses {
+			target = index.qualify(target)
+			child, err := g.expandAddress(target, fmt.Sprintf("user %s forwards mail to %s", user.LoginName, target), append(trail[:len(trail):len(trail)], address))
+			if err != nil {
+				return nil, err
+			}
+			expansion.Children = append(expansion.Children, child)
+		}
+	case AddressPublicFolder:
+		_, exists := g.folders[node.Id]
+		expansion.Delivers = exists
+		expansion.Dangling = !exists
+	case AddressAlias:
+		alias := g.aliases[node.Id]
+		if alias.Type == TypePublicFolder {
+			folder := AddressNode{Kind: AddressPublicFolder, Id: alias.DeliverToId, DomainId: alias.DomainId, Name: alias.DeliverTo}
+			child, err := g.expandNode(folder, "alias delivers to public folder", trail)
+			if err != nil {
+				return nil, err
+			}
+			expansion.Children = append(expansion.Children, child)
+			break
+		}
+		target := g.aliasTarget(alias)
+		child, err := g.expandAddress(target, fmt.Sprintf("alias %s forwards to %s", node.Address, target), trail)
+		if err != nil {
+			return nil, err
+		}
+		expansion.Children = append(expansion.Children, child)
+	case AddressGroup:
+		index := g.indexes.forDomain(node.DomainId)
+		if index == nil {
+			break
+		}
+		for _, user := range index.users {
+			if !isGroupMember(user, node.Id) {
+				continue
+			}
+			member := AddressNode{Kind: AddressUser, Id: user.Id, DomainId: node.DomainId, Name: user.LoginName, Address: index.primaryAddress(user)}
+			child, err := g.expandNode(member, "member of group "+node.Name, trail)
+			if err != nil {
+				return nil, err
+			}
+			expansion.Children = append(expansion.Children, child)
+		}
+	case AddressMailingList:
+		members, err := g.mailingListMembers(node.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			if member.Kind != Member {
+				continue
+			}
+			reason := "member of mailing list " + node.Address
+			if !member.HasId {
+				child, err := g.expandAddress(strings.ToLower(member.EmailAddress), reason, trail)
+				if err != nil {
+					return nil, err
+				}
+				expansion.Children = append(expansion.Children, child)
+				continue
+			}
+			index, user, ok := g.indexes.user(member.UserId)
+			if !ok {
+				expansion.Children = append(expansion.Children, &AddressExpansion{
+					Node:     AddressNode{Kind: AddressUser, Id: member.UserId, Name: member.FullName},
+					Reason:   reason,
+					Dangling: true,
+				})
+				continue
+			}
+			userNode := AddressNode{Kind: AddressUser, Id: user.Id, DomainId: index.domain.Id, Name: user.LoginName, Address: index.primaryAddress(user)}
+			child, err := g.expandNode(userNode, reason, trail)
+			if err != nil {
+				return nil, err
+			}
+			expansion.Children = append(expansion.Children, child)
+		}
+	}
+	return expansion, nil
+}
+
+// mailingListMembers returns cached members of the mailing list
+func (g *AddressGraph) mailingListMembers(mlId KId) (UserOrEmailList, error) {
+	if members, ok := g.mlMembers[mlId]; ok {
+		return members, nil
+	}
+	members, _, err := g.s.MailingListsGetMlUserList(SearchQuery{}, mlId)
+	if err != nil {
+		return nil, err
+	}
+	g.mlMembers[mlId] = members
+	return members, nil
+}
+
+// canonical converts the address to lower case and replaces domain alias with the domain name
+func (g *AddressGraph) canonical(address string) string {
+	local, domain := splitAddress(address)
+	if index := g.indexes.forAddress(address); index != nil {
+		domain = strings.ToLower(index.domain.Name)
+	}
+	if domain == "" {
+		return local
+	}
+	return local + "@" + domain
+}
+
+// aliasTarget returns the fully qualified address the alias delivers to
+func (g *AddressGraph) aliasTarget(alias Alias) string {
+	target := strings.ToLower(strings.TrimSpace(alias.DeliverTo))
+	if strings.Contains(target, "@") {
+		return target
+	}
+	if index := g.indexes.forDomain(alias.DomainId); index != nil {
+		return index.qualify(target)
+	}
+	return target
+}
+
+func (g *AddressGraph) aliasNode(alias Alias) AddressNode {
+	node := AddressNode{Kind: AddressAlias, Id: alias.Id, DomainId: alias.DomainId, Name: alias.Name, Address: strings.ToLower(alias.Name)}
+	if index := g.indexes.forDomain(alias.DomainId); index != nil {
+		node.Address = index.qualify(alias.Name)
+	}
+	return node
+}
+
+func (g *AddressGraph) ownerReason(owner AddressNode, address string) string {
+	switch owner.Kind {
+	case AddressUser:
+		if owner.Address == address {
+			return "primary address of user " + owner.Name
+		}
+		return "email address of user " + owner.Name
+	case AddressGroup:
+		return "email address of group " + owner.Name
+	case AddressMailingList:
+		return "address of mailing list " + owner.Name
+	case AddressAlias:
+		if owner.Address != address {
+			return "matches wildcard alias " + owner.Name
+		}
+		return "alias " + owner.Name
+	}
+	return string(owner.Kind)
+}
+
+// addOwner registers the node as owner of the address in the domain; addresses without domain part are qualified
+func (g *AddressGraph) addOwner(index *userIndex, address string, node AddressNode) {
+	address = index.qualify(address)
+	for _, owner := range g.owners[address] {
+		if owner.Kind == node.Kind && owner.Id == node.Id {
+			return
+		}
+	}
+	g.owners[address] = append(g.owners[address], node)
+}
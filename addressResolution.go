@@ -0,0 +1,72 @@
+package connect
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ResolveAddress - Find out which mailboxes receive mail for the address. The expansion walks domain aliases,
+// user addresses, aliases, groups, mailing lists and user forwarding.
+//	address - email address to resolve
+// Return
+//	expansion - tree with the address in the root, final recipients have Delivers set
+func (s *ServerConnection) ResolveAddress(address string) (*AddressExpansion, error) {
+	graph, err := s.NewAddressGraph()
+	if err != nil {
+		return nil, err
+	}
+	return graph.Expand(address)
+}
+
+// WriteTree - Write the expansion as an indented text tree with the reason of each hop
+func (e *AddressExpansion) WriteTree(w io.Writer) error {
+	return e.writeTree(w, 0)
+}
+
+func (e *AddressExpansion) writeTree(w io.Writer, depth int) error {
+	var flags []string
+	if e.Delivers {
+		flags = append(flags, "delivered")
+	}
+	if e.Loop {
+		flags = append(flags, "loop")
+	}
+	if e.Dangling {
+		flags = append(flags, "missing")
+	}
+	line := strings.Repeat("  ", depth) + e.Node.describe()
+	if len(flags) > 0 {
+		line += " [" + strings.Join(flags, ", ") + "]"
+	}
+	if e.Reason != "" {
+		line += " - " + e.Reason
+	}
+	if _, err := fmt.Fprintln(w, line); err != nil {
+		return err
+	}
+	for _, child := range e.Children {
+		if err := child.writeTree(w, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n AddressNode) describe() string {
+	switch n.Kind {
+	case AddressUser:
+		return fmt.Sprintf("user %s <%s>", n.Name, n.Address)
+	case AddressGroup:
+		return fmt.Sprintf("group %s <%s>", n.Name, n.Address)
+	case AddressMailingList:
+		return "mailing list " + n.Address
+	case AddressAlias:
+		return "alias " + n.Address
+	case AddressPublicFolder:
+		return "public folder " + n.Name
+	case AddressExternal:
+		return "external " + n.Address
+	}
+	return n.Name
+}
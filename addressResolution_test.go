@@ -0,0 +1,52 @@
+package connect
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolveAddress(t *testing.T) {
+	handlers := addressGraphHandlers()
+	handlers["Users.get"] = result(map[string]interface{}{"list": UserList{
+		{Id: "u1", LoginName: "alice", UserGroups: UserGroupList{{Id: "g1"}}},
+		{Id: "u2", LoginName: "bob", UserGroups: UserGroupList{{Id: "g1"}},
+			EmailForwarding: EmailForwarding{Mode: UForwardYes, EmailAddresses: UserEmailAddressList{"bob@home.org"}}},
+		{Id: "u3", LoginName: "carol",
+			EmailForwarding: EmailForwarding{Mode: UForwardDeliver, EmailAddresses: UserEmailAddressList{"all"}}},
+	}})
+	conn, _ := newFakeConnection(t, handlers)
+	expansion, err := conn.ResolveAddress("team@example.net")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expansion.Node.Kind != AddressDomainAlias || len(expansion.Children) != 1 {
+		t.Fatalf("domain alias hop expected: %+v", expansion.Node)
+	}
+	expected := StringList{"alice@example.com", "bob@home.org"}
+	if recipients := expansion.Recipients(); !reflect.DeepEqual(recipients, expected) {
+		t.Errorf("unexpected recipients: %v", recipients)
+	}
+	expansion, err = conn.ResolveAddress("carol@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = StringList{"alice@example.com", "bob@home.org", "carol@example.com", "friend@partner.org"}
+	if recipients := expansion.Recipients(); !reflect.DeepEqual(recipients, expected) {
+		t.Errorf("unexpected recipients: %v", recipients)
+	}
+	tree := &bytes.Buffer{}
+	if err = expansion.WriteTree(tree); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"  user carol <carol@example.com> [delivered] - primary address of user carol",
+		"user carol forwards mail to all@example.com",
+		"all@example.com [loop] - user carol forwards mail to all@example.com",
+	} {
+		if !strings.Contains(tree.String(), line) {
+			t.Errorf("tree does not contain %q:\n%s", line, tree)
+		}
+	}
+}
@@ -46,11 +46,16 @@ type Distance struct {
 	Minutes int `json:"minutes"`
 }
 
+// Seconds - Return the distance in seconds
+func (d Distance) Seconds() float64 {
+	return float64(((d.Days*24)+d.Hours)*60+d.Minutes) * 60
+}
+
 type DistanceType string
 
 const (
-	dtNull     DistanceType = "dtNull"
-	dtTimeSpan DistanceType = "dtTimeSpan"
+	DtNull     DistanceType = "dtNull"
+	DtTimeSpan DistanceType = "dtTimeSpan"
 )
 
 type DistanceOrNull struct {
@@ -108,10 +113,10 @@ type NamedConstantList []NamedConstant
 type DirectoryAccessResult string
 
 const (
-	directoryExists            DirectoryAccessResult = "directoryExists"            // Directory exist, read/write allowed
-	directoryDoesNotExist      DirectoryAccessResult = "directoryDoesNotExist"      // Directory does not exist (or unable to create)
-	directoryExistAccessDenied DirectoryAccessResult = "directoryExistAccessDenied" // Directory exist, read or write permission not granted
-	directoryUnaccessible      DirectoryAccessResult = "directoryUnaccessible"      // Unable to connect network device
+	DirectoryExists            DirectoryAccessResult = "directoryExists"            // Directory exist, read/write allowed
+	DirectoryDoesNotExist      DirectoryAccessResult = "directoryDoesNotExist"      // Directory does not exist (or unable to create)
+	DirectoryExistAccessDenied DirectoryAccessResult = "directoryExistAccessDenied" // Directory exist, read or write permission not granted
+	DirectoryUnaccessible      DirectoryAccessResult = "directoryUnaccessible"      // Unable to connect network device
 )
 
 // Information about directory
@@ -143,9 +148,9 @@ const (
 type UpdateCheckerStatus string
 
 const (
-	updNoUpdate   UpdateCheckerStatus = "updNoUpdate"   // Update status: No update
-	updNewVersion UpdateCheckerStatus = "updNewVersion" // Update status: New version
-	updError      UpdateCheckerStatus = "updError"      // Update status: Error
+	UpdNoUpdate   UpdateCheckerStatus = "updNoUpdate"   // Update status: No update
+	UpdNewVersion UpdateCheckerStatus = "updNewVersion" // Update status: New version
+	UpdError      UpdateCheckerStatus = "updError"      // Update status: Error
 )
 
 type UpdateInfo struct {
@@ -175,8 +180,8 @@ type CustomImage struct {
 type NotificationType string
 
 const (
-	notifyOnce  NotificationType = "notifyOnce"
-	notifyEvery NotificationType = "notifyEvery"
+	NotifyOnce  NotificationType = "notifyOnce"
+	NotifyEvery NotificationType = "notifyEvery"
 )
 
 type QuotaNotification struct {
@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 type MiscellaneousOptions struct {
 	LogHostNames              bool `json:"logHostNames"`              // Log hostnames for incoming connections
 	ShowProgramNameAndVersion bool `json:"showProgramNameAndVersion"` // Show program name and version in network communication for non-authenticated users
@@ -163,7 +161,7 @@ func (s *ServerConnection) AdvancedOptionsCheckUpdates() (*UpdateCheckerOptions,
 			Options UpdateCheckerOptions `json:"options"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &options)
+	err = s.unmarshal(data, &options)
 	return &options.Result.Options, err
 }
 
@@ -180,7 +178,7 @@ func (s *ServerConnection) AdvancedOptionsGet() (*AdvancedOptionsSetting, error)
 			Options AdvancedOptionsSetting `json:"options"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &options)
+	err = s.unmarshal(data, &options)
 	return &options.Result.Options, err
 }
 
@@ -197,7 +195,7 @@ func (s *ServerConnection) AdvancedOptionsGetFulltextStatus() (*FulltextRebuildS
 			Info FulltextRebuildStatus `json:"info"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &info)
+	err = s.unmarshal(data, &info)
 	return &info.Result.Info, err
 }
 
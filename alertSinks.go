@@ -0,0 +1,218 @@
+package connect
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DefaultAlertCommandTimeout - Time limit of CommandNotifier
+const DefaultAlertCommandTimeout = 30 * time.Second
+
+// WebhookNotifier - Posts events as JSON object {"events": [...]} to the URL
+type WebhookNotifier struct {
+	Url     string
+	Headers map[string]string // additional headers, e.g. Authorization
+	Client  *http.Client      // http.DefaultClient if nil
+}
+
+// NewWebhookNotifier - Create notifier posting to the URL
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{Url: url}
+}
+
+// Notify - Post events, response other than 2xx is an error
+func (n *WebhookNotifier) Notify(ctx context.Context, events AlertEventList) error {
+	body, err := json.Marshal(struct {
+		Events AlertEventList `json:"events"`
+	}{events})
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest(http.MethodPost, n.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set("Content-Type", "application/json")
+	for name, value := range n.Headers {
+		request.Header.Set(name, value)
+	}
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, response.Body)
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook %s returned %s", n.Url, response.Status)
+	}
+	return nil
+}
+
+// SmtpNotifier - Sends events by email through the relay
+type SmtpNotifier struct {
+	Relay string    // host:port of SMTP relay
+	Auth  smtp.Auth // nil means no authentication
+	From  string
+	To    []string
+}
+
+// NewSmtpNotifier - Create notifier sending email through the relay.
+//	relay - host:port of SMTP relay
+//	from - sender address
+//	to - recipient addresses
+//	auth - authentication, e.g. smtp.PlainAuth; nil means none
+func NewSmtpNotifier(relay, from string, to []string, auth smtp.Auth) *SmtpNotifier {
+	return &SmtpNotifier{Relay: relay, Auth: auth, From: from, To: to}
+}
+
+// Notify - Send one message with all events. STARTTLS is used if the relay offers it.
+// Cancellation of ctx aborts the SMTP session.
+func (n *SmtpNotifier) Notify(ctx context.Context, events AlertEventList) error {
+	err := n.send(ctx, alertMailMessage(n.From, n.To, events))
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+func (n *SmtpNotifier) send(ctx context.Context, message []byte) error {
+	host, _, err := net.SplitHostPort(n.Relay)
+	if err != nil {
+		return err
+	}
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", n.Relay)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	// deadline is set only after ctx is done, so a failure caused by ctx is always reported as ctx.Err()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.SetDeadline(time.Now()) // unblocks pending reads and writes
+		case <-done:
+		}
+	}()
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if n.Auth != nil {
+		if err = client.Auth(n.Auth); err != nil {
+			return err
+		}
+	}
+	if err = client.Mail(n.From); err != nil {
+		return err
+	}
+	for _, to := range n.To {
+		if err = client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	data, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = data.Write(message); err != nil {
+		return err
+	}
+	if err = data.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// alertMailMessage formats events as plain text message
+func alertMailMessage(from string, to []string, events AlertEventList) []byte {
+	raised, cleared := 0, 0
+	for _, event := range events {
+		if event.Transition == AlertRaised {
+			raised++
+		} else {
+			cleared++
+		}
+	}
+	subject := fmt.Sprintf("Kerio Connect alerts: %d raised, %d cleared", raised, cleared)
+	if len(events) > 0 && events[0].Host != "" {
+		subject += " on " + events[0].Host
+	}
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "From: %s\r\n", from)
+	fmt.Fprintf(b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(b, "Subject: %s\r\n", subject)
+	fmt.Fprintf(b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n")
+	for _, event := range events {
+		fmt.Fprintf(b, "%s, since %s\r\n", event, event.Since.Format(time.RFC3339))
+	}
+	return b.Bytes()
+}
+
+// CommandNotifier - Runs local command with events as JSON array on standard input.
+// Environment variables ALERT_RAISED and ALERT_CLEARED hold space separated names of alerts.
+type CommandNotifier struct {
+	Path    string
+	Args    []string
+	Timeout time.Duration // DefaultAlertCommandTimeout if 0
+}
+
+// NewCommandNotifier - Create notifier running the command
+func NewCommandNotifier(path string, args ...string) *CommandNotifier {
+	return &CommandNotifier{Path: path, Args: args}
+}
+
+// Notify - Run the command, non-zero exit status is an error
+func (n *CommandNotifier) Notify(ctx context.Context, events AlertEventList) error {
+	input, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	timeout := n.Timeout
+	if timeout <= 0 {
+		timeout = DefaultAlertCommandTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var raised, cleared []string
+	for _, event := range events {
+		if event.Transition == AlertRaised {
+			raised = append(raised, string(event.Alert.AlertName))
+		} else {
+			cleared = append(cleared, string(event.Alert.AlertName))
+		}
+	}
+	cmd := exec.CommandContext(ctx, n.Path, n.Args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Env = append(os.Environ(), "ALERT_RAISED="+strings.Join(raised, " "), "ALERT_CLEARED="+strings.Join(cleared, " "))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %v: %s", n.Path, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"time"
+)
+
+// DefaultAlertPollInterval - Pause between checks of AlertWatcher
+const DefaultAlertPollInterval = 5 * time.Minute
+
+// AlertTransition - Change of alert state
+type AlertTransition string
+
+const (
+	AlertRaised  AlertTransition = "raised"  // alert appeared or its silence expired
+	AlertCleared AlertTransition = "cleared" // notified alert disappeared
+)
+
+// AlertEvent - Notification about alert transition
+type AlertEvent struct {
+	Transition AlertTransition `json:"transition"`
+	Alert      Alert           `json:"alert"`
+	Severity   TypeAlert       `json:"severity"` // type of the alert after severity overrides
+	Since      time.Time       `json:"since"`    // when the alert was seen first
+	Time       time.Time       `json:"time"`     // when the transition was detected
+	Host       string          `json:"host,omitempty"`
+}
+
+// AlertEventList - Transitions found by one check
+type AlertEventList []AlertEvent
+
+// String - Describe the event in one line, e.g. "StorageSpaceLow raised (Critical): current 5 GB, critical 10 GB"
+func (e AlertEvent) String() string {
+	text := fmt.Sprintf("%s %s (%s)", e.Alert.AlertName, e.Transition, e.Severity)
+	if e.Alert.CurrentValue != "" || e.Alert.CriticalValue != "" {
+		text += fmt.Sprintf(": current %s, critical %s", e.Alert.CurrentValue, e.Alert.CriticalValue)
+	}
+	if e.Host != "" {
+		text = e.Host + ": " + text
+	}
+	return text
+}
+
+// AlertNotifier - Destination of alert events. Events of failed call are kept in the state file and sent again by the next check.
+type AlertNotifier interface {
+	Notify(ctx context.Context, events AlertEventList) error
+}
+
+// AlertSilence - Suppresses notifications of the alert until the time
+type AlertSilence struct {
+	AlertName AlertName `json:"alertName" yaml:"alertName"` // empty name silences all alerts
+	Until     time.Time `json:"until" yaml:"until"`
+	Comment   string    `json:"comment,omitempty" yaml:"comment,omitempty"`
+}
+
+// AlertSilenceList - Silences
+type AlertSilenceList []AlertSilence
+
+// Silenced - Return true if the alert is silenced at the time
+func (l AlertSilenceList) Silenced(name AlertName, t time.Time) bool {
+	for _, silence := range l {
+		if (silence.AlertName == "" || silence.AlertName == name) && t.Before(silence.Until) {
+			return true
+		}
+	}
+	return false
+}
+
+// AlertWatcherOptions - Options of NewAlertWatcher
+type AlertWatcherOptions struct {
+	StateFile    string                  // file with active alerts and undelivered events, so restart neither repeats nor loses notifications; empty means no file
+	PollInterval time.Duration           // pause between checks of Run; DefaultAlertPollInterval if 0
+	Severity     map[AlertName]TypeAlert // overrides of alert types
+	Silences     AlertSilenceList        // suppressed alerts
+	Host         string                  // name of the server in events
+	OnError      func(err error)         // called when a check fails, Run continues
+}
+
+// AlertState - Active alert as stored in the state file
+type AlertState struct {
+	Alert    Alert     `json:"alert"`
+	Severity TypeAlert `json:"severity"`
+	Since    time.Time `json:"since"`
+	Notified bool      `json:"notified"` // raise was sent, so clear will be sent too
+	Cleared  bool      `json:"cleared"`  // alert disappeared while silenced, clear is sent when the silence ends
+}
+
+// alertWatcherState - Content of the state file
+type alertWatcherState struct {
+	Active  map[AlertName]AlertState `json:"active"`
+	Pending []AlertEventList         `json:"pending"` // undelivered events by index of notifier
+}
+
+// AlertWatcher - Polls alerts of the server and notifies their transitions
+type AlertWatcher struct {
+	s         *ServerConnection
+	notifiers []AlertNotifier
+	options   AlertWatcherOptions
+	active    map[AlertName]AlertState
+	pending   []AlertEventList // events not delivered to the notifier with the same index
+}
+
+// NewAlertWatcher - Create watcher notifying all notifiers.
+// Undelivered events of the state file belong to notifiers by index, so the order of notifiers should not change.
+//	notifiers - destinations of events
+//	options - nil means defaults
+// Return
+//	watcher - watcher with state loaded from the state file
+func (s *ServerConnection) NewAlertWatcher(notifiers []AlertNotifier, options *AlertWatcherOptions) (*AlertWatcher, error) {
+	w := &AlertWatcher{s: s, notifiers: notifiers, active: map[AlertName]AlertState{}, pending: make([]AlertEventList, len(notifiers))}
+	if options != nil {
+		w.options = *options
+	}
+	if w.options.PollInterval <= 0 {
+		w.options.PollInterval = DefaultAlertPollInterval
+	}
+	if w.options.StateFile != "" {
+		data, err := ioutil.ReadFile(w.options.StateFile)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if err == nil {
+			state := alertWatcherState{}
+			if err = json.Unmarshal(data, &state); err != nil {
+				return nil, fmt.Errorf("%s: %v", w.options.StateFile, err)
+			}
+			if state.Active != nil {
+				w.active = state.Active
+			}
+			copy(w.pending, state.Pending)
+		}
+	}
+	return w, nil
+}
+
+// Active - Return currently active alerts ordered by name
+func (w *AlertWatcher) Active() []AlertState {
+	states := make([]AlertState, 0, len(w.active))
+	for _, state := range w.active {
+		if !state.Cleared {
+			states = append(states, state)
+		}
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Alert.AlertName < states[j].Alert.AlertName })
+	return states
+}
+
+// Check - Read alerts, find transitions and notify them.
+// Alerts are identified by their names, so repeated alert is notified once.
+// The state is saved after the delivery, events of failed notifiers are saved with it.
+// Return
+//	events - transitions found by this check, silenced alerts excluded
+func (w *AlertWatcher) Check(ctx context.Context) (AlertEventList, error) {
+	alerts, err := w.s.ServerGetAlertList()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	events := w.transitions(alerts, now)
+	failed := 0
+	for i, notifier := range w.notifiers {
+		batch := append(w.pending[i], events...)
+		if len(batch) == 0 {
+			continue
+		}
+		if err = notifier.Notify(ctx, batch); err != nil {
+			w.pending[i] = batch
+			failed++
+			continue
+		}
+		w.pending[i] = nil
+	}
+	if saveErr := w.saveState(); saveErr != nil {
+		return events, saveErr
+	}
+	if failed > 0 {
+		return events, fmt.Errorf("%d of %d alert notifiers failed, last error: %v", failed, len(w.notifiers), err)
+	}
+	return events, nil
+}
+
+// transitions updates active alerts and returns events to notify
+func (w *AlertWatcher) transitions(alerts AlertList, now time.Time) AlertEventList {
+	events := AlertEventList{}
+	seen := map[AlertName]bool{}
+	for _, alert := range alerts {
+		if seen[alert.AlertName] {
+			continue
+		}
+		seen[alert.AlertName] = true
+		state, ok := w.active[alert.AlertName]
+		if !ok {
+			state = AlertState{Since: now}
+		}
+		state.Alert = alert
+		state.Cleared = false
+		state.Severity = alert.AlertType
+		if severity, ok := w.options.Severity[alert.AlertName]; ok {
+			state.Severity = severity
+		}
+		if !state.Notified && !w.options.Silences.Silenced(alert.AlertName, now) {
+			state.Notified = true
+			events = append(events, w.event(AlertRaised, state, now))
+		}
+		w.active[alert.AlertName] = state
+	}
+	for name, state := range w.active {
+		if seen[name] {
+			continue
+		}
+		if state.Notified && w.options.Silences.Silenced(name, now) {
+			state.Cleared = true
+			w.active[name] = state
+			continue
+		}
+		delete(w.active, name)
+		if state.Notified {
+			events = append(events, w.event(AlertCleared, state, now))
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Alert.AlertName < events[j].Alert.AlertName })
+	return events
+}
+
+func (w *AlertWatcher) event(transition AlertTransition, state AlertState, now time.Time) AlertEvent {
+	return AlertEvent{Transition: transition, Alert: state.Alert, Severity: state.Severity, Since: state.Since, Time: now, Host: w.options.Host}
+}
+
+func (w *AlertWatcher) saveState() error {
+	if w.options.StateFile == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(alertWatcherState{Active: w.active, Pending: w.pending}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(w.options.StateFile, data)
+}
+
+// Run - Check alerts periodically until ctx is done
+func (w *AlertWatcher) Run(ctx context.Context) {
+	for {
+		if _, err := w.Check(ctx); err != nil && w.options.OnError != nil {
+			w.options.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.options.PollInterval):
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingNotifier struct {
+	batches []AlertEventList
+	fail    bool
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, events AlertEventList) error {
+	if n.fail {
+		return errors.New("unavailable")
+	}
+	n.batches = append(n.batches, events)
+	return nil
+}
+
+func (n *recordingNotifier) transitions() []string {
+	var list []string
+	for _, batch := range n.batches {
+		for _, event := range batch {
+			list = append(list, string(event.Alert.AlertName)+" "+string(event.Transition)+" "+string(event.Severity))
+		}
+	}
+	return list
+}
+
+func TestAlertWatcher(t *testing.T) {
+	var mu sync.Mutex
+	alerts := AlertList{
+		{AlertName: StorageSpaceLow, AlertType: Warning, CurrentValue: "5 GB", CriticalValue: "10 GB"},
+		{AlertName: StorageSpaceLow, AlertType: Warning},
+		{AlertName: CoredumpFound, AlertType: Info},
+	}
+	conn, _ := newFakeConnection(t, map[string]fakeHandler{
+		"Server.getAlertList": func(json.RawMessage) interface{} {
+			mu.Lock()
+			defer mu.Unlock()
+			return map[string]interface{}{"alerts": alerts}
+		},
+	})
+	dir, err := ioutil.TempDir("", "alerts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	options := &AlertWatcherOptions{
+		StateFile: filepath.Join(dir, "state.json"),
+		Severity:  map[AlertName]TypeAlert{StorageSpaceLow: Critical},
+		Silences:  AlertSilenceList{{AlertName: CoredumpFound, Until: time.Now().Add(time.Hour)}},
+	}
+	notifier := &recordingNotifier{}
+	failing := &recordingNotifier{fail: true}
+	watcher, err := conn.NewAlertWatcher([]AlertNotifier{notifier, failing}, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = watcher.Check(context.Background()); err == nil {
+		t.Error("failed notifier not reported")
+	}
+	if _, err = watcher.Check(context.Background()); err == nil {
+		t.Error("failed notifier not reported")
+	}
+	if got := strings.Join(notifier.transitions(), ","); got != "StorageSpaceLow raised Critical" {
+		t.Errorf("unexpected transitions %q", got)
+	}
+	if len(watcher.Active()) != 2 {
+		t.Errorf("unexpected active alerts %+v", watcher.Active())
+	}
+	// restart does not notify active alerts again, the expired silence raises the silenced one
+	failing.fail = false
+	options.Silences = nil
+	watcher, err = conn.NewAlertWatcher([]AlertNotifier{notifier}, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	alerts = AlertList{{AlertName: CoredumpFound, AlertType: Info}}
+	mu.Unlock()
+	events, err := watcher.Check(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Alert.AlertName != CoredumpFound || events[1].Transition != AlertCleared || events[1].Alert.CurrentValue != "5 GB" {
+		t.Errorf("unexpected events %+v", events)
+	}
+	if events, _ = watcher.Check(context.Background()); len(events) != 0 {
+		t.Errorf("repeated events %+v", events)
+	}
+	if len(notifier.batches) != 2 {
+		t.Errorf("unexpected batches %+v", notifier.batches)
+	}
+}
+
+func TestAlertEventString(t *testing.T) {
+	event := AlertEvent{Transition: AlertRaised, Severity: Critical, Host: "mail",
+		Alert: Alert{AlertName: StorageSpaceLow, CurrentValue: "5 GB", CriticalValue: "10 GB"}}
+	if event.String() != "mail: StorageSpaceLow raised (Critical): current 5 GB, critical 10 GB" {
+		t.Errorf("unexpected text %q", event.String())
+	}
+	message := string(alertMailMessage("kerio@example.com", []string{"ops@example.com"}, AlertEventList{event}))
+	if !strings.Contains(message, "Subject: Kerio Connect alerts: 1 raised, 0 cleared on mail\r\n") || !strings.Contains(message, "critical 10 GB, since") {
+		t.Errorf("unexpected message %q", message)
+	}
+}
+
+func TestAlertNotifiers(t *testing.T) {
+	events := AlertEventList{{Transition: AlertRaised, Severity: Critical, Alert: Alert{AlertName: StorageSpaceLow}}}
+	var posted struct {
+		Events AlertEventList `json:"events"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&posted)
+	}))
+	defer server.Close()
+	webhook := NewWebhookNotifier(server.URL)
+	if err := webhook.Notify(context.Background(), events); err == nil {
+		t.Error("unauthorized webhook succeeded")
+	}
+	webhook.Headers = map[string]string{"Authorization": "Bearer token"}
+	if err := webhook.Notify(context.Background(), events); err != nil {
+		t.Fatal(err)
+	}
+	if len(posted.Events) != 1 || posted.Events[0].Alert.AlertName != StorageSpaceLow {
+		t.Errorf("unexpected posted events %+v", posted)
+	}
+	dir, err := ioutil.TempDir("", "alerts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	output := filepath.Join(dir, "output")
+	command := NewCommandNotifier("sh", "-c", `echo "$ALERT_RAISED" > "$0" && cat >> "$0"`, output)
+	if err = command.Notify(context.Background(), events); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "StorageSpaceLow\n[{") {
+		t.Errorf("unexpected command input %q", data)
+	}
+	if err = NewCommandNotifier("sh", "-c", "echo broken; exit 3").Notify(context.Background(), events); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestAlertWatcherRedelivery(t *testing.T) {
+	var mu sync.Mutex
+	alerts := AlertList{{AlertName: StorageSpaceLow, AlertType: Warning}, {AlertName: CoredumpFound, AlertType: Info}}
+	conn, _ := newFakeConnection(t, map[string]fakeHandler{
+		"Server.getAlertList": func(json.RawMessage) interface{} {
+			mu.Lock()
+			defer mu.Unlock()
+			return map[string]interface{}{"alerts": alerts}
+		},
+	})
+	dir, err := ioutil.TempDir("", "alerts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	options := &AlertWatcherOptions{StateFile: filepath.Join(dir, "state.json")}
+	failing := &recordingNotifier{fail: true}
+	watcher, err := conn.NewAlertWatcher([]AlertNotifier{failing}, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = watcher.Check(context.Background()); err == nil {
+		t.Error("failed notifier not reported")
+	}
+	// events undelivered before restart are sent by the first check after it
+	notifier := &recordingNotifier{}
+	options.Silences = AlertSilenceList{{AlertName: CoredumpFound, Until: time.Now().Add(time.Hour)}}
+	watcher, err = conn.NewAlertWatcher([]AlertNotifier{notifier}, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = watcher.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(notifier.transitions(), ","); got != "CoredumpFound raised Info,StorageSpaceLow raised Warning" {
+		t.Errorf("unexpected transitions %q", got)
+	}
+	// clear during silence is sent when the silence ends
+	mu.Lock()
+	alerts = AlertList{{AlertName: StorageSpaceLow, AlertType: Warning}}
+	mu.Unlock()
+	if events, _ := watcher.Check(context.Background()); len(events) != 0 || len(watcher.Active()) != 1 {
+		t.Errorf("unexpected events %+v, active %+v", events, watcher.Active())
+	}
+	watcher.options.Silences = nil
+	events, err := watcher.Check(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Alert.AlertName != CoredumpFound || events[0].Transition != AlertCleared {
+		t.Errorf("unexpected events %+v", events)
+	}
+}
+
+func TestSmtpNotifierCancel(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		// accept the connection and never send the greeting
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			ioutil.ReadAll(conn)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	notifier := NewSmtpNotifier(listener.Addr().String(), "kerio@example.com", []string{"ops@example.com"}, nil)
+	if err = notifier.Notify(ctx, AlertEventList{{Transition: AlertRaised}}); err != context.DeadlineExceeded {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+package connect
+
+import (
+	"sort"
+	"strings"
+)
+
+// AliasIssueType - Kind of problem found in alias configuration
+type AliasIssueType string
+
+const (
+	AliasLoop      AliasIssueType = "AliasLoop"      // expansion of alias returns to an address which is already being expanded
+	AliasShadowing AliasIssueType = "AliasShadowing" // alias has the same address as a user, group or mailing list and hides it
+	AliasDangling  AliasIssueType = "AliasDangling"  // alias delivers to a local address or public folder which does not exist
+)
+
+// AliasIssue - Problem found by alias analysis
+type AliasIssue struct {
+	Type            AliasIssueType `json:"type"`
+	AliasId         KId            `json:"aliasId"`                   // global identification of alias
+	Address         string         `json:"address"`                   // address of alias
+	Target          string         `json:"target"`                    // alias target, shadowed entity or missing address
+	Path            StringList     `json:"path,omitempty"`            // addresses forming the loop, the first one is repeated at the end
+	ServerExpansion StringList     `json:"serverExpansion,omitempty"` // expansion of the address reported by AliasesCheck
+}
+
+// AliasIssueList - List of alias issues
+type AliasIssueList []AliasIssue
+
+// ExpansionCheck - Comparison of locally computed expansion with the server one
+type ExpansionCheck struct {
+	Address string     `json:"address"` // checked address
+	Local   StringList `json:"local"`   // final recipients computed from the address graph
+	Server  StringList `json:"server"`  // expansion reported by AliasesCheck
+	Missing StringList `json:"missing"` // reported by the server only
+	Extra   StringList `json:"extra"`   // computed locally only
+}
+
+// IsConsistent returns true if the server confirms the local expansion
+func (c *ExpansionCheck) IsConsistent() bool {
+	return len(c.Missing) == 0 && len(c.Extra) == 0
+}
+
+// AnalyzeAliases - Find alias loops, aliases shadowing mailboxes, groups or mailing lists
+// and aliases pointing to addresses or public folders which no longer exist.
+// Return
+//	issues - found problems sorted by type and address
+func (g *AddressGraph) AnalyzeAliases() (AliasIssueList, error) {
+	var issues AliasIssueList
+	loops := map[string]bool{}
+	ids := make(KIdList, 0, len(g.aliases))
+	for id := range g.aliases {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		alias := g.aliases[id]
+		node := g.aliasNode(alias)
+		if alias.Type == TypePublicFolder {
+			if _, ok := g.folders[alias.DeliverToId]; !ok {
+				issues = append(issues, AliasIssue{Type: AliasDangling, AliasId: alias.Id, Address: node.Address, Target: alias.DeliverTo})
+			}
+			continue
+		}
+		if !strings.ContainsAny(alias.Name, "?*") {
+			for _, owner := range g.owners[node.Address] {
+				if owner.Kind != AddressAlias {
+					issues = append(issues, AliasIssue{Type: AliasShadowing, AliasId: alias.Id, Address: node.Address, Target: g.ownerReason(owner, node.Address)})
+				}
+			}
+		}
+		target := g.aliasTarget(alias)
+		expansion, err := g.expandAddress(target, "alias target", StringList{node.Address})
+		if err != nil {
+			return nil, err
+		}
+		if expansion.Dangling {
+			issues = append(issues, AliasIssue{Type: AliasDangling, AliasId: alias.Id, Address: node.Address, Target: target})
+		}
+		for _, loop := range expansion.loops(StringList{node.Address}) {
+			key := loopKey(loop)
+			if loops[key] {
+				continue
+			}
+			loops[key] = true
+			issues = append(issues, AliasIssue{Type: AliasLoop, AliasId: alias.Id, Address: node.Address, Target: target, Path: loop})
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Type != issues[j].Type {
+			return issues[i].Type < issues[j].Type
+		}
+		return issues[i].Address < issues[j].Address
+	})
+	return issues, nil
+}
+
+// ConfirmAliasIssues - Fill ServerExpansion of each issue with the result of AliasesCheck
+func (g *AddressGraph) ConfirmAliasIssues(issues AliasIssueList) error {
+	for i := range issues {
+		expansion, err := g.s.AliasesCheck(issues[i].Address)
+		if err != nil {
+			return err
+		}
+		issues[i].ServerExpansion = expansion
+	}
+	return nil
+}
+
+// CheckExpansion - Compare final recipients of the address with the expansion reported by AliasesCheck.
+//	address - email address to check
+// Return
+//	check - local and server expansions and their differences
+func (g *AddressGraph) CheckExpansion(address string) (*ExpansionCheck, error) {
+	expansion, err := g.Expand(address)
+	if err != nil {
+		return nil, err
+	}
+	server, err := g.s.AliasesCheck(address)
+	if err != nil {
+		return nil, err
+	}
+	check := &ExpansionCheck{Address: address, Local: expansion.Recipients(), Server: server}
+	local := map[string]bool{}
+	for _, recipient := range check.Local {
+		local[strings.ToLower(recipient)] = true
+	}
+	remote := map[string]bool{}
+	for _, recipient := range server {
+		recipient = strings.ToLower(strings.TrimSpace(recipient))
+		remote[recipient] = true
+		if !local[recipient] {
+			check.Missing = append(check.Missing, recipient)
+		}
+	}
+	for _, recipient := range check.Local {
+		if !remote[strings.ToLower(recipient)] {
+			check.Extra = append(check.Extra, recipient)
+		}
+	}
+	sort.Strings(check.Missing)
+	return check, nil
+}
+
+// loops returns address paths which end in a loop; trail contains addresses of all nodes above this node
+func (e *AddressExpansion) loops(trail StringList) []StringList {
+	if e.Loop {
+		for i, address := range trail {
+			if address == e.Node.Address {
+				loop := append(StringList{}, trail[i:]...)
+				return []StringList{append(loop, e.Node.Address)}
+			}
+		}
+		return []StringList{append(append(StringList{}, trail...), e.Node.Address)}
+	}
+	// domain alias addresses are rewritten to the canonical address of the child
+	if e.Node.Kind != AddressDomainAlias && e.Node.Address != "" && (len(trail) == 0 || trail[len(trail)-1] != e.Node.Address) {
+		trail = append(trail[:len(trail):len(trail)], e.Node.Address)
+	}
+	var result []StringList
+	for _, child := range e.Children {
+		result = append(result, child.loops(trail)...)
+	}
+	return result
+}
+
+// loopKey identifies the loop regardless of the address where it was entered
+func loopKey(loop StringList) string {
+	members := append(StringList{}, loop[:len(loop)-1]...)
+	sort.Strings(members)
+	return strings.Join(members, " ")
+}
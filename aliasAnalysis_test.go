@@ -0,0 +1,111 @@
+package connect
+
+import (
+	"reflect"
+	"testing"
+)
+
+func addressGraphHandlers() map[string]fakeHandler {
+	return map[string]fakeHandler{
+		"Domains.get": result(map[string]interface{}{"list": DomainList{{Id: "d1", Name: "example.com", AliasList: StringList{"example.net"}}}}),
+		"Users.get": result(map[string]interface{}{"list": UserList{
+			{Id: "u1", LoginName: "alice", UserGroups: UserGroupList{{Id: "g1"}}},
+			{Id: "u2", LoginName: "bob", EmailAddresses: UserEmailAddressList{"robert"}, UserGroups: UserGroupList{{Id: "g1"}}},
+			{Id: "u3", LoginName: "carol"},
+		}}),
+		"Groups.get":       result(map[string]interface{}{"list": GroupList{{Id: "g1", DomainId: "d1", Name: "team", EmailAddresses: StringList{"team"}}}}),
+		"MailingLists.get": result(map[string]interface{}{"list": MlList{{Id: "ml1", DomainId: "d1", Name: "news"}}}),
+		"MailingLists.getMlUserList": result(map[string]interface{}{"list": UserOrEmailList{
+			{HasId: true, UserId: "u3", Kind: Member},
+			{EmailAddress: "friend@partner.org", Kind: Member},
+			{HasId: true, UserId: "u1", Kind: Moderator},
+		}}),
+		"Aliases.get": result(map[string]interface{}{"list": AliasList{
+			{Id: "a1", DomainId: "d1", Name: "ping", DeliverTo: "pong", Type: TypeEmailAddress},
+			{Id: "a2", DomainId: "d1", Name: "pong", DeliverTo: "ping@example.net", Type: TypeEmailAddress},
+			{Id: "a3", DomainId: "d1", Name: "robert", DeliverTo: "carol@example.com", Type: TypeEmailAddress},
+			{Id: "a4", DomainId: "d1", Name: "old", DeliverTo: "ghost", Type: TypeEmailAddress},
+			{Id: "a5", DomainId: "d1", Name: "docs", DeliverTo: "Documents", DeliverToId: "f9", Type: TypePublicFolder},
+			{Id: "a6", DomainId: "d1", Name: "all", DeliverTo: "team", Type: TypeEmailAddress},
+			{Id: "a7", DomainId: "d1", Name: "all", DeliverTo: "news", Type: TypeEmailAddress},
+		}}),
+		"Aliases.getMailPublicFolderList": result(map[string]interface{}{"publicFolderList": PublicFolderList{{Id: "f1", Name: "Support"}}}),
+		"Aliases.check":                   result(map[string]interface{}{"result": StringList{"alice@example.com", "bob@example.com", "carol@example.com"}}),
+	}
+}
+
+func TestAnalyzeAliases(t *testing.T) {
+	conn, _ := newFakeConnection(t, addressGraphHandlers())
+	graph, err := conn.NewAddressGraph()
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues, err := graph.AnalyzeAliases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := map[AliasIssueType]StringList{}
+	for _, issue := range issues {
+		types[issue.Type] = append(types[issue.Type], issue.Address)
+	}
+	expected := map[AliasIssueType]StringList{
+		AliasDangling:  {"docs@example.com", "old@example.com"},
+		AliasLoop:      {"ping@example.com"},
+		AliasShadowing: {"robert@example.com"},
+	}
+	if !reflect.DeepEqual(types, expected) {
+		t.Errorf("unexpected issues: %+v", issues)
+	}
+	for _, issue := range issues {
+		if issue.Type == AliasLoop && !reflect.DeepEqual(issue.Path, StringList{"ping@example.com", "pong@example.com", "ping@example.com"}) {
+			t.Errorf("unexpected loop path: %v", issue.Path)
+		}
+	}
+}
+
+func TestAnalyzeAliasesGroupLoop(t *testing.T) {
+	handlers := addressGraphHandlers()
+	handlers["Users.get"] = result(map[string]interface{}{"list": UserList{
+		{Id: "u1", LoginName: "alice", UserGroups: UserGroupList{{Id: "g1"}},
+			EmailForwarding: EmailForwarding{Mode: UForwardYes, EmailAddresses: UserEmailAddressList{"bob"}}},
+		{Id: "u2", LoginName: "bob", EmailForwarding: EmailForwarding{Mode: UForwardYes, EmailAddresses: UserEmailAddressList{"alice"}}},
+	}})
+	handlers["Aliases.get"] = result(map[string]interface{}{"list": AliasList{
+		{Id: "a1", DomainId: "d1", Name: "all", DeliverTo: "team", Type: TypeEmailAddress},
+	}})
+	conn, _ := newFakeConnection(t, handlers)
+	graph, err := conn.NewAddressGraph()
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues, err := graph.AnalyzeAliases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].Type != AliasLoop || !reflect.DeepEqual(issues[0].Path, StringList{"alice@example.com", "bob@example.com", "alice@example.com"}) {
+		t.Errorf("unexpected issues: %+v", issues)
+	}
+}
+
+func TestAddressGraphExpand(t *testing.T) {
+	conn, _ := newFakeConnection(t, addressGraphHandlers())
+	graph, err := conn.NewAddressGraph()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expansion, err := graph.Expand("ALL@example.net")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := StringList{"alice@example.com", "bob@example.com", "carol@example.com", "friend@partner.org"}
+	if recipients := expansion.Recipients(); !reflect.DeepEqual(recipients, expected) {
+		t.Errorf("unexpected recipients: %v", recipients)
+	}
+	check, err := graph.CheckExpansion("all@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if check.IsConsistent() || !reflect.DeepEqual(check.Extra, StringList{"friend@partner.org"}) {
+		t.Errorf("unexpected check: %+v", check)
+	}
+}
@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 // AliasType - Alias type definition
 type AliasType string
 
@@ -68,7 +66,7 @@ func (s *ServerConnection) AliasesCheck(checkString string) (StringList, error)
 			Result StringList `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &result)
+	err = s.unmarshal(data, &result)
 	return result.Result.Result, err
 }
 
@@ -91,7 +89,7 @@ func (s *ServerConnection) AliasesCreate(aliases AliasList) (ErrorList, CreateRe
 			Result CreateResultList `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, errors.Result.Result, err
 }
 
@@ -116,7 +114,7 @@ func (s *ServerConnection) AliasesGet(query SearchQuery, domainId KId) (AliasLis
 			TotalItems int       `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -137,7 +135,7 @@ func (s *ServerConnection) AliasesGetMailPublicFolderList(domainId KId) (PublicF
 			PublicFolderList PublicFolderList `json:"publicFolderList"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &publicFolderList)
+	err = s.unmarshal(data, &publicFolderList)
 	return publicFolderList.Result.PublicFolderList, err
 }
 
@@ -163,7 +161,7 @@ func (s *ServerConnection) AliasesGetTargetList(query SearchQuery, domainId KId)
 			TotalItems int             `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -183,7 +181,7 @@ func (s *ServerConnection) AliasesRemove(aliasIds KIdList) (ErrorList, error) {
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -206,6 +204,6 @@ func (s *ServerConnection) AliasesSet(aliasIds KIdList, pattern Alias) (ErrorLis
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
@@ -1,13 +1,11 @@
 package connect
 
-import "encoding/json"
-
 type DayWeekMonthPeriod string
 
 const (
-	periodDay   DayWeekMonthPeriod = "periodDay"
-	periodWeek  DayWeekMonthPeriod = "periodWeek"
-	periodMonth DayWeekMonthPeriod = "periodMonth"
+	PeriodDay   DayWeekMonthPeriod = "periodDay"
+	PeriodWeek  DayWeekMonthPeriod = "periodWeek"
+	PeriodMonth DayWeekMonthPeriod = "periodMonth"
 )
 
 type ArchiveOptions struct {
@@ -42,7 +40,7 @@ func (s *ServerConnection) ArchiveGet() (*ArchiveOptions, error) {
 			Options ArchiveOptions `json:"options"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &options)
+	err = s.unmarshal(data, &options)
 	return &options.Result.Options, err
 }
 
@@ -67,7 +65,7 @@ func (s *ServerConnection) ArchiveGetXmppArchiveFiles() (DownloadList, error) {
 			FileList DownloadList `json:"fileList"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &fileList)
+	err = s.unmarshal(data, &fileList)
 	return fileList.Result.FileList, err
 }
 
@@ -86,6 +84,6 @@ func (s *ServerConnection) ArchiveGetImArchiveFile(fromDate Date, toDate Date) (
 			FileDownload Download `json:"fileDownload"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &fileDownload)
+	err = s.unmarshal(data, &fileDownload)
 	return &fileDownload.Result.FileDownload, err
 }
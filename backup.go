@@ -1,21 +1,19 @@
 package connect
 
-import "encoding/json"
-
 type LastBackupStatus string
 
 const (
-	backupStatusNone       LastBackupStatus = "backupStatusNone"
-	backupStatusSuccessful LastBackupStatus = "backupStatusSuccessful"
-	backupStatusFailed     LastBackupStatus = "backupStatusFailed"
+	BackupStatusNone       LastBackupStatus = "backupStatusNone"
+	BackupStatusSuccessful LastBackupStatus = "backupStatusSuccessful"
+	BackupStatusFailed     LastBackupStatus = "backupStatusFailed"
 )
 
 type BackupType string
 
 const (
-	backupTypeFull         BackupType = "backupTypeFull"
-	backupTypeDifferential BackupType = "backupTypeDifferential"
-	backupTypeMirror       BackupType = "backupTypeMirror"
+	BackupTypeFull         BackupType = "backupTypeFull"
+	BackupTypeDifferential BackupType = "backupTypeDifferential"
+	BackupTypeMirror       BackupType = "backupTypeMirror"
 )
 
 // BackupInfo - [READ-ONLY]
@@ -74,7 +72,7 @@ func (s *ServerConnection) BackupGet() (*BackupOptions, error) {
 			Options BackupOptions `json:"options"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &options)
+	err = s.unmarshal(data, &options)
 	return &options.Result.Options, err
 }
 
@@ -96,7 +94,7 @@ func (s *ServerConnection) BackupGetScheduleList(query SearchQuery) (BackupSched
 			ScheduleList BackupScheduleList `json:"scheduleList"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &scheduleList)
+	err = s.unmarshal(data, &scheduleList)
 	return scheduleList.Result.ScheduleList, err
 }
 
@@ -113,7 +111,7 @@ func (s *ServerConnection) BackupGetStatus() (*BackupStatus, error) {
 			Status BackupStatus `json:"status"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &status)
+	err = s.unmarshal(data, &status)
 	return &status.Result.Status, err
 }
 
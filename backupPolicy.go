@@ -0,0 +1,122 @@
+package connect
+
+import (
+	"fmt"
+	"time"
+)
+
+// BackupFindingCode - Kind of backup policy violation
+type BackupFindingCode string
+
+const (
+	BackupDisabled          BackupFindingCode = "BackupDisabled"          // backup is disabled
+	BackupNotScheduled      BackupFindingCode = "BackupNotScheduled"      // no enabled schedule of full backup
+	BackupFullMissing       BackupFindingCode = "BackupFullMissing"       // no successful full backup within MaxFullAge
+	BackupDifferentialStale BackupFindingCode = "BackupDifferentialStale" // no differential backup within MaxDifferentialAge
+	BackupLastFailed        BackupFindingCode = "BackupLastFailed"        // the last backup failed
+	BackupRotationTooLow    BackupFindingCode = "BackupRotationTooLow"    // rotation limit keeps backups for shorter time than MinRetention
+	BackupNoNotification    BackupFindingCode = "BackupNoNotification"    // nobody is notified about backup problems
+)
+
+// BackupPolicy - Expectations on backup configuration and results
+type BackupPolicy struct {
+	MaxFullAge          time.Duration `json:"maxFullAge" yaml:"maxFullAge"`                   // age of the last successful full backup; 7 days if 0
+	MaxDifferentialAge  time.Duration `json:"maxDifferentialAge" yaml:"maxDifferentialAge"`   // age of the last differential backup; 0 disables the check
+	MinRetention        time.Duration `json:"minRetention" yaml:"minRetention"`               // time the rotation limit has to cover; 14 days if 0
+	RequireNotification bool          `json:"requireNotification" yaml:"requireNotification"` // notification email address has to be set
+}
+
+// BackupFinding - Violation of the backup policy
+type BackupFinding struct {
+	Code        BackupFindingCode `json:"code"`
+	Severity    TypeAlert         `json:"severity"`
+	Description string            `json:"description"`
+}
+
+// BackupFindingList - Violations of the backup policy
+type BackupFindingList []BackupFinding
+
+// EvaluateBackupPolicy - Compare backup options and schedules with the policy.
+//	options - backup options from BackupGet
+//	schedules - schedules from BackupGetScheduleList
+//	policy - expectations; nil means defaults
+//	now - time the ages are measured to
+// Return
+//	findings - violations of the policy
+func EvaluateBackupPolicy(options *BackupOptions, schedules BackupScheduleList, policy *BackupPolicy, now time.Time) BackupFindingList {
+	var p BackupPolicy
+	if policy != nil {
+		p = *policy
+	}
+	if p.MaxFullAge <= 0 {
+		p.MaxFullAge = 7 * 24 * time.Hour
+	}
+	if p.MinRetention <= 0 {
+		p.MinRetention = 14 * 24 * time.Hour
+	}
+	findings := BackupFindingList{}
+	add := func(code BackupFindingCode, severity TypeAlert, format string, args ...interface{}) {
+		findings = append(findings, BackupFinding{Code: code, Severity: severity, Description: fmt.Sprintf(format, args...)})
+	}
+	if !options.IsEnabled {
+		add(BackupDisabled, Critical, "backup is disabled")
+	}
+	fullsPerWeek := 0
+	for _, schedule := range schedules {
+		if schedule.IsEnabled && schedule.Type == BackupTypeFull {
+			fullsPerWeek++
+		}
+	}
+	if fullsPerWeek == 0 {
+		add(BackupNotScheduled, Critical, "no enabled schedule of full backup")
+	} else if retention := time.Duration(options.RotationLimit) * 7 * 24 * time.Hour / time.Duration(fullsPerWeek); retention < p.MinRetention {
+		add(BackupRotationTooLow, Warning, "rotation limit %d with %d full backups a week keeps backups for %s, policy requires %s",
+			options.RotationLimit, fullsPerWeek, formatAge(retention.Truncate(time.Hour)), formatAge(p.MinRetention))
+	}
+	if age, ok := backupAge(options.Status.LastFull, now); !ok {
+		add(BackupFullMissing, Critical, "no successful full backup found")
+	} else if age > p.MaxFullAge {
+		add(BackupFullMissing, Critical, "no successful full backup within %s, the last one is %s old",
+			formatAge(p.MaxFullAge), formatAge(age.Truncate(time.Hour)))
+	}
+	if p.MaxDifferentialAge > 0 {
+		if age, ok := backupAge(options.Status.LastDifferential, now); !ok || age > p.MaxDifferentialAge {
+			add(BackupDifferentialStale, Warning, "no differential backup within %s", formatAge(p.MaxDifferentialAge))
+		}
+	}
+	if options.Status.LastBackupStatus == BackupStatusFailed {
+		add(BackupLastFailed, Critical, "the last backup failed")
+	}
+	if p.RequireNotification && options.NotificationEmailAddress == "" {
+		add(BackupNoNotification, Warning, "notification email address is not set")
+	}
+	return findings
+}
+
+// backupAge returns age of created backup
+func backupAge(info BackupInfo, now time.Time) (time.Duration, bool) {
+	if !info.IsCreated {
+		return 0, false
+	}
+	created, err := info.Created.Time()
+	if err != nil {
+		return 0, false
+	}
+	return now.Sub(created), true
+}
+
+// CheckBackupPolicy - Read backup options and schedules and compare them with the policy.
+//	policy - expectations; nil means defaults
+// Return
+//	findings - violations of the policy
+func (s *ServerConnection) CheckBackupPolicy(policy *BackupPolicy) (BackupFindingList, error) {
+	options, err := s.BackupGet()
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := s.BackupGetScheduleList(SearchQuery{})
+	if err != nil {
+		return nil, err
+	}
+	return EvaluateBackupPolicy(options, schedules, policy, time.Now()), nil
+}
@@ -0,0 +1,130 @@
+package connect
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultBackupPollInterval - Pause between reads of backup status
+const DefaultBackupPollInterval = 5 * time.Second
+
+// DefaultBackupStartTimeout - Time the server has to begin the started backup
+const DefaultBackupStartTimeout = 2 * time.Minute
+
+// BackupProgress - State of running backup
+type BackupProgress struct {
+	Time      time.Time     `json:"time"`
+	Percents  int           `json:"percents"`  // from 0 to 100
+	Elapsed   time.Duration `json:"elapsed"`   // time since backup started as reported by the server
+	Remaining time.Duration `json:"remaining"` // approximated time to the end
+}
+
+// BackupRunOptions - Options of RunBackup
+type BackupRunOptions struct {
+	PollInterval time.Duration                 // pause between reads of status; DefaultBackupPollInterval if 0
+	StartTimeout time.Duration                 // time the server has to begin the backup; DefaultBackupStartTimeout if 0
+	Timeout      time.Duration                 // limit of the whole backup; 0 means no limit besides ctx
+	OnProgress   func(progress BackupProgress) // called after each read of status of running backup
+}
+
+// BackupResult - Finished backup
+type BackupResult struct {
+	Type     BackupType       `json:"type"`
+	Status   LastBackupStatus `json:"status"`
+	Started  time.Time        `json:"started"`
+	Finished time.Time        `json:"finished"`
+	Duration time.Duration    `json:"duration"`
+	Size     int64            `json:"size"` // compressed size in bytes
+	Info     BackupInfo       `json:"info"` // information about the backup as reported by the server
+}
+
+// Successful - Return true if the server reports the backup as successful
+func (r *BackupResult) Successful() bool {
+	return r.Status == BackupStatusSuccessful
+}
+
+// backupInfo returns information about the last backup of the type
+func (b *BackupStatus) backupInfo(backupType BackupType) BackupInfo {
+	switch backupType {
+	case BackupTypeDifferential:
+		return b.LastDifferential
+	case BackupTypeMirror:
+		return b.LastMirror
+	}
+	return b.LastFull
+}
+
+// RunBackup - Start backup and wait for its completion.
+// The server can not stop a running backup, so the backup continues when ctx is done or timeout expires.
+//	ctx - cancels waiting
+//	backupType - backup type
+//	options - nil means defaults
+// Return
+//	result - finished backup; returned with error also if the backup failed
+func (s *ServerConnection) RunBackup(ctx context.Context, backupType BackupType, options *BackupRunOptions) (*BackupResult, error) {
+	var o BackupRunOptions
+	if options != nil {
+		o = *options
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = DefaultBackupPollInterval
+	}
+	if o.StartTimeout <= 0 {
+		o.StartTimeout = DefaultBackupStartTimeout
+	}
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+	before, err := s.BackupGetStatus()
+	if err != nil {
+		return nil, err
+	}
+	if before.BackupInProgress {
+		return nil, fmt.Errorf("another backup is in progress")
+	}
+	previous := before.backupInfo(backupType)
+	if err = s.BackupStart(backupType); err != nil {
+		return nil, err
+	}
+	result := &BackupResult{Type: backupType, Started: time.Now()}
+	running := false
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("backup did not finish: %v", ctx.Err())
+		case <-time.After(o.PollInterval):
+		}
+		status, err := s.BackupGetStatus()
+		if err != nil {
+			return nil, err
+		}
+		info := status.backupInfo(backupType)
+		if status.BackupInProgress {
+			running = true
+			if o.OnProgress != nil {
+				o.OnProgress(BackupProgress{Time: time.Now(), Percents: status.Percents,
+					Elapsed: time.Duration(status.ElapsedTime.Seconds()) * time.Second, Remaining: time.Duration(status.RemainingTime.Seconds()) * time.Second})
+			}
+			continue
+		}
+		// a short backup may finish between two reads of status
+		if !running && info == previous && status.LastBackupStatus == before.LastBackupStatus {
+			if time.Since(result.Started) > o.StartTimeout {
+				return nil, fmt.Errorf("backup did not start within %s", o.StartTimeout)
+			}
+			continue
+		}
+		result.Finished = time.Now()
+		result.Duration = result.Finished.Sub(result.Started)
+		result.Status = status.LastBackupStatus
+		result.Info = info
+		result.Size = info.Size.ByteCount()
+		if !result.Successful() {
+			return result, fmt.Errorf("backup finished with status %s", result.Status)
+		}
+		return result, nil
+	}
+}
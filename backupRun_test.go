@@ -0,0 +1,98 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunBackup(t *testing.T) {
+	var mu sync.Mutex
+	statuses := []BackupStatus{
+		{LastBackupStatus: BackupStatusSuccessful, LastFull: BackupInfo{IsCreated: true, Created: "2021-01-20 02:00:00"}},
+		{BackupInProgress: true, Percents: 10, RemainingTime: Distance{Minutes: 9}},
+		{BackupInProgress: true, Percents: 60, RemainingTime: Distance{Minutes: 4}},
+		{LastBackupStatus: BackupStatusSuccessful, LastFull: BackupInfo{IsCreated: true, Created: "2021-01-28 02:00:00",
+			Size: ByteValueWithUnits{Value: 3, Units: GigaBytes}}},
+	}
+	conn, fake := newFakeConnection(t, map[string]fakeHandler{
+		"Backup.start": result(map[string]interface{}{}),
+		"Backup.getStatus": func(json.RawMessage) interface{} {
+			mu.Lock()
+			defer mu.Unlock()
+			status := statuses[0]
+			if len(statuses) > 1 {
+				statuses = statuses[1:]
+			}
+			return map[string]interface{}{"status": status}
+		},
+	})
+	var progress []int
+	backup, err := conn.RunBackup(context.Background(), BackupTypeFull, &BackupRunOptions{
+		PollInterval: time.Millisecond,
+		OnProgress:   func(p BackupProgress) { progress = append(progress, p.Percents) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fake.called("Backup.start") != 1 || len(progress) != 2 || progress[1] != 60 {
+		t.Errorf("unexpected progress %v", progress)
+	}
+	if !backup.Successful() || backup.Size != 3<<30 || backup.Info.Created != "2021-01-28 02:00:00" || backup.Duration <= 0 {
+		t.Errorf("unexpected result %+v", backup)
+	}
+}
+
+func TestRunBackupTimeout(t *testing.T) {
+	conn, _ := newFakeConnection(t, map[string]fakeHandler{
+		"Backup.start":     result(map[string]interface{}{}),
+		"Backup.getStatus": result(map[string]interface{}{"status": BackupStatus{LastBackupStatus: BackupStatusNone}}),
+	})
+	_, err := conn.RunBackup(context.Background(), BackupTypeDifferential, &BackupRunOptions{PollInterval: time.Millisecond, StartTimeout: 10 * time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "did not start") {
+		t.Errorf("unexpected error %v", err)
+	}
+	_, err = conn.RunBackup(context.Background(), BackupTypeDifferential, &BackupRunOptions{PollInterval: time.Millisecond, Timeout: 5 * time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "did not finish") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestEvaluateBackupPolicy(t *testing.T) {
+	now := time.Date(2021, 1, 28, 12, 0, 0, 0, time.UTC)
+	options := &BackupOptions{IsEnabled: true, RotationLimit: 2, Status: BackupStatus{
+		LastBackupStatus: BackupStatusFailed,
+		LastFull:         BackupInfo{IsCreated: true, Created: "2021-01-10 02:00:00"},
+	}}
+	schedules := BackupScheduleList{
+		{IsEnabled: true, Type: BackupTypeFull, DayType: Sunday},
+		{IsEnabled: true, Type: BackupTypeFull, DayType: Wednesday},
+		{IsEnabled: false, Type: BackupTypeFull, DayType: Friday},
+		{IsEnabled: true, Type: BackupTypeDifferential, DayType: Monday},
+	}
+	findings := EvaluateBackupPolicy(options, schedules, &BackupPolicy{MaxDifferentialAge: 48 * time.Hour, RequireNotification: true}, now)
+	var codes []string
+	for _, finding := range findings {
+		codes = append(codes, string(finding.Code))
+	}
+	expected := "BackupRotationTooLow,BackupFullMissing,BackupDifferentialStale,BackupLastFailed,BackupNoNotification"
+	if strings.Join(codes, ",") != expected {
+		t.Fatalf("unexpected findings %+v", findings)
+	}
+	if findings[0].Description != "rotation limit 2 with 2 full backups a week keeps backups for 7d, policy requires 14d" {
+		t.Errorf("unexpected description %q", findings[0].Description)
+	}
+	options = &BackupOptions{IsEnabled: true, RotationLimit: 4, NotificationEmailAddress: "ops@example.com", Status: BackupStatus{
+		LastBackupStatus: BackupStatusSuccessful,
+		LastFull:         BackupInfo{IsCreated: true, Created: "2021-01-25T02:00:00Z"},
+	}}
+	if findings = EvaluateBackupPolicy(options, schedules[:1], nil, now); len(findings) != 0 {
+		t.Errorf("unexpected findings %+v", findings)
+	}
+	if findings = EvaluateBackupPolicy(&BackupOptions{}, nil, nil, now); len(findings) != 3 || findings[0].Code != BackupDisabled {
+		t.Errorf("unexpected findings %+v", findings)
+	}
+}
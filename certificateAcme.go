@@ -0,0 +1,413 @@
+package connect
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultAcmePollInterval = 2 * time.Second // pause between reads of order and authorization status
+	DefaultAcmeTimeout      = 5 * time.Minute // time to wait for validation and issuance
+)
+
+// AcmeChallengeSolver - Makes the key authorization of http-01 challenge available to the ACME server
+type AcmeChallengeSolver interface {
+	Present(ctx context.Context, domain, token, keyAuthorization string) error
+	CleanUp(ctx context.Context, domain, token string) error
+}
+
+// HttpChallengeSolver - Serves http-01 challenges on /.well-known/acme-challenge/, run it on port 80 of the validated host
+type HttpChallengeSolver struct {
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+// NewHttpChallengeSolver - Create solver to be used as http.Handler
+func NewHttpChallengeSolver() *HttpChallengeSolver {
+	return &HttpChallengeSolver{tokens: map[string]string{}}
+}
+
+func (h *HttpChallengeSolver) Present(_ context.Context, _, token, keyAuthorization string) error {
+	h.mu.Lock()
+	h.tokens[token] = keyAuthorization
+	h.mu.Unlock()
+	return nil
+}
+
+func (h *HttpChallengeSolver) CleanUp(_ context.Context, _, token string) error {
+	h.mu.Lock()
+	delete(h.tokens, token)
+	h.mu.Unlock()
+	return nil
+}
+
+func (h *HttpChallengeSolver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	keyAuthorization, ok := h.tokens[strings.TrimPrefix(r.URL.Path, "/.well-known/acme-challenge/")]
+	h.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	_, _ = w.Write([]byte(keyAuthorization))
+}
+
+// AcmeOptions - Options of ObtainAcmeCertificate
+type AcmeOptions struct {
+	DirectoryUrl string              // directory of the ACME server, e.g. https://localhost:14000/dir for Pebble
+	Contact      StringList          // contacts of the account, e.g. mailto:admin@example.com
+	AccountKey   *ecdsa.PrivateKey   // P-256 key of the account; new account is created if nil
+	Solver       AcmeChallengeSolver // solver of http-01 challenges
+	Client       *http.Client        // client trusting the ACME server; http.DefaultClient if nil
+	PollInterval time.Duration       // DefaultAcmePollInterval if 0
+	Timeout      time.Duration       // DefaultAcmeTimeout if 0
+}
+
+// acmeClient - Minimal RFC 8555 client signing requests by ES256
+type acmeClient struct {
+	options   AcmeOptions
+	directory struct {
+		NewNonce   string `json:"newNonce"`
+		NewAccount string `json:"newAccount"`
+		NewOrder   string `json:"newOrder"`
+	}
+	nonce string
+	kid   string
+}
+
+type acmeOrder struct {
+	Status         string     `json:"status"`
+	Authorizations StringList `json:"authorizations"`
+	Finalize       string     `json:"finalize"`
+	Certificate    string     `json:"certificate"`
+}
+
+type acmeAuthorization struct {
+	Status     string `json:"status"`
+	Identifier struct {
+		Value string `json:"value"`
+	} `json:"identifier"`
+	Challenges []struct {
+		Type   string `json:"type"`
+		Url    string `json:"url"`
+		Token  string `json:"token"`
+		Status string `json:"status"`
+	} `json:"challenges"`
+}
+
+// ObtainAcmeCertificate - Order certificate for the domains from ACME server using http-01 challenges.
+//	domains - DNS names of the certificate, the first one is the subject
+//	options - ACME server and challenge solver
+// Return
+//	certPEM - the issued certificate
+//	keyPEM - new ECDSA private key of the certificate
+//	chain - intermediate certificates returned by the server
+func ObtainAcmeCertificate(ctx context.Context, domains StringList, options *AcmeOptions) ([]byte, []byte, []byte, error) {
+	if options == nil || options.DirectoryUrl == "" || options.Solver == nil {
+		return nil, nil, nil, fmt.Errorf("acme: directory URL and challenge solver are required")
+	}
+	if len(domains) == 0 {
+		return nil, nil, nil, fmt.Errorf("acme: no domain given")
+	}
+	c := &acmeClient{options: *options}
+	if c.options.Client == nil {
+		c.options.Client = http.DefaultClient
+	}
+	if c.options.PollInterval <= 0 {
+		c.options.PollInterval = DefaultAcmePollInterval
+	}
+	if c.options.Timeout <= 0 {
+		c.options.Timeout = DefaultAcmeTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.options.Timeout)
+	defer cancel()
+	if c.options.AccountKey == nil {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		c.options.AccountKey = key
+	}
+	if err := c.get(ctx, c.options.DirectoryUrl, &c.directory); err != nil {
+		return nil, nil, nil, err
+	}
+	account := map[string]interface{}{"termsOfServiceAgreed": true}
+	if len(c.options.Contact) > 0 {
+		account["contact"] = c.options.Contact
+	}
+	header, err := c.post(ctx, c.directory.NewAccount, account, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	c.kid = header.Get("Location")
+	identifiers := make([]map[string]string, len(domains))
+	for i, domain := range domains {
+		identifiers[i] = map[string]string{"type": "dns", "value": domain}
+	}
+	var order acmeOrder
+	header, err = c.post(ctx, c.directory.NewOrder, map[string]interface{}{"identifiers": identifiers}, &order)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	orderUrl := header.Get("Location")
+	for _, url := range order.Authorizations {
+		if err = c.authorize(ctx, url); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: domains[0]}, DNSNames: domains}, key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if _, err = c.post(ctx, order.Finalize, map[string]string{"csr": base64.RawURLEncoding.EncodeToString(csr)}, &order); err != nil {
+		return nil, nil, nil, err
+	}
+	for order.Status != "valid" {
+		if order.Status == "invalid" {
+			return nil, nil, nil, fmt.Errorf("acme: order for %s is invalid", strings.Join(domains, ", "))
+		}
+		if err = c.wait(ctx); err != nil {
+			return nil, nil, nil, err
+		}
+		if _, err = c.post(ctx, orderUrl, nil, &order); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	var bundle []byte
+	if _, err = c.post(ctx, order.Certificate, nil, &bundle); err != nil {
+		return nil, nil, nil, err
+	}
+	block, rest := pem.Decode(bundle)
+	if block == nil {
+		return nil, nil, nil, fmt.Errorf("acme: no certificate returned")
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return pem.EncodeToMemory(block), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), bytes.TrimLeft(rest, "\n"), nil
+}
+
+// authorize solves http-01 challenge of the authorization and waits for its validation
+func (c *acmeClient) authorize(ctx context.Context, url string) error {
+	var authorization acmeAuthorization
+	if _, err := c.post(ctx, url, nil, &authorization); err != nil {
+		return err
+	}
+	if authorization.Status == "valid" {
+		return nil
+	}
+	domain := authorization.Identifier.Value
+	for _, challenge := range authorization.Challenges {
+		if challenge.Type != "http-01" {
+			continue
+		}
+		keyAuthorization := challenge.Token + "." + c.thumbprint()
+		if err := c.options.Solver.Present(ctx, domain, challenge.Token, keyAuthorization); err != nil {
+			return err
+		}
+		defer func(token string) { _ = c.options.Solver.CleanUp(ctx, domain, token) }(challenge.Token)
+		if _, err := c.post(ctx, challenge.Url, struct{}{}, nil); err != nil {
+			return err
+		}
+		for {
+			if err := c.wait(ctx); err != nil {
+				return err
+			}
+			if _, err := c.post(ctx, url, nil, &authorization); err != nil {
+				return err
+			}
+			switch authorization.Status {
+			case "valid":
+				return nil
+			case "invalid", "deactivated", "expired", "revoked":
+				return fmt.Errorf("acme: authorization of %s is %s", domain, authorization.Status)
+			}
+		}
+	}
+	return fmt.Errorf("acme: no http-01 challenge offered for %s", domain)
+}
+
+func (c *acmeClient) wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(c.options.PollInterval):
+		return nil
+	}
+}
+
+// jwk returns public key of the account in the form used for thumbprint, i.e. with sorted members
+func (c *acmeClient) jwk() string {
+	public := c.options.AccountKey.PublicKey
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	public.X.FillBytes(x)
+	public.Y.FillBytes(y)
+	return fmt.Sprintf(`{"crv":"P-256","kty":"EC","x":"%s","y":"%s"}`, base64.RawURLEncoding.EncodeToString(x), base64.RawURLEncoding.EncodeToString(y))
+}
+
+func (c *acmeClient) thumbprint() string {
+	sum := sha256.Sum256([]byte(c.jwk()))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func (c *acmeClient) get(ctx context.Context, url string, result interface{}) error {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.do(request.WithContext(ctx), result)
+	return err
+}
+
+// post sends JWS signed request, nil payload means POST-as-GET. Result []byte receives raw body.
+func (c *acmeClient) post(ctx context.Context, url string, payload interface{}, result interface{}) (http.Header, error) {
+	for attempt := 0; ; attempt++ {
+		if c.nonce == "" {
+			request, err := http.NewRequest(http.MethodHead, c.directory.NewNonce, nil)
+			if err != nil {
+				return nil, err
+			}
+			if _, err = c.do(request.WithContext(ctx), nil); err != nil {
+				return nil, err
+			}
+		}
+		body, err := c.sign(url, payload)
+		if err != nil {
+			return nil, err
+		}
+		request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("Content-Type", "application/jose+json")
+		header, err := c.do(request.WithContext(ctx), result)
+		if problem, ok := err.(*acmeProblem); ok && problem.Type == "urn:ietf:params:acme:error:badNonce" && attempt == 0 {
+			continue
+		}
+		return header, err
+	}
+}
+
+// sign creates flattened JWS of the payload
+func (c *acmeClient) sign(url string, payload interface{}) ([]byte, error) {
+	protected := map[string]interface{}{"alg": "ES256", "nonce": c.nonce, "url": url}
+	if c.kid != "" {
+		protected["kid"] = c.kid
+	} else {
+		protected["jwk"] = json.RawMessage(c.jwk())
+	}
+	c.nonce = ""
+	header, err := json.Marshal(protected)
+	if err != nil {
+		return nil, err
+	}
+	var content []byte
+	if payload != nil {
+		if content, err = json.Marshal(payload); err != nil {
+			return nil, err
+		}
+	}
+	encodedHeader := base64.RawURLEncoding.EncodeToString(header)
+	encodedPayload := base64.RawURLEncoding.EncodeToString(content)
+	digest := sha256.Sum256([]byte(encodedHeader + "." + encodedPayload))
+	r, s, err := ecdsa.Sign(rand.Reader, c.options.AccountKey, digest[:])
+	if err != nil {
+		return nil, err
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return json.Marshal(map[string]string{
+		"protected": encodedHeader,
+		"payload":   encodedPayload,
+		"signature": base64.RawURLEncoding.EncodeToString(signature),
+	})
+}
+
+// acmeProblem - Error document of ACME server
+type acmeProblem struct {
+	Type   string `json:"type"`
+	Detail string `json:"detail"`
+}
+
+func (p *acmeProblem) Error() string {
+	return fmt.Sprintf("acme: %s: %s", p.Type, p.Detail)
+}
+
+func (c *acmeClient) do(request *http.Request, result interface{}) (http.Header, error) {
+	response, err := c.options.Client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = response.Body.Close() }()
+	if nonce := response.Header.Get("Replay-Nonce"); nonce != "" {
+		c.nonce = nonce
+	}
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode >= 400 {
+		problem := &acmeProblem{}
+		if json.Unmarshal(data, problem) != nil || problem.Type == "" {
+			return nil, fmt.Errorf("acme: %s %s: %s", request.Method, request.URL, response.Status)
+		}
+		return nil, problem
+	}
+	switch result := result.(type) {
+	case nil:
+	case *[]byte:
+		*result = data
+	default:
+		if err = json.Unmarshal(data, result); err != nil {
+			return nil, fmt.Errorf("acme: %s: %v", request.URL, err)
+		}
+	}
+	return response.Header, nil
+}
+
+// RotateAcmeCertificate - Obtain certificate for the hostname of the server from ACME server and rotate it by RotateCertificate.
+//	acme - ACME server and challenge solver
+//	options - nil means defaults; Hostname and domains of the certificate are InitGetHostname if empty
+// Return
+//	result - imported certificate and removed ones
+func (s *ServerConnection) RotateAcmeCertificate(ctx context.Context, acme *AcmeOptions, options *CertificateRotationOptions) (*CertificateRotationResult, error) {
+	var o CertificateRotationOptions
+	if options != nil {
+		o = *options
+	}
+	if o.Hostname == "" {
+		hostname, err := s.InitGetHostname()
+		if err != nil {
+			return nil, err
+		}
+		o.Hostname = hostname
+	}
+	certPEM, keyPEM, chain, err := ObtainAcmeCertificate(ctx, StringList{o.Hostname}, acme)
+	if err != nil {
+		return nil, err
+	}
+	return s.RotateCertificate(certPEM, keyPEM, chain, &o)
+}
@@ -0,0 +1,314 @@
+package connect
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const DefaultCertificateWarnBefore = 30 * 24 * time.Hour // time before expiry the certificate is reported
+
+// CertificateInfo - Certificate of the server with the content parsed from its source
+type CertificateInfo struct {
+	Id                  KId                      `json:"id"`
+	Name                string                   `json:"name"`
+	Type                CertificateType          `json:"type"`
+	Subject             string                   `json:"subject"`     // common name of the subject
+	DnsNames            StringList               `json:"dnsNames"`    // DNS names of subject alternative names
+	IpAddresses         StringList               `json:"ipAddresses"` // IP addresses of subject alternative names
+	KeyType             string                   `json:"keyType"`     // RSA, ECDSA or Ed25519
+	KeySize             int                      `json:"keySize"`     // size of the key in bits
+	Issuer              string                   `json:"issuer"`      // common name of the issuer
+	Chain               StringList               `json:"chain"`       // subjects of the issuer chain, from the leaf's issuer up
+	NotBefore           time.Time                `json:"notBefore"`   // zero for certificate requests
+	NotAfter            time.Time                `json:"notAfter"`    // zero for certificate requests
+	IsSelfSigned        bool                     `json:"isSelfSigned"`
+	IsUntrusted         bool                     `json:"isUntrusted"`
+	VerificationMessage string                   `json:"verificationMessage"`
+	Certificate         *x509.Certificate        `json:"-"` // nil for certificate requests
+	Request             *x509.CertificateRequest `json:"-"` // set for certificate requests only
+}
+
+// CertificateInfoList - List of parsed certificates
+type CertificateInfoList []CertificateInfo
+
+// ParseCertificateSource - Parse PEM blocks of source returned by CertificatesToSource.
+// Text around the blocks is ignored.
+//	source - certificate in plain text
+// Return
+//	certificates - certificates in order of the source, the leaf first
+//	request - certificate request if the source contains one
+func ParseCertificateSource(source string) ([]*x509.Certificate, *x509.CertificateRequest, error) {
+	var certificates []*x509.Certificate
+	var request *x509.CertificateRequest
+	rest := []byte(source)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		switch block.Type {
+		case "CERTIFICATE":
+			certificate, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, nil, err
+			}
+			certificates = append(certificates, certificate)
+		case "CERTIFICATE REQUEST", "NEW CERTIFICATE REQUEST":
+			var err error
+			if request, err = x509.ParseCertificateRequest(block.Bytes); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	if len(certificates) == 0 && request == nil {
+		return nil, nil, fmt.Errorf("no PEM certificate found in source")
+	}
+	return certificates, request, nil
+}
+
+// NewCertificateInfo - Combine certificate of the server with its parsed source.
+//	certificate - certificate from CertificatesGet
+//	source - source from CertificatesToSource
+// Return
+//	info - parsed certificate
+func NewCertificateInfo(certificate Certificate, source string) (*CertificateInfo, error) {
+	certificates, request, err := ParseCertificateSource(source)
+	if err != nil {
+		return nil, fmt.Errorf("certificate %s: %v", certificate.Name, err)
+	}
+	info := &CertificateInfo{
+		Id:                  certificate.Id,
+		Name:                certificate.Name,
+		Type:                certificate.Type,
+		DnsNames:            StringList{},
+		IpAddresses:         StringList{},
+		Chain:               StringList{},
+		IsSelfSigned:        certificate.IsSelfSigned,
+		IsUntrusted:         certificate.IsUntrusted,
+		VerificationMessage: certificate.VerificationMessage,
+	}
+	if len(certificates) == 0 {
+		info.Request = request
+		info.Subject = request.Subject.CommonName
+		info.DnsNames = append(info.DnsNames, request.DNSNames...)
+		for _, ip := range request.IPAddresses {
+			info.IpAddresses = append(info.IpAddresses, ip.String())
+		}
+		info.KeyType, info.KeySize = publicKeyType(request.PublicKey)
+		return info, nil
+	}
+	leaf := certificates[0]
+	info.Certificate = leaf
+	info.Subject = leaf.Subject.CommonName
+	info.DnsNames = append(info.DnsNames, leaf.DNSNames...)
+	for _, ip := range leaf.IPAddresses {
+		info.IpAddresses = append(info.IpAddresses, ip.String())
+	}
+	info.KeyType, info.KeySize = publicKeyType(leaf.PublicKey)
+	info.Issuer = leaf.Issuer.CommonName
+	info.NotBefore = leaf.NotBefore
+	info.NotAfter = leaf.NotAfter
+	for _, issuer := range certificates[1:] {
+		info.Chain = append(info.Chain, issuer.Subject.CommonName)
+	}
+	if len(info.Chain) == 0 {
+		info.Chain = append(info.Chain, certificate.ChainInfo...)
+	}
+	if !info.IsSelfSigned && bytes.Equal(leaf.RawSubject, leaf.RawIssuer) &&
+		leaf.CheckSignature(leaf.SignatureAlgorithm, leaf.RawTBSCertificate, leaf.Signature) == nil {
+		info.IsSelfSigned = true
+	}
+	return info, nil
+}
+
+// publicKeyType returns name of the key algorithm and size of the key in bits
+func publicKeyType(key interface{}) (string, int) {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return "RSA", key.N.BitLen()
+	case *ecdsa.PublicKey:
+		return "ECDSA", key.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return "Ed25519", 256
+	}
+	return "unknown", 0
+}
+
+// CertificateInventory - Read all certificates and parse their sources.
+// Built-in authorities are skipped.
+// Return
+//	list - parsed certificates
+func (s *ServerConnection) CertificateInventory() (CertificateInfoList, error) {
+	certificates, _, err := s.CertificatesGet(SearchQuery{})
+	if err != nil {
+		return nil, err
+	}
+	list := CertificateInfoList{}
+	for _, certificate := range certificates {
+		if certificate.Type == BuiltInAuthority {
+			continue
+		}
+		source, err := s.CertificatesToSource(certificate.Id)
+		if err != nil {
+			return nil, err
+		}
+		info, err := NewCertificateInfo(certificate, source)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *info)
+	}
+	return list, nil
+}
+
+// Active - Return the active certificate
+func (l CertificateInfoList) Active() (CertificateInfo, bool) {
+	for _, info := range l {
+		if info.Type == ActiveCertificate {
+			return info, true
+		}
+	}
+	return CertificateInfo{}, false
+}
+
+// WriteCertificateInventory - Write certificates as text table for people
+func WriteCertificateInventory(w io.Writer, list CertificateInfoList) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Name\tType\tSubject\tNames\tKey\tIssuer\tExpires\tFlags\n")
+	for _, info := range list {
+		var flags []string
+		if info.IsSelfSigned {
+			flags = append(flags, "self-signed")
+		}
+		if info.IsUntrusted {
+			flags = append(flags, "untrusted")
+		}
+		expires := ""
+		if !info.NotAfter.IsZero() {
+			expires = info.NotAfter.UTC().Format("2006-01-02")
+		}
+		issuer := strings.Join(append(StringList{info.Issuer}, info.Chain...), " < ")
+		names := strings.Join(append(append(StringList{}, info.DnsNames...), info.IpAddresses...), ", ")
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s %d\t%s\t%s\t%s\n", info.Name, info.Type, info.Subject, names,
+			info.KeyType, info.KeySize, issuer, expires, strings.Join(flags, ", "))
+	}
+	return tw.Flush()
+}
+
+// CertificateFindingCode - Kind of certificate problem
+type CertificateFindingCode string
+
+const (
+	CertificateNoActive         CertificateFindingCode = "CertificateNoActive"         // no certificate is active
+	CertificateExpired          CertificateFindingCode = "CertificateExpired"          // certificate is not valid anymore
+	CertificateExpiresSoon      CertificateFindingCode = "CertificateExpiresSoon"      // certificate expires within WarnBefore
+	CertificateNotYetValid      CertificateFindingCode = "CertificateNotYetValid"      // validity of certificate did not start yet
+	CertificateHostnameMismatch CertificateFindingCode = "CertificateHostnameMismatch" // active certificate does not cover the hostname of the server
+	CertificateUntrusted        CertificateFindingCode = "CertificateUntrusted"        // active certificate is self-signed or not trusted
+)
+
+// CertificatePolicy - Expectations on certificates
+type CertificatePolicy struct {
+	WarnBefore  time.Duration `json:"warnBefore" yaml:"warnBefore"`   // time before expiry the certificate is reported; DefaultCertificateWarnBefore if 0
+	AllInactive bool          `json:"allInactive" yaml:"allInactive"` // check expiry of inactive certificates and authorities too
+}
+
+// CertificateFinding - Problem of a certificate
+type CertificateFinding struct {
+	Id          KId                    `json:"id"` // certificate, empty for CertificateNoActive
+	Name        string                 `json:"name"`
+	Code        CertificateFindingCode `json:"code"`
+	Severity    TypeAlert              `json:"severity"`
+	Description string                 `json:"description"`
+}
+
+// CertificateFindingList - Problems of certificates
+type CertificateFindingList []CertificateFinding
+
+// EvaluateCertificates - Check expiry of certificates and whether the active certificate covers the hostname.
+//	list - certificates from CertificateInventory
+//	hostname - hostname of the server from InitGetHostname; empty disables the check
+//	policy - expectations; nil means defaults
+//	now - time the expiry is measured to
+// Return
+//	findings - found problems
+func EvaluateCertificates(list CertificateInfoList, hostname string, policy *CertificatePolicy, now time.Time) CertificateFindingList {
+	var p CertificatePolicy
+	if policy != nil {
+		p = *policy
+	}
+	if p.WarnBefore <= 0 {
+		p.WarnBefore = DefaultCertificateWarnBefore
+	}
+	findings := CertificateFindingList{}
+	add := func(info CertificateInfo, code CertificateFindingCode, severity TypeAlert, format string, args ...interface{}) {
+		findings = append(findings, CertificateFinding{Id: info.Id, Name: info.Name, Code: code, Severity: severity, Description: fmt.Sprintf(format, args...)})
+	}
+	active, ok := list.Active()
+	if !ok {
+		add(CertificateInfo{}, CertificateNoActive, Critical, "no certificate is active")
+	}
+	for _, info := range list {
+		if info.Certificate == nil || (info.Type != ActiveCertificate && !p.AllInactive) {
+			continue
+		}
+		severity := Warning
+		if info.Type == ActiveCertificate {
+			severity = Critical
+		}
+		switch left := info.NotAfter.Sub(now); {
+		case left <= 0:
+			add(info, CertificateExpired, severity, "certificate %s expired %s ago", info.Name, formatAge(-left.Truncate(time.Hour)))
+		case left < p.WarnBefore:
+			add(info, CertificateExpiresSoon, Warning, "certificate %s expires in %s on %s", info.Name, formatAge(left.Truncate(time.Hour)),
+				info.NotAfter.UTC().Format("2006-01-02"))
+		}
+		if now.Before(info.NotBefore) {
+			add(info, CertificateNotYetValid, severity, "certificate %s is valid from %s", info.Name, info.NotBefore.UTC().Format(time.RFC3339))
+		}
+	}
+	if !ok || active.Certificate == nil {
+		return findings
+	}
+	if hostname != "" && active.Certificate.VerifyHostname(hostname) != nil {
+		add(active, CertificateHostnameMismatch, Critical, "active certificate %s does not cover hostname %s, it covers %s",
+			active.Name, hostname, strings.Join(active.DnsNames, ", "))
+	}
+	if active.IsSelfSigned || active.IsUntrusted {
+		message := "self-signed"
+		if !active.IsSelfSigned {
+			message = "not trusted"
+		}
+		if active.VerificationMessage != "" {
+			message += ": " + active.VerificationMessage
+		}
+		add(active, CertificateUntrusted, Warning, "active certificate %s is %s", active.Name, message)
+	}
+	return findings
+}
+
+// CheckCertificates - Read certificates and hostname of the server and check them.
+//	policy - expectations; nil means defaults
+// Return
+//	findings - found problems
+func (s *ServerConnection) CheckCertificates(policy *CertificatePolicy) (CertificateFindingList, error) {
+	list, err := s.CertificateInventory()
+	if err != nil {
+		return nil, err
+	}
+	hostname, err := s.InitGetHostname()
+	if err != nil {
+		return nil, err
+	}
+	return EvaluateCertificates(list, hostname, policy, time.Now()), nil
+}
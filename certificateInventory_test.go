@@ -0,0 +1,133 @@
+package connect
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCertificate signs certificate for the names by parent, nil parent means self-signed and no names mean authority
+func testCertificate(t *testing.T, name string, notAfter time.Time, parent *x509.Certificate, parentKey crypto.Signer, names ...string) (*x509.Certificate, crypto.Signer, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    notAfter.AddDate(-10, 0, 0),
+		NotAfter:     notAfter,
+		DNSNames:     names,
+	}
+	if len(names) == 0 {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certificate, key, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestValidPeriod(t *testing.T) {
+	period := ValidPeriod{ValidFromDate: Date{Year: 2021, Month: 0, Day: 31}, ValidFromTime: Time{Hour: 13, Min: 5}}
+	if from := period.ValidFrom(nil); !from.Equal(time.Date(2021, 1, 31, 13, 5, 0, 0, time.UTC)) {
+		t.Errorf("unexpected time %s", from)
+	}
+	if !period.ValidTo(nil).IsZero() {
+		t.Errorf("empty date is not zero time")
+	}
+}
+
+func TestNewCertificateInfoSelfSigned(t *testing.T) {
+	ca, caKey, _ := testCertificate(t, "Test CA", time.Now().AddDate(1, 0, 0), nil, nil)
+	_, _, selfSigned := testCertificate(t, "mail.example.com", time.Now().AddDate(1, 0, 0), nil, nil, "mail.example.com")
+	_, _, signed := testCertificate(t, "mail.example.com", time.Now().AddDate(1, 0, 0), ca, caKey, "mail.example.com")
+	for source, expected := range map[string]bool{selfSigned: true, signed: false} {
+		info, err := NewCertificateInfo(Certificate{Id: "1", Name: "server", Type: ActiveCertificate}, source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.IsSelfSigned != expected || info.Certificate.IsCA {
+			t.Errorf("self-signed %v, expected %v", info.IsSelfSigned, expected)
+		}
+	}
+}
+
+func TestEvaluateCertificates(t *testing.T) {
+	now := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	ca, caKey, caSource := testCertificate(t, "Test CA", now.AddDate(5, 0, 0), nil, nil)
+	_, _, leafSource := testCertificate(t, "mail.example.com", now.AddDate(0, 0, 10), ca, caKey, "mail.example.com", "*.example.org")
+	_, _, oldSource := testCertificate(t, "old.example.com", now.AddDate(0, 0, -3), ca, caKey, "old.example.com")
+	list := CertificateInfoList{}
+	for _, c := range []struct {
+		certificate Certificate
+		source      string
+	}{
+		{Certificate{Id: "1", Name: "server", Type: ActiveCertificate, IsUntrusted: true, VerificationMessage: "unknown issuer"}, "Certificate:\n" + leafSource + caSource},
+		{Certificate{Id: "2", Name: "old", Type: InactiveCertificate}, oldSource},
+		{Certificate{Id: "3", Name: "ca", Type: LocalAuthority, ChainInfo: StringList{"Test CA"}}, caSource},
+	} {
+		info, err := NewCertificateInfo(c.certificate, c.source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		list = append(list, *info)
+	}
+	if info := list[0]; info.KeyType != "ECDSA" || info.KeySize != 256 || info.Issuer != "Test CA" || len(info.Chain) != 1 ||
+		len(info.DnsNames) != 2 || info.IsSelfSigned || !list[2].IsSelfSigned {
+		t.Errorf("unexpected info %+v", info)
+	}
+	var codes []string
+	for _, finding := range EvaluateCertificates(list, "webmail.example.org", &CertificatePolicy{AllInactive: true}, now) {
+		codes = append(codes, string(finding.Code))
+	}
+	if strings.Join(codes, ",") != "CertificateExpiresSoon,CertificateExpired,CertificateUntrusted" {
+		t.Errorf("unexpected findings %v", codes)
+	}
+	findings := EvaluateCertificates(list, "mail.example.net", nil, now)
+	if len(findings) != 3 || findings[1].Code != CertificateHostnameMismatch ||
+		findings[1].Description != "active certificate server does not cover hostname mail.example.net, it covers mail.example.com, *.example.org" {
+		t.Errorf("unexpected findings %+v", findings)
+	}
+	if findings = EvaluateCertificates(list[1:], "", nil, now); len(findings) != 1 || findings[0].Code != CertificateNoActive {
+		t.Errorf("unexpected findings %+v", findings)
+	}
+}
+
+func TestCheckCertificates(t *testing.T) {
+	_, _, source := testCertificate(t, "mail.example.com", time.Now().AddDate(1, 0, 0), nil, nil, "mail.example.com")
+	conn, fake := newFakeConnection(t, map[string]fakeHandler{
+		"Certificates.get": result(map[string]interface{}{"certificates": CertificateList{
+			{Id: "1", Name: "server", Type: ActiveCertificate},
+			{Id: "2", Name: "builtin", Type: BuiltInAuthority},
+		}}),
+		"Certificates.toSource": result(map[string]interface{}{"source": source}),
+		"Init.getHostname":      result(map[string]interface{}{"hostname": "mail.example.com"}),
+	})
+	findings, err := conn.CheckCertificates(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 || findings[0].Code != CertificateUntrusted || fake.called("Certificates.toSource") != 1 {
+		t.Errorf("unexpected findings %+v", findings)
+	}
+}
@@ -0,0 +1,321 @@
+package connect
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+)
+
+const DefaultLocalCertificateValidity = 365 * 24 * time.Hour // validity of certificates signed by LocalCertificateAuthority
+
+// CertificateSubject - Subject of generated certificate or certificate request
+type CertificateSubject struct {
+	CommonName         string     `json:"commonName" yaml:"commonName"`                 // hostname, required
+	Organization       string     `json:"organization" yaml:"organization"`             // organizationName
+	OrganizationalUnit string     `json:"organizationalUnit" yaml:"organizationalUnit"` // organizationalUnitName
+	City               string     `json:"city" yaml:"city"`
+	State              string     `json:"state" yaml:"state"`
+	Country            string     `json:"country" yaml:"country"` // ISO 3166 code, e.g. CZ
+	EmailAddress       string     `json:"emailAddress" yaml:"emailAddress"`
+	AlternativeNames   StringList `json:"alternativeNames" yaml:"alternativeNames"` // DNS names and IP addresses
+}
+
+// NamedValues - Return the subject as name/value pairs of CertificatesGenerate.
+// Alternative names are passed as comma separated subjectAlternativeName, e.g. "DNS:mail.example.com,IP:192.0.2.1".
+func (s CertificateSubject) NamedValues() NamedValueList {
+	values := NamedValueList{}
+	for _, field := range []NamedValue{
+		{Name: "hostname", Value: s.CommonName},
+		{Name: "organizationName", Value: s.Organization},
+		{Name: "organizationalUnitName", Value: s.OrganizationalUnit},
+		{Name: "city", Value: s.City},
+		{Name: "state", Value: s.State},
+		{Name: "country", Value: strings.ToUpper(s.Country)},
+		{Name: "emailAddress", Value: s.EmailAddress},
+	} {
+		if field.Value != "" {
+			values = append(values, field)
+		}
+	}
+	if len(s.AlternativeNames) > 0 {
+		names := make([]string, len(s.AlternativeNames))
+		for i, name := range s.AlternativeNames {
+			if net.ParseIP(name) != nil {
+				names[i] = "IP:" + name
+			} else {
+				names[i] = "DNS:" + name
+			}
+		}
+		values = append(values, NamedValue{Name: "subjectAlternativeName", Value: strings.Join(names, ",")})
+	}
+	return values
+}
+
+// Validate - Check the subject before it is sent to the server.
+//	countries - list from CertificatesGetCountryList; nil skips the check of country
+func (s CertificateSubject) Validate(countries NamedValueList) error {
+	if s.CommonName == "" {
+		return fmt.Errorf("common name is required")
+	}
+	for _, field := range s.NamedValues() {
+		limit := 127
+		if field.Name == "emailAddress" {
+			limit = 255
+		}
+		if field.Name != "subjectAlternativeName" && len(field.Value) > limit {
+			return fmt.Errorf("%s is longer than %d bytes", field.Name, limit)
+		}
+	}
+	if s.Country != "" && countries != nil {
+		known := false
+		for _, country := range countries {
+			known = known || strings.EqualFold(country.Value, s.Country)
+		}
+		if !known {
+			return fmt.Errorf("unknown country code %q", s.Country)
+		}
+	}
+	for _, name := range append(StringList{s.CommonName}, s.AlternativeNames...) {
+		if net.ParseIP(name) == nil && !validDnsName(name) {
+			return fmt.Errorf("invalid DNS name %q", name)
+		}
+	}
+	return nil
+}
+
+// validDnsName accepts host names with optional leading wildcard label
+func validDnsName(name string) bool {
+	name = strings.TrimPrefix(name, "*.")
+	if name == "" || len(name) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// GenerateCertificateRequest - Validate the subject against countries of the server and generate certificate request with new private key on the server.
+//	subject - subject of the request
+//	name - name of the request
+// Return
+//	id - ID of generated certificate request
+func (s *ServerConnection) GenerateCertificateRequest(subject CertificateSubject, name string) (KId, error) {
+	countries, err := s.CertificatesGetCountryList()
+	if err != nil {
+		return "", err
+	}
+	if err = subject.Validate(countries); err != nil {
+		return "", err
+	}
+	return s.CertificatesGenerate(subject.NamedValues(), name, CertificateRequest, ValidPeriod{})
+}
+
+// ExportCertificateRequest - Download certificate request by CertificatesExportCertificate.
+//	id - ID of certificate request
+// Return
+//	csrPEM - certificate request in PEM format
+func (s *ServerConnection) ExportCertificateRequest(id KId) ([]byte, error) {
+	download, err := s.CertificatesExportCertificate(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.DownloadFile(download)
+}
+
+// LocalCertificateAuthority - Certificate authority for lab environments signing requests of the server
+type LocalCertificateAuthority struct {
+	Certificate *x509.Certificate
+	Key         crypto.Signer
+}
+
+// NewLocalCertificateAuthority - Create self-signed authority with new ECDSA key.
+//	commonName - name of the authority
+//	validity - validity of the authority certificate
+func NewLocalCertificateAuthority(commonName string, validity time.Duration) (*LocalCertificateAuthority, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := randomSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(validity),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalCertificateAuthority{Certificate: certificate, Key: key}, nil
+}
+
+// LoadLocalCertificateAuthority - Load authority from PEM files
+//	certPEM - certificate of the authority
+//	keyPEM - unencrypted PKCS #8, PKCS #1 or EC private key of the authority
+func LoadLocalCertificateAuthority(certPEM, keyPEM []byte) (*LocalCertificateAuthority, error) {
+	certificates, _, err := ParseCertificateSource(string(certPEM))
+	if err != nil {
+		return nil, err
+	}
+	if len(certificates) == 0 || !certificates[0].IsCA {
+		return nil, fmt.Errorf("certificate of authority not found")
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM private key found")
+	}
+	var key interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key %T", key)
+	}
+	return &LocalCertificateAuthority{Certificate: certificates[0], Key: signer}, nil
+}
+
+// CertificatePEM - Return certificate of the authority in PEM format
+func (ca *LocalCertificateAuthority) CertificatePEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Certificate.Raw})
+}
+
+// KeyPEM - Return private key of the authority in PKCS #8 PEM format
+func (ca *LocalCertificateAuthority) KeyPEM() ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(ca.Key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// Sign - Sign certificate request for TLS server.
+// The common name is added to alternative names if the request has none.
+//	csrPEM - certificate request in PEM format
+//	validity - DefaultLocalCertificateValidity if 0
+// Return
+//	certPEM - signed certificate in PEM format
+func (ca *LocalCertificateAuthority) Sign(csrPEM []byte, validity time.Duration) ([]byte, error) {
+	if validity <= 0 {
+		validity = DefaultLocalCertificateValidity
+	}
+	_, request, err := ParseCertificateSource(string(csrPEM))
+	if err != nil {
+		return nil, err
+	}
+	if request == nil {
+		return nil, fmt.Errorf("no certificate request found")
+	}
+	if err = request.CheckSignature(); err != nil {
+		return nil, err
+	}
+	serial, err := randomSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:   serial,
+		Subject:        request.Subject,
+		DNSNames:       request.DNSNames,
+		IPAddresses:    request.IPAddresses,
+		EmailAddresses: request.EmailAddresses,
+		NotBefore:      now.Add(-time.Hour),
+		NotAfter:       now.Add(validity),
+		KeyUsage:       x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if len(template.DNSNames) == 0 && len(template.IPAddresses) == 0 {
+		if ip := net.ParseIP(request.Subject.CommonName); ip != nil {
+			template.IPAddresses = []net.IP{ip}
+		} else if request.Subject.CommonName != "" {
+			template.DNSNames = []string{request.Subject.CommonName}
+		}
+	}
+	if template.NotAfter.After(ca.Certificate.NotAfter) {
+		template.NotAfter = ca.Certificate.NotAfter
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.Certificate, request.PublicKey, ca.Key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
+
+func randomSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+// SignCertificateRequest - Export certificate request of the server, sign it by the local authority and import the signed certificate.
+// The certificate is imported as inactive and uses the private key of the request.
+//	id - ID of certificate request
+//	ca - local authority
+//	name - name of the imported certificate
+//	validity - DefaultLocalCertificateValidity if 0
+// Return
+//	certificateId - ID of imported certificate
+func (s *ServerConnection) SignCertificateRequest(id KId, ca *LocalCertificateAuthority, name string, validity time.Duration) (KId, error) {
+	csrPEM, err := s.ExportCertificateRequest(id)
+	if err != nil {
+		return "", err
+	}
+	certPEM, err := ca.Sign(csrPEM, validity)
+	if err != nil {
+		return "", err
+	}
+	fileId, err := s.UploadFile(name+".pem", append(certPEM, ca.CertificatePEM()...))
+	if err != nil {
+		return "", err
+	}
+	return s.CertificatesImportCertificate(id, fileId, name, InactiveCertificate)
+}
+
+// ImportLocalCertificateAuthority - Import certificate of the local authority as trusted authority of the server.
+//	ca - local authority
+//	name - name of the imported authority
+// Return
+//	id - ID of imported authority
+func (s *ServerConnection) ImportLocalCertificateAuthority(ca *LocalCertificateAuthority, name string) (KId, error) {
+	fileId, err := s.UploadFile(name+".pem", ca.CertificatePEM())
+	if err != nil {
+		return "", err
+	}
+	return s.CertificatesImportCertificate("", fileId, name, Authority)
+}
@@ -0,0 +1,127 @@
+package connect
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCertificateSubject(t *testing.T) {
+	subject := CertificateSubject{CommonName: "mail.example.com", Organization: "Example", Country: "cz",
+		AlternativeNames: StringList{"mail.example.com", "192.0.2.1"}}
+	values := subject.NamedValues()
+	if len(values) != 4 || values[2] != (NamedValue{Name: "country", Value: "CZ"}) ||
+		values[3] != (NamedValue{Name: "subjectAlternativeName", Value: "DNS:mail.example.com,IP:192.0.2.1"}) {
+		t.Errorf("unexpected values %+v", values)
+	}
+	countries := NamedValueList{{Name: "Czech Republic", Value: "CZ"}, {Name: "Germany", Value: "DE"}}
+	if err := subject.Validate(countries); err != nil {
+		t.Error(err)
+	}
+	for _, c := range []struct {
+		subject CertificateSubject
+		err     string
+	}{
+		{CertificateSubject{}, "common name is required"},
+		{CertificateSubject{CommonName: "mail.example.com", Country: "XX"}, `unknown country code "XX"`},
+		{CertificateSubject{CommonName: "mail.example.com", City: strings.Repeat("x", 128)}, "city is longer than 127 bytes"},
+		{CertificateSubject{CommonName: "mail.example.com", AlternativeNames: StringList{"bad_name.example.com"}}, `invalid DNS name "bad_name.example.com"`},
+	} {
+		if err := c.subject.Validate(countries); err == nil || err.Error() != c.err {
+			t.Errorf("unexpected error %v, expected %s", err, c.err)
+		}
+	}
+}
+
+func TestSignCertificateRequest(t *testing.T) {
+	// the fake server keeps the private key of the request like Kerio Connect does
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var generated NamedValueList
+	var imported []string
+	var uploaded string
+	conn, fake := newFakeConnection(t, map[string]fakeHandler{
+		"Certificates.getCountryList": result(map[string]interface{}{"countries": NamedValueList{{Name: "Czech Republic", Value: "CZ"}}}),
+		"Certificates.generate": func(params json.RawMessage) interface{} {
+			request := struct {
+				Subject NamedValueList  `json:"subject"`
+				Type    CertificateType `json:"type"`
+			}{}
+			json.Unmarshal(params, &request)
+			if request.Type == CertificateRequest {
+				generated = request.Subject
+			}
+			return map[string]interface{}{"id": "req1"}
+		},
+		"Certificates.exportCertificate": result(map[string]interface{}{"fileDownload": Download{Url: "/admin/api/jsonrpc/download/req1.csr", Name: "req1.csr"}}),
+		"download": func(params json.RawMessage) interface{} {
+			der, _ := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "mail.example.com"}}, key)
+			return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+		},
+		"upload": func(params json.RawMessage) interface{} {
+			json.Unmarshal(params, &uploaded)
+			return map[string]interface{}{"fileUpload": map[string]string{"id": "file1"}}
+		},
+		"Certificates.importCertificate": func(params json.RawMessage) interface{} {
+			request := struct {
+				KeyId KId             `json:"keyId"`
+				Name  string          `json:"name"`
+				Type  CertificateType `json:"type"`
+			}{}
+			json.Unmarshal(params, &request)
+			imported = append(imported, string(request.KeyId)+" "+request.Name+" "+string(request.Type))
+			return map[string]interface{}{"id": "cert1"}
+		},
+	})
+	id, err := conn.GenerateCertificateRequest(CertificateSubject{CommonName: "mail.example.com", Country: "CZ"}, "request")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "req1" || len(generated) != 2 || generated[0].Value != "mail.example.com" {
+		t.Errorf("unexpected request %s %+v", id, generated)
+	}
+	if _, err = conn.GenerateCertificateRequest(CertificateSubject{CommonName: "mail.example.com", Country: "DE"}, "request"); err == nil {
+		t.Errorf("unknown country accepted")
+	}
+	ca, err := NewLocalCertificateAuthority("Lab CA", 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificateId, err := conn.SignCertificateRequest(id, ca, "lab", 48*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if certificateId != "cert1" || fake.called("download") != 1 || strings.Join(imported, ",") != "req1 lab InactiveCertificate" {
+		t.Errorf("unexpected import %s %v", certificateId, imported)
+	}
+	certificates, _, err := ParseCertificateSource(uploaded)
+	if err != nil || len(certificates) != 2 {
+		t.Fatalf("unexpected upload %v\n%s", err, uploaded)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Certificate)
+	leaf := certificates[0]
+	if _, err = leaf.Verify(x509.VerifyOptions{DNSName: "mail.example.com", Roots: roots}); err != nil {
+		t.Error(err)
+	}
+	if !leaf.NotAfter.Equal(ca.Certificate.NotAfter) || !leaf.PublicKey.(*ecdsa.PublicKey).Equal(&key.PublicKey) {
+		t.Errorf("unexpected certificate %s %v", leaf.NotAfter, leaf.PublicKey)
+	}
+	keyPEM, err := ca.KeyPEM()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadLocalCertificateAuthority(ca.CertificatePEM(), keyPEM)
+	if err != nil || !loaded.Certificate.Equal(ca.Certificate) {
+		t.Errorf("authority not loaded: %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package connect
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CertificateRotationOptions - Options of RotateCertificate
+type CertificateRotationOptions struct {
+	Name           string         // name of the imported certificate; hostname and expiry date if empty
+	Hostname       string         // hostname the certificate has to cover; InitGetHostname if empty
+	Roots          *x509.CertPool // trusted authorities for verification of the chain; system roots if nil
+	KeyPassword    string         // password of encrypted private key
+	KeepSuperseded bool           // keep inactive certificates replaced by the new one
+}
+
+// CertificateRotationResult - Result of RotateCertificate
+type CertificateRotationResult struct {
+	Id       KId        `json:"id"`       // imported and activated certificate
+	Name     string     `json:"name"`     // name of the imported certificate
+	Hostname string     `json:"hostname"` // verified hostname
+	NotAfter time.Time  `json:"notAfter"` // expiry of the new certificate
+	Removed  StringList `json:"removed"`  // names of removed superseded certificates
+}
+
+// RotateCertificate - Replace the active SSL certificate of the server.
+// The certificate is verified against the chain and the hostname before anything is changed, then the private key
+// and the certificate are imported, the certificate is activated and inactive certificates for the same hostname
+// which expire sooner are removed.
+//	certPEM - certificate in PEM format, may be followed by intermediate certificates
+//	keyPEM - private key of the certificate in PEM format, optionally encrypted by KeyPassword
+//	chain - intermediate certificates in PEM format; may be nil
+//	options - nil means defaults
+// Return
+//	result - imported certificate and removed ones
+func (s *ServerConnection) RotateCertificate(certPEM, keyPEM, chain []byte, options *CertificateRotationOptions) (*CertificateRotationResult, error) {
+	var o CertificateRotationOptions
+	if options != nil {
+		o = *options
+	}
+	if o.Hostname == "" {
+		hostname, err := s.InitGetHostname()
+		if err != nil {
+			return nil, err
+		}
+		o.Hostname = hostname
+	}
+	leaf, err := verifyCertificateChain(certPEM, keyPEM, chain, o.Hostname, o.Roots, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	result := &CertificateRotationResult{Name: o.Name, Hostname: o.Hostname, NotAfter: leaf.NotAfter, Removed: StringList{}}
+	if result.Name == "" {
+		result.Name = fmt.Sprintf("%s %s", o.Hostname, leaf.NotAfter.UTC().Format("2006-01-02"))
+	}
+	keyFile, err := s.UploadFile("key.pem", keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	keyId, needPassword, err := s.CertificatesImportPrivateKey(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	if needPassword {
+		if o.KeyPassword == "" {
+			return nil, fmt.Errorf("private key is encrypted and no password is given")
+		}
+		if err = s.CertificatesUnlockPrivateKey(keyId, o.KeyPassword); err != nil {
+			return nil, err
+		}
+	}
+	content := strings.TrimRight(string(certPEM), "\n") + "\n" + string(chain)
+	certificateFile, err := s.UploadFile("certificate.pem", []byte(content))
+	if err != nil {
+		return nil, err
+	}
+	if result.Id, err = s.CertificatesImportCertificate(keyId, certificateFile, result.Name, InactiveCertificate); err != nil {
+		return nil, err
+	}
+	if err = s.ConnectCertificateSetActive(result.Id); err != nil {
+		return result, err
+	}
+	list, err := s.CertificateInventory()
+	if err != nil {
+		return result, err
+	}
+	if active, ok := list.Active(); !ok || active.Id != result.Id {
+		return result, fmt.Errorf("certificate %s was imported but is not active", result.Name)
+	}
+	if o.KeepSuperseded {
+		return result, nil
+	}
+	var superseded KIdList
+	for _, info := range list {
+		if info.Type == InactiveCertificate && info.Id != result.Id && info.Certificate != nil &&
+			info.Certificate.VerifyHostname(o.Hostname) == nil && info.NotAfter.Before(leaf.NotAfter) {
+			superseded = append(superseded, info.Id)
+			result.Removed = append(result.Removed, info.Name)
+		}
+	}
+	if len(superseded) == 0 {
+		return result, nil
+	}
+	errors, err := s.CertificatesRemove(superseded)
+	if err != nil {
+		return result, err
+	}
+	if len(errors) > 0 {
+		return result, fmt.Errorf("cannot remove superseded certificate: %s", errors[0].Message)
+	}
+	return result, nil
+}
+
+// verifyCertificateChain checks that the certificate is valid for the hostname and matches the unencrypted private key
+func verifyCertificateChain(certPEM, keyPEM, chain []byte, hostname string, roots *x509.CertPool, now time.Time) (*x509.Certificate, error) {
+	certificates, _, err := ParseCertificateSource(string(certPEM) + "\n" + string(chain))
+	if err != nil {
+		return nil, err
+	}
+	if len(certificates) == 0 {
+		return nil, fmt.Errorf("no certificate found, certificate request cannot be imported")
+	}
+	intermediates := x509.NewCertPool()
+	for _, certificate := range certificates[1:] {
+		intermediates.AddCert(certificate)
+	}
+	leaf := certificates[0]
+	_, err = leaf.Verify(x509.VerifyOptions{DNSName: hostname, Roots: roots, Intermediates: intermediates, CurrentTime: now})
+	if err != nil {
+		return nil, fmt.Errorf("certificate %s: %v", leaf.Subject.CommonName, err)
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM private key found")
+	}
+	if block.Type == "ENCRYPTED PRIVATE KEY" || block.Headers["Proc-Type"] == "4,ENCRYPTED" {
+		return leaf, nil
+	}
+	if _, err = tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		return nil, fmt.Errorf("certificate %s: %v", leaf.Subject.CommonName, err)
+	}
+	return leaf, nil
+}
@@ -0,0 +1,220 @@
+package connect
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeAcme is ACME server issuing certificates by the intermediate after it fetched the http-01 challenge from the solver
+type fakeAcme struct {
+	mu           sync.Mutex
+	url          string
+	solverUrl    string
+	intermediate *x509.Certificate
+	key          crypto.Signer
+	nonce        int
+	validated    bool
+	issued       []byte
+}
+
+func (f *fakeAcme) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nonce++
+	w.Header().Set("Replay-Nonce", fmt.Sprintf("nonce%d", f.nonce))
+	var payload json.RawMessage
+	if r.Method == http.MethodPost {
+		jws := struct {
+			Protected string `json:"protected"`
+			Payload   string `json:"payload"`
+		}{}
+		json.NewDecoder(r.Body).Decode(&jws)
+		payload, _ = base64.RawURLEncoding.DecodeString(jws.Payload)
+		header, _ := base64.RawURLEncoding.DecodeString(jws.Protected)
+		if !strings.Contains(string(header), `"url":"`+f.url+r.URL.Path+`"`) {
+			http.Error(w, `{"type": "urn:ietf:params:acme:error:malformed", "detail": "wrong url"}`, http.StatusBadRequest)
+			return
+		}
+	}
+	order := map[string]interface{}{"status": "pending", "authorizations": []string{f.url + "/authz/1"}, "finalize": f.url + "/finalize"}
+	switch r.URL.Path {
+	case "/dir":
+		json.NewEncoder(w).Encode(map[string]string{"newNonce": f.url + "/nonce", "newAccount": f.url + "/account", "newOrder": f.url + "/order"})
+	case "/nonce":
+	case "/account":
+		w.Header().Set("Location", f.url+"/account/1")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("{}"))
+	case "/order":
+		w.Header().Set("Location", f.url+"/order/1")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(order)
+	case "/authz/1":
+		status := "pending"
+		if f.validated {
+			status = "valid"
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": status, "identifier": map[string]string{"type": "dns", "value": "mail.example.com"},
+			"challenges": []map[string]string{{"type": "dns-01", "url": f.url + "/chall/2", "token": "dns"}, {"type": "http-01", "url": f.url + "/chall/1", "token": "tok"}}})
+	case "/chall/1":
+		response, err := http.Get(f.solverUrl + "/.well-known/acme-challenge/tok")
+		if err == nil {
+			body, _ := ioutil.ReadAll(response.Body)
+			response.Body.Close()
+			f.validated = strings.HasPrefix(string(body), "tok.") && len(body) == 4+43
+		}
+		w.Write([]byte("{}"))
+	case "/finalize":
+		request := struct {
+			Csr string `json:"csr"`
+		}{}
+		json.Unmarshal(payload, &request)
+		der, _ := base64.RawURLEncoding.DecodeString(request.Csr)
+		csr, err := x509.ParseCertificateRequest(der)
+		if err != nil || !f.validated {
+			http.Error(w, `{"type": "urn:ietf:params:acme:error:badCSR", "detail": "rejected"}`, http.StatusForbidden)
+			return
+		}
+		template := &x509.Certificate{SerialNumber: big.NewInt(2), Subject: csr.Subject, DNSNames: csr.DNSNames,
+			NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().AddDate(0, 3, 0)}
+		leaf, _ := x509.CreateCertificate(rand.Reader, template, f.intermediate, csr.PublicKey, f.key)
+		f.issued = append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf}),
+			pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: f.intermediate.Raw})...)
+		order["status"] = "processing"
+		json.NewEncoder(w).Encode(order)
+	case "/order/1":
+		order["status"] = "valid"
+		order["certificate"] = f.url + "/cert"
+		json.NewEncoder(w).Encode(order)
+	case "/cert":
+		w.Write(f.issued)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// fakeKerioCertificates keeps uploaded files and certificates of the server
+type fakeKerioCertificates struct {
+	mu      sync.Mutex
+	uploads []string
+	sources map[KId]string
+	removed KIdList
+}
+
+func (f *fakeKerioCertificates) handlers() map[string]fakeHandler {
+	return map[string]fakeHandler{
+		"Init.getHostname": result(map[string]interface{}{"hostname": "mail.example.com"}),
+		"upload": func(params json.RawMessage) interface{} {
+			var content string
+			json.Unmarshal(params, &content)
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.uploads = append(f.uploads, content)
+			return map[string]interface{}{"fileUpload": map[string]string{"id": fmt.Sprintf("file%d", len(f.uploads))}}
+		},
+		"Certificates.importPrivateKey":  result(map[string]interface{}{"keyId": "key1", "needPassword": false}),
+		"Certificates.importCertificate": result(map[string]interface{}{"id": "new"}),
+		"ConnectCertificate.setActive":   result(map[string]interface{}{}),
+		"Certificates.get": func(json.RawMessage) interface{} {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.sources["new"] = f.uploads[len(f.uploads)-1]
+			return map[string]interface{}{"certificates": CertificateList{
+				{Id: "new", Name: "new", Type: ActiveCertificate},
+				{Id: "old", Name: "old", Type: InactiveCertificate},
+				{Id: "other", Name: "other", Type: InactiveCertificate},
+			}}
+		},
+		"Certificates.toSource": func(params json.RawMessage) interface{} {
+			request := struct {
+				Id KId `json:"id"`
+			}{}
+			json.Unmarshal(params, &request)
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			return map[string]interface{}{"source": f.sources[request.Id]}
+		},
+		"Certificates.remove": func(params json.RawMessage) interface{} {
+			request := struct {
+				Ids KIdList `json:"ids"`
+			}{}
+			json.Unmarshal(params, &request)
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.removed = append(f.removed, request.Ids...)
+			return map[string]interface{}{"errors": ErrorList{}}
+		},
+	}
+}
+
+func TestRotateAcmeCertificate(t *testing.T) {
+	root, rootKey, _ := testCertificate(t, "Test Root", time.Now().AddDate(10, 0, 0), nil, nil)
+	intermediate, intermediateKey, _ := testCertificate(t, "Test Intermediate", time.Now().AddDate(5, 0, 0), root, rootKey)
+	_, _, oldSource := testCertificate(t, "mail.example.com", time.Now().AddDate(0, 0, 5), intermediate, intermediateKey, "mail.example.com")
+	_, _, otherSource := testCertificate(t, "other.example.com", time.Now().AddDate(0, 0, 5), intermediate, intermediateKey, "other.example.com")
+	solver := NewHttpChallengeSolver()
+	solverServer := httptest.NewServer(solver)
+	defer solverServer.Close()
+	acme := &fakeAcme{solverUrl: solverServer.URL, intermediate: intermediate, key: intermediateKey}
+	acmeServer := httptest.NewServer(acme)
+	defer acmeServer.Close()
+	acme.url = acmeServer.URL
+	kerio := &fakeKerioCertificates{sources: map[KId]string{"old": oldSource, "other": otherSource}}
+	conn, fake := newFakeConnection(t, kerio.handlers())
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	result, err := conn.RotateAcmeCertificate(context.Background(),
+		&AcmeOptions{DirectoryUrl: acmeServer.URL + "/dir", Solver: solver, PollInterval: time.Millisecond},
+		&CertificateRotationOptions{Roots: roots})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Id != "new" || result.Hostname != "mail.example.com" || !strings.HasPrefix(result.Name, "mail.example.com 20") {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if len(kerio.uploads) != 2 || !strings.Contains(kerio.uploads[0], "EC PRIVATE KEY") || strings.Count(kerio.uploads[1], "BEGIN CERTIFICATE") != 2 {
+		t.Errorf("unexpected uploads %q", kerio.uploads)
+	}
+	if fake.called("ConnectCertificate.setActive") != 1 || fmt.Sprint(kerio.removed) != "[old]" || fmt.Sprint(result.Removed) != "[old]" {
+		t.Errorf("unexpected removal %v", kerio.removed)
+	}
+}
+
+func TestRotateCertificateVerification(t *testing.T) {
+	root, rootKey, rootSource := testCertificate(t, "Test Root", time.Now().AddDate(10, 0, 0), nil, nil)
+	_, _, source := testCertificate(t, "other.example.com", time.Now().AddDate(1, 0, 0), root, rootKey, "other.example.com")
+	_, key, _ := testCertificate(t, "key", time.Now().AddDate(1, 0, 0), root, rootKey, "key.example.com")
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	conn, fake := newFakeConnection(t, (&fakeKerioCertificates{}).handlers())
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	_, err = conn.RotateCertificate([]byte(source), keyPEM, nil, &CertificateRotationOptions{Roots: roots})
+	if err == nil || !strings.Contains(err.Error(), "not mail.example.com") {
+		t.Errorf("unexpected error %v", err)
+	}
+	_, err = conn.RotateCertificate([]byte(source), keyPEM, []byte(rootSource), &CertificateRotationOptions{Hostname: "other.example.com", Roots: roots})
+	if err == nil || !strings.Contains(err.Error(), "private key does not match") {
+		t.Errorf("unexpected error %v", err)
+	}
+	if fake.called("upload") != 0 {
+		t.Errorf("certificate uploaded")
+	}
+}
@@ -1,6 +1,6 @@
 package connect
 
-import "encoding/json"
+import "time"
 
 // ValidType - Certificate Time properties info
 type ValidType string
@@ -21,6 +21,18 @@ type ValidPeriod struct {
 	ValidType     ValidType `json:"validType"`
 }
 
+// ValidFrom - Return start of the validity
+//	location - time zone of the server; nil means UTC
+func (p ValidPeriod) ValidFrom(location *time.Location) time.Time {
+	return dateTime(p.ValidFromDate, p.ValidFromTime, location)
+}
+
+// ValidTo - Return end of the validity
+//	location - time zone of the server; nil means UTC
+func (p ValidPeriod) ValidTo(location *time.Location) time.Time {
+	return dateTime(p.ValidToDate, p.ValidToTime, location)
+}
+
 type CertificateType string
 
 const (
@@ -85,7 +97,7 @@ func (s *ServerConnection) CertificatesGet(query SearchQuery) (CertificateList,
 			TotalItems   int             `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &certificates)
+	err = s.unmarshal(data, &certificates)
 	return certificates.Result.Certificates, certificates.Result.TotalItems, err
 }
 
@@ -118,7 +130,7 @@ func (s *ServerConnection) CertificatesRemove(ids KIdList) (ErrorList, error) {
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -145,7 +157,7 @@ func (s *ServerConnection) CertificatesGenerate(subject NamedValueList, name str
 			Id KId `json:"id"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &id)
+	err = s.unmarshal(data, &id)
 	return id.Result.Id, err
 }
 
@@ -162,7 +174,7 @@ func (s *ServerConnection) CertificatesGetCountryList() (NamedValueList, error)
 			Countries NamedValueList `json:"countries"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &countries)
+	err = s.unmarshal(data, &countries)
 	return countries.Result.Countries, err
 }
 
@@ -189,7 +201,7 @@ func (s *ServerConnection) CertificatesImportCertificate(keyId KId, fileId strin
 			Id KId `json:"id"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &id)
+	err = s.unmarshal(data, &id)
 	return id.Result.Id, err
 }
 
@@ -212,7 +224,7 @@ func (s *ServerConnection) CertificatesImportPrivateKey(fileId string) (KId, boo
 			NeedPassword bool `json:"needPassword"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &keyId)
+	err = s.unmarshal(data, &keyId)
 	return keyId.Result.KeyId, keyId.Result.NeedPassword, err
 }
 
@@ -245,7 +257,7 @@ func (s *ServerConnection) CertificatesExportCertificate(id KId) (*Download, err
 			FileDownload Download `json:"fileDownload"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &fileDownload)
+	err = s.unmarshal(data, &fileDownload)
 	return &fileDownload.Result.FileDownload, err
 }
 
@@ -266,7 +278,7 @@ func (s *ServerConnection) CertificatesExportPrivateKey(id KId) (*Download, erro
 			FileDownload Download `json:"fileDownload"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &fileDownload)
+	err = s.unmarshal(data, &fileDownload)
 	return &fileDownload.Result.FileDownload, err
 }
 
@@ -287,6 +299,6 @@ func (s *ServerConnection) CertificatesToSource(id KId) (string, error) {
 			Source string `json:"source"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &source)
+	err = s.unmarshal(data, &source)
 	return source.Result.Source, err
 }
@@ -0,0 +1,242 @@
+package connect
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// minChartTimestamp - X values from this Unix time on are taken as timestamps, smaller ones as sample indexes
+const minChartTimestamp = 946684800 // 2000-01-01
+
+// ChartScale - Chart with one of its scales
+type ChartScale struct {
+	Chart Chart `json:"chart"`
+	Scale Scale `json:"scale"`
+}
+
+// ChartScaleList - Charts with scales
+type ChartScaleList []ChartScale
+
+// Title - Name of the chart and the time scale, e.g. "traffic (1d)"
+func (c ChartScale) Title() string {
+	return fmt.Sprintf("%s (%s)", c.Chart.Name, formatAge(time.Duration(c.Scale.ScaleTime)*time.Second))
+}
+
+// ChartScales - Enumerate every chart and each of its scales.
+// Return
+//	scales - all charts with scales
+func (s *ServerConnection) ChartScales() (ChartScaleList, error) {
+	charts, err := s.StatisticsGetCharts()
+	if err != nil {
+		return nil, err
+	}
+	var scales ChartScaleList
+	for _, chart := range charts {
+		for _, scale := range chart.Scale {
+			scales = append(scales, ChartScale{Chart: chart, Scale: scale})
+		}
+	}
+	return scales, nil
+}
+
+// ChartRow - Named values of one line of the chart
+type ChartRow struct {
+	Name   string         `json:"name"`
+	Values ChartValueList `json:"values"`
+}
+
+// ChartRowList - Lines of the chart
+type ChartRowList []ChartRow
+
+// ChartTimeSeries - Chart data with timestamps
+type ChartTimeSeries struct {
+	ChartScale
+	XName string       `json:"xName"`
+	Times []time.Time  `json:"times"` // time of each sample
+	Rows  ChartRowList `json:"rows"`  // values of rows, one value for each time
+}
+
+// ChartTimeSeriesList - Time series of several charts
+type ChartTimeSeriesList []*ChartTimeSeries
+
+// NewChartTimeSeries - Convert raw chart data to time series.
+// X values are used as Unix timestamps when they look like ones, otherwise the last sample is taken at now
+// and each previous one SampleTime of the scale earlier.
+//	scale - chart and scale the data belongs to
+//	data - data returned by StatisticsGetChartData
+//	now - time the data was read
+// Return
+//	series - labeled rows with timestamps
+func NewChartTimeSeries(scale ChartScale, data *ChartData, now time.Time) *ChartTimeSeries {
+	count := len(data.XValues)
+	for _, values := range data.RowValues {
+		if len(values) > count {
+			count = len(values)
+		}
+	}
+	series := &ChartTimeSeries{ChartScale: scale, XName: data.XName, Times: make([]time.Time, count)}
+	timestamps := len(data.XValues) == count && count > 0
+	for _, x := range data.XValues {
+		if x < minChartTimestamp {
+			timestamps = false
+		}
+	}
+	step := time.Duration(scale.Scale.SampleTime) * time.Second
+	for i := range series.Times {
+		if timestamps {
+			series.Times[i] = time.Unix(int64(data.XValues[i]), 0).UTC()
+		} else {
+			series.Times[i] = now.Add(-time.Duration(count-1-i) * step).Truncate(time.Second)
+		}
+	}
+	for i, values := range data.RowValues {
+		row := ChartRow{Name: fmt.Sprintf("row %d", i+1), Values: make(ChartValueList, count)}
+		if i < len(data.RowNames) {
+			row.Name = data.RowNames[i]
+		}
+		// shorter rows are aligned to the latest sample
+		copy(row.Values[count-len(values):], values)
+		series.Rows = append(series.Rows, row)
+	}
+	return series
+}
+
+// GetChartTimeSeries - Read data of the chart scale as time series
+func (s *ServerConnection) GetChartTimeSeries(scale ChartScale) (*ChartTimeSeries, error) {
+	data, err := s.StatisticsGetChartData(scale.Chart.Classname, scale.Chart.Name, scale.Scale.Id)
+	if err != nil {
+		return nil, err
+	}
+	return NewChartTimeSeries(scale, data, time.Now()), nil
+}
+
+// GetAllChartTimeSeries - Read data of every chart and scale
+func (s *ServerConnection) GetAllChartTimeSeries() (ChartTimeSeriesList, error) {
+	scales, err := s.ChartScales()
+	if err != nil {
+		return nil, err
+	}
+	list := make(ChartTimeSeriesList, 0, len(scales))
+	for _, scale := range scales {
+		series, err := s.GetChartTimeSeries(scale)
+		if err != nil {
+			return nil, fmt.Errorf("chart %s: %v", scale.Title(), err)
+		}
+		list = append(list, series)
+	}
+	return list, nil
+}
+
+// WriteChartCsv - Write time series as CSV with header 'Time' followed by names of rows, times are in RFC 3339
+func WriteChartCsv(w io.Writer, series *ChartTimeSeries) error {
+	writer := csv.NewWriter(w)
+	header := []string{"Time"}
+	for _, row := range series.Rows {
+		header = append(header, row.Name)
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for i, t := range series.Times {
+		record := []string{t.Format(time.RFC3339)}
+		for _, row := range series.Rows {
+			record = append(record, strconv.Itoa(row.Values[i]))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+var chartColors = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b", "#e377c2", "#7f7f7f"}
+
+const (
+	chartWidth   = 720
+	chartHeight  = 240
+	chartMarginX = 60
+	chartMarginY = 20
+)
+
+// ChartSvg - Render time series as self-contained SVG line chart
+func ChartSvg(series *ChartTimeSeries) template.HTML {
+	max := 0
+	for _, row := range series.Rows {
+		for _, value := range row.Values {
+			if value > max {
+				max = value
+			}
+		}
+	}
+	if max == 0 {
+		max = 1
+	}
+	plotWidth := float64(chartWidth - 2*chartMarginX)
+	plotHeight := float64(chartHeight - 2*chartMarginY)
+	x := func(i int) float64 {
+		if len(series.Times) < 2 {
+			return chartMarginX
+		}
+		return chartMarginX + plotWidth*float64(i)/float64(len(series.Times)-1)
+	}
+	y := func(value int) float64 {
+		return chartMarginY + plotHeight*(1-float64(value)/float64(max))
+	}
+	b := &strings.Builder{}
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`,
+		chartWidth, chartHeight+20*((len(series.Rows)+3)/4), chartWidth, chartHeight+20*((len(series.Rows)+3)/4))
+	fmt.Fprintf(b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#999"/>`, chartMarginX, chartHeight-chartMarginY, chartWidth-chartMarginX, chartHeight-chartMarginY)
+	fmt.Fprintf(b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#999"/>`, chartMarginX, chartMarginY, chartMarginX, chartHeight-chartMarginY)
+	fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="end">%d</text>`, chartMarginX-4, chartMarginY+4, max)
+	fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="end">0</text>`, chartMarginX-4, chartHeight-chartMarginY)
+	if len(series.Times) > 0 {
+		fmt.Fprintf(b, `<text x="%d" y="%d">%s</text>`, chartMarginX, chartHeight-4, template.HTMLEscapeString(series.Times[0].Format("2006-01-02 15:04")))
+		fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="end">%s</text>`, chartWidth-chartMarginX, chartHeight-4,
+			template.HTMLEscapeString(series.Times[len(series.Times)-1].Format("2006-01-02 15:04")))
+	}
+	for i, row := range series.Rows {
+		color := chartColors[i%len(chartColors)]
+		points := make([]string, len(row.Values))
+		for j, value := range row.Values {
+			points[j] = fmt.Sprintf("%.1f,%.1f", x(j), y(value))
+		}
+		fmt.Fprintf(b, `<polyline fill="none" stroke="%s" stroke-width="1.5" points="%s"/>`, color, strings.Join(points, " "))
+		legendX := chartMarginX + (i%4)*(chartWidth-2*chartMarginX)/4
+		legendY := chartHeight + 20*(i/4) + 4
+		fmt.Fprintf(b, `<rect x="%d" y="%d" width="10" height="10" fill="%s"/>`, legendX, legendY, color)
+		fmt.Fprintf(b, `<text x="%d" y="%d">%s</text>`, legendX+14, legendY+9, template.HTMLEscapeString(row.Name))
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+var chartsHtml = template.Must(template.New("charts").Funcs(template.FuncMap{"svg": ChartSvg}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>body{font-family:sans-serif;margin:2em}h2{font-size:1.1em;margin-top:2em}</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>Generated {{.Generated.Format "2006-01-02 15:04 MST"}}</p>
+{{range .Charts}}<h2>{{.Title}}</h2>
+{{svg .}}
+{{end}}</body>
+</html>
+`))
+
+// WriteChartsHtml - Write self-contained HTML page with SVG chart of each time series
+func WriteChartsHtml(w io.Writer, title string, list ChartTimeSeriesList) error {
+	return chartsHtml.Execute(w, struct {
+		Title     string
+		Generated time.Time
+		Charts    ChartTimeSeriesList
+	}{title, time.Now(), list})
+}
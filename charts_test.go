@@ -0,0 +1,70 @@
+package connect
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewChartTimeSeries(t *testing.T) {
+	scale := ChartScale{Chart: Chart{Name: "traffic"}, Scale: Scale{Id: 1, ScaleTime: 7200, SampleTime: 20}}
+	now := time.Date(2021, 1, 28, 12, 0, 0, 0, time.UTC)
+	series := NewChartTimeSeries(scale, &ChartData{XValues: ChartValueList{0, 1, 2}, RowNames: ChartRowNamesList{"in"},
+		RowValues: ChartRowValuesList{{1, 2, 3}, {5, 6}}}, now)
+	if len(series.Times) != 3 || !series.Times[0].Equal(now.Add(-40*time.Second)) || !series.Times[2].Equal(now) {
+		t.Errorf("unexpected reconstructed times %v", series.Times)
+	}
+	if len(series.Rows) != 2 || series.Rows[0].Name != "in" || series.Rows[1].Name != "row 2" || series.Rows[1].Values[0] != 0 || series.Rows[1].Values[2] != 6 {
+		t.Errorf("unexpected rows %+v", series.Rows)
+	}
+	series = NewChartTimeSeries(scale, &ChartData{XValues: ChartValueList{1611835200, 1611835220}, RowValues: ChartRowValuesList{{1, 2}}}, now)
+	if !series.Times[1].Equal(time.Unix(1611835220, 0)) {
+		t.Errorf("X values not used as timestamps %v", series.Times)
+	}
+	if scale.Title() != "traffic (2h)" {
+		t.Errorf("unexpected title %q", scale.Title())
+	}
+}
+
+func TestGetAllChartTimeSeries(t *testing.T) {
+	conn, fake := newFakeConnection(t, map[string]fakeHandler{
+		"Statistics.getCharts": result(map[string]interface{}{"chartList": ChartList{
+			{Classname: "mail", Name: "received", Scale: ScaleList{{Id: 0, ScaleTime: 7200, SampleTime: 20}, {Id: 1, ScaleTime: 86400, SampleTime: 300}}},
+			{Classname: "mail", Name: "<sent>", Scale: ScaleList{{Id: 0, ScaleTime: 7200, SampleTime: 20}}},
+		}}),
+		"Statistics.getChartData": func(params json.RawMessage) interface{} {
+			return map[string]interface{}{"chartData": ChartData{XValues: ChartValueList{0, 1}, RowNames: ChartRowNamesList{"smtp & pop3"},
+				RowValues: ChartRowValuesList{{3, 4}}}}
+		},
+	})
+	list, err := conn.GetAllChartTimeSeries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 3 || fake.called("Statistics.getChartData") != 3 || list[1].Scale.Id != 1 {
+		t.Fatalf("unexpected series %+v", list)
+	}
+	buffer := &bytes.Buffer{}
+	if err = WriteChartCsv(buffer, list[0]); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 3 || lines[0] != "Time,smtp & pop3" || !strings.HasSuffix(lines[2], "Z,4") {
+		t.Errorf("unexpected CSV %q", buffer.String())
+	}
+	buffer.Reset()
+	if err = WriteChartsHtml(buffer, "Weekly <report>", list); err != nil {
+		t.Fatal(err)
+	}
+	page := buffer.String()
+	for _, text := range []string{"<title>Weekly &lt;report&gt;</title>", "<h2>received (1d)</h2>", "<h2>&lt;sent&gt; (2h)</h2>", "<polyline", "smtp &amp; pop3"} {
+		if !strings.Contains(page, text) {
+			t.Errorf("page does not contain %q", text)
+		}
+	}
+	if strings.Count(page, "<svg") != 3 {
+		t.Errorf("unexpected count of charts in %s", page)
+	}
+}
@@ -0,0 +1,431 @@
+package connect
+
+import (
+	"fmt"
+	"strings"
+)
+
+const DefaultClusterRenameSuffix = "-local" // appended to names of renamed conflicting objects
+
+// ClusterResolutionAction - How to resolve a conflict of a local object with an object of the cluster
+type ClusterResolutionAction string
+
+const (
+	ClusterRename   ClusterResolutionAction = "ClusterRename"   // rename the local alias to NewName
+	ClusterRecreate ClusterResolutionAction = "ClusterRecreate" // create resource or mailing list NewName and remove the local one; bookings or ML archive are lost
+	ClusterRemove   ClusterResolutionAction = "ClusterRemove"   // remove the local object, the object of the cluster stays
+	ClusterMerge    ClusterResolutionAction = "ClusterMerge"    // add members of the local mailing list to the list of the cluster and remove the local one
+	ClusterManual   ClusterResolutionAction = "ClusterManual"   // must be resolved by administrator, e.g. domain alias; the plan cannot be applied
+)
+
+// ClusterResolution - Planned resolution of one conflict
+type ClusterResolution struct {
+	Conflict    ClusterConflict         `json:"conflict"`
+	Action      ClusterResolutionAction `json:"action"`
+	NewName     string                  `json:"newName,omitempty"` // new name for ClusterRename and ClusterRecreate
+	Description string                  `json:"description"`       // human readable description
+	domain      Domain                  // local domain of the object
+	masterId    KId                     // domain of the object on the master
+	resource    Resource
+	alias       Alias
+	ml          Ml
+	masterMl    Ml
+}
+
+// ClusterResolutionList - List of planned resolutions in order of conflicts
+type ClusterResolutionList []ClusterResolution
+
+// ClusterJoinOptions - Options of PlanClusterJoin
+type ClusterJoinOptions struct {
+	RenameSuffix string // appended to names of renamed objects; DefaultClusterRenameSuffix if empty
+}
+
+// ClusterJoinPlan - Checks and resolutions before connecting the server to the cluster. Apply it with ApplyClusterJoin of the same connection.
+type ClusterJoinPlan struct {
+	HostName      string                `json:"hostName"`      // master server
+	Distributable StringList            `json:"distributable"` // domains distributed by the master
+	Domains       StringList            `json:"domains"`       // local domains which become distributed
+	Conflicts     ClusterConflictList   `json:"conflicts"`     // local objects whose names are used in the cluster
+	Resolutions   ClusterResolutionList `json:"resolutions"`   // one resolution per conflict
+	auth          ClusterAuthentication
+	master        *ServerConnection
+	options       ClusterJoinOptions
+}
+
+// ClusterJoinResult - Result of applied plan
+type ClusterJoinResult struct {
+	Done       StringList    `json:"done"`       // descriptions of applied resolutions and steps
+	Errors     ErrorList     `json:"errors"`     // errors returned by the server
+	Connect    *ClusterError `json:"connect"`    // result of DistributedDomainConnect, nil if not called
+	RolledBack bool          `json:"rolledBack"` // server was disconnected because post-checks failed
+}
+
+// PlanClusterJoin - Check that the server can join the cluster and find conflicts without connecting.
+// Resources, aliases and mailing lists of the distributed domains are compared with the master by name
+// and a resolution is proposed for each conflict: mailing lists are merged, aliases with the same target are removed
+// and other aliases are renamed. Names of resources and mailing lists cannot be changed, so other conflicts of them
+// are left to the administrator as ClusterManual. Change proposals by SetResolution.
+//	master - logged in connection to the master server
+//	authentication - credentials used by DistributedDomainConnect
+//	options - nil means defaults
+// Return
+//	plan - checks and resolutions
+func (s *ServerConnection) PlanClusterJoin(master *ServerConnection, authentication ClusterAuthentication, options *ClusterJoinOptions) (*ClusterJoinPlan, error) {
+	plan := &ClusterJoinPlan{HostName: authentication.HostName, Domains: StringList{}, Conflicts: ClusterConflictList{},
+		Resolutions: ClusterResolutionList{}, auth: authentication, master: master}
+	if options != nil {
+		plan.options = *options
+	}
+	if plan.options.RenameSuffix == "" {
+		plan.options.RenameSuffix = DefaultClusterRenameSuffix
+	}
+	role, _, err := s.DistributedDomainGetRole()
+	if err != nil {
+		return nil, err
+	}
+	if *role != ClStandalone {
+		return nil, fmt.Errorf("server is already in cluster as %s", strings.TrimPrefix(string(*role), "cl"))
+	}
+	if plan.Distributable, err = s.DistributedDomainGetDistributable(authentication, false); err != nil {
+		return nil, err
+	}
+	if len(plan.Distributable) == 0 {
+		return nil, fmt.Errorf("master %s has no distributable domain", authentication.HostName)
+	}
+	domains, _, err := s.DomainsGet(SearchQuery{})
+	if err != nil {
+		return nil, err
+	}
+	masterDomains, _, err := master.DomainsGet(SearchQuery{})
+	if err != nil {
+		return nil, err
+	}
+	for _, domain := range domains {
+		if !containsFold(plan.Distributable, domain.Name) {
+			continue
+		}
+		plan.Domains = append(plan.Domains, domain.Name)
+		for _, masterDomain := range masterDomains {
+			if strings.EqualFold(masterDomain.Name, domain.Name) {
+				if err = s.planClusterConflicts(plan, domain, masterDomain); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return plan, nil
+}
+
+// planClusterConflicts compares objects of the local domain with the domain of the master
+func (s *ServerConnection) planClusterConflicts(plan *ClusterJoinPlan, domain, masterDomain Domain) error {
+	masterResources, _, err := plan.master.ResourcesGet(SearchQuery{}, masterDomain.Id)
+	if err != nil {
+		return err
+	}
+	masterAliases, _, err := plan.master.AliasesGet(SearchQuery{}, masterDomain.Id)
+	if err != nil {
+		return err
+	}
+	masterMls, _, err := plan.master.MailingListsGet(SearchQuery{}, masterDomain.Id)
+	if err != nil {
+		return err
+	}
+	used := map[string]bool{}
+	for _, resource := range masterResources {
+		used[strings.ToLower(resource.Name)] = true
+	}
+	masterAliasTargets := map[string]string{}
+	for _, alias := range masterAliases {
+		used[strings.ToLower(alias.Name)] = true
+		masterAliasTargets[strings.ToLower(alias.Name)] = strings.ToLower(alias.DeliverTo)
+	}
+	byName := map[string]Ml{}
+	for _, ml := range masterMls {
+		used[strings.ToLower(ml.Name)] = true
+		byName[strings.ToLower(ml.Name)] = ml
+	}
+	add := func(resolution ClusterResolution) {
+		resolution.domain = domain
+		resolution.masterId = masterDomain.Id
+		resolution.Conflict.Domain = domain.Name
+		resolution.Conflict.HomeServer = plan.HostName
+		if resolution.Action == ClusterRename {
+			resolution.NewName = resolution.Conflict.Name + plan.options.RenameSuffix
+		}
+		resolution.Description = resolution.describe()
+		plan.Conflicts = append(plan.Conflicts, resolution.Conflict)
+		plan.Resolutions = append(plan.Resolutions, resolution)
+	}
+	resources, _, err := s.ResourcesGet(SearchQuery{}, domain.Id)
+	if err != nil {
+		return err
+	}
+	for _, resource := range resources {
+		if used[strings.ToLower(resource.Name)] {
+			add(ClusterResolution{Conflict: ClusterConflict{Type: ClResource, Name: resource.Name}, Action: ClusterManual, resource: resource})
+		}
+	}
+	aliases, _, err := s.AliasesGet(SearchQuery{}, domain.Id)
+	if err != nil {
+		return err
+	}
+	for _, alias := range aliases {
+		name := strings.ToLower(alias.Name)
+		if !used[name] {
+			continue
+		}
+		action := ClusterRename
+		if target, ok := masterAliasTargets[name]; ok && target == strings.ToLower(alias.DeliverTo) {
+			action = ClusterRemove
+		}
+		add(ClusterResolution{Conflict: ClusterConflict{Type: ClAlias, Name: alias.Name}, Action: action, alias: alias})
+	}
+	mls, _, err := s.MailingListsGet(SearchQuery{}, domain.Id)
+	if err != nil {
+		return err
+	}
+	for _, ml := range mls {
+		if !used[strings.ToLower(ml.Name)] {
+			continue
+		}
+		action := ClusterManual
+		masterMl, ok := byName[strings.ToLower(ml.Name)]
+		if ok {
+			action = ClusterMerge
+		}
+		add(ClusterResolution{Conflict: ClusterConflict{Type: ClMailingList, Name: ml.Name}, Action: action, ml: ml, masterMl: masterMl})
+	}
+	return nil
+}
+
+// describe returns human readable description of the resolution
+func (r *ClusterResolution) describe() string {
+	kind := map[ClusterConflictTarget]string{ClResource: "resource", ClAlias: "alias", ClMailingList: "mailing list",
+		ClDomainAlias: "domain alias", ClDomain: "domain"}[r.Conflict.Type]
+	name := r.Conflict.Name + "@" + r.Conflict.Domain
+	switch r.Action {
+	case ClusterRename:
+		return fmt.Sprintf("rename %s %s to %s", kind, name, r.NewName)
+	case ClusterRecreate:
+		lost := "existing bookings are lost"
+		if r.Conflict.Type == ClMailingList {
+			lost = "members are copied, the archive is lost"
+		}
+		return fmt.Sprintf("recreate %s %s as %s; %s", kind, name, r.NewName, lost)
+	case ClusterRemove:
+		return fmt.Sprintf("remove %s %s", kind, name)
+	case ClusterMerge:
+		return fmt.Sprintf("merge members of %s %s into the list of the cluster", kind, name)
+	}
+	return fmt.Sprintf("resolve %s %s manually", kind, name)
+}
+
+// SetResolution - Change the proposed resolution of the conflict.
+//	conflict - one of Conflicts of the plan
+//	action - new resolution; ClusterMerge is possible for mailing lists existing on the master only,
+//	  ClusterRename for aliases and ClusterRecreate for resources and mailing lists
+//	newName - new name of the local object for ClusterRename and ClusterRecreate
+func (p *ClusterJoinPlan) SetResolution(conflict ClusterConflict, action ClusterResolutionAction, newName string) error {
+	for i := range p.Resolutions {
+		r := &p.Resolutions[i]
+		if r.Conflict != conflict {
+			continue
+		}
+		switch {
+		case (action == ClusterRename || action == ClusterRecreate) && newName == "":
+			return fmt.Errorf("%s: new name is required", r.Description)
+		case action == ClusterRename && r.Conflict.Type != ClAlias:
+			return fmt.Errorf("%s: name cannot be changed, the object can be recreated by ClusterRecreate", r.Description)
+		case action == ClusterRecreate && r.Conflict.Type != ClResource && r.Conflict.Type != ClMailingList:
+			return fmt.Errorf("%s: only resources and mailing lists are recreated", r.Description)
+		case action == ClusterMerge && (r.Conflict.Type != ClMailingList || r.masterMl.Id == ""):
+			return fmt.Errorf("%s: only mailing lists existing on the master can be merged", r.Description)
+		case action != ClusterManual && r.Conflict.Type != ClResource && r.Conflict.Type != ClAlias && r.Conflict.Type != ClMailingList:
+			return fmt.Errorf("%s: cannot be resolved automatically", r.Description)
+		}
+		r.Action = action
+		r.NewName = ""
+		if action == ClusterRename || action == ClusterRecreate {
+			r.NewName = newName
+		}
+		r.Description = r.describe()
+		return nil
+	}
+	return fmt.Errorf("conflict %s %s@%s is not in the plan", conflict.Type, conflict.Name, conflict.Domain)
+}
+
+// ApplyClusterJoin - Apply resolutions of the plan, connect the server to the cluster as slave and verify it.
+// If the server is not in cluster without error after connecting, it is disconnected again. Applied resolutions are not reverted.
+//	plan - plan from PlanClusterJoin
+// Return
+//	result - applied steps; result.Connect contains conflicts reported by the server if they were not all found by the plan
+func (s *ServerConnection) ApplyClusterJoin(plan *ClusterJoinPlan) (*ClusterJoinResult, error) {
+	result := &ClusterJoinResult{Done: StringList{}, Errors: ErrorList{}}
+	for _, resolution := range plan.Resolutions {
+		if resolution.Action == ClusterManual || resolution.Action == "" {
+			return result, fmt.Errorf("%s: conflict is not resolved", resolution.Description)
+		}
+	}
+	for _, resolution := range plan.Resolutions {
+		errors, err := s.applyClusterResolution(plan, resolution)
+		if err != nil {
+			return result, fmt.Errorf("%s: %v", resolution.Description, err)
+		}
+		result.Errors = append(result.Errors, errors...)
+		if len(errors) > 0 {
+			return result, fmt.Errorf("%s: %s", resolution.Description, errors[0].Message)
+		}
+		result.Done = append(result.Done, resolution.Description)
+	}
+	var err error
+	result.Connect, err = s.DistributedDomainConnect(plan.auth.HostName, plan.auth.AdminUser, plan.auth.Password)
+	if err != nil {
+		return result, err
+	}
+	if result.Connect.Type != ClSuccess {
+		message := formatMessage(result.Connect.ErrorMessage.Message, result.Connect.ErrorMessage.PositionalParameters)
+		if result.Connect.Type == ClDataConflict {
+			message = fmt.Sprintf("%d conflicts remain", len(result.Connect.ConflictList))
+		}
+		return result, fmt.Errorf("cannot connect to %s: %s %s", plan.auth.HostName, result.Connect.Type, message)
+	}
+	result.Done = append(result.Done, "connect to "+plan.auth.HostName)
+	if err = s.verifyClusterJoin(); err == nil {
+		result.Done = append(result.Done, "verify cluster status")
+		return result, nil
+	}
+	if disconnectErr := s.DistributedDomainDisconnect(); disconnectErr != nil {
+		return result, fmt.Errorf("%v; disconnect failed: %v", err, disconnectErr)
+	}
+	result.RolledBack = true
+	return result, fmt.Errorf("%v; server was disconnected", err)
+}
+
+// verifyClusterJoin checks that the server is slave in cluster without error
+func (s *ServerConnection) verifyClusterJoin() error {
+	isInCluster, isError, err := s.DistributedDomainGetStatus()
+	if err != nil {
+		return err
+	}
+	if !isInCluster || isError {
+		return fmt.Errorf("post-check failed: in cluster %t, error %t", isInCluster, isError)
+	}
+	role, _, err := s.DistributedDomainGetRole()
+	if err != nil {
+		return err
+	}
+	if *role != ClSlave {
+		return fmt.Errorf("post-check failed: role is %s", *role)
+	}
+	return nil
+}
+
+func (s *ServerConnection) applyClusterResolution(plan *ClusterJoinPlan, r ClusterResolution) (ErrorList, error) {
+	switch {
+	case r.Conflict.Type == ClResource && r.Action == ClusterRecreate:
+		// name of resource is write-once, the resource is recreated
+		pattern := r.resource
+		pattern.Id = ""
+		pattern.Name = r.NewName
+		errors, _, err := s.ResourcesCreate(ResourceList{pattern})
+		if err != nil || len(errors) > 0 {
+			return errors, err
+		}
+		return s.ResourcesRemove(KIdList{r.resource.Id})
+	case r.Conflict.Type == ClResource && r.Action == ClusterRemove:
+		return s.ResourcesRemove(KIdList{r.resource.Id})
+	case r.Conflict.Type == ClAlias && r.Action == ClusterRename:
+		pattern := r.alias
+		pattern.Name = r.NewName
+		return s.AliasesSet(KIdList{r.alias.Id}, pattern)
+	case r.Conflict.Type == ClAlias && r.Action == ClusterRemove:
+		return s.AliasesRemove(KIdList{r.alias.Id})
+	case r.Conflict.Type == ClMailingList && r.Action == ClusterRecreate:
+		// name of mailing list is write-once, the list is recreated with its members
+		members, _, err := s.MailingListsGetMlUserList(SearchQuery{}, r.ml.Id)
+		if err != nil {
+			return nil, err
+		}
+		pattern := r.ml
+		pattern.Id = ""
+		pattern.Name = r.NewName
+		errors, created, err := s.MailingListsCreate(MlList{pattern})
+		if err != nil || len(errors) > 0 {
+			return errors, err
+		}
+		if len(created) != 1 {
+			return nil, fmt.Errorf("server did not return ID of the new mailing list")
+		}
+		if len(members) > 0 {
+			if errors, err = s.MailingListsAddMlUserList(members, created[0].Id); err != nil || len(errors) > 0 {
+				return errors, err
+			}
+		}
+		return s.MailingListsRemove(KIdList{r.ml.Id})
+	case r.Conflict.Type == ClMailingList && r.Action == ClusterRemove:
+		return s.MailingListsRemove(KIdList{r.ml.Id})
+	case r.Conflict.Type == ClMailingList && r.Action == ClusterMerge:
+		members, err := s.clusterMlMembers(plan, r)
+		if err != nil {
+			return nil, err
+		}
+		if len(members) > 0 {
+			errors, err := plan.master.MailingListsAddMlUserList(members, r.masterMl.Id)
+			if err != nil || len(errors) > 0 {
+				return errors, err
+			}
+		}
+		return s.MailingListsRemove(KIdList{r.ml.Id})
+	}
+	return nil, fmt.Errorf("unsupported resolution %s", r.Action)
+}
+
+// clusterMlMembers returns members of the local mailing list missing in the list of the master.
+// Local users are replaced by users of the master with the same login name or by their addresses.
+func (s *ServerConnection) clusterMlMembers(plan *ClusterJoinPlan, r ClusterResolution) (UserOrEmailList, error) {
+	members, _, err := s.MailingListsGetMlUserList(SearchQuery{}, r.ml.Id)
+	if err != nil {
+		return nil, err
+	}
+	existing, _, err := plan.master.MailingListsGetMlUserList(SearchQuery{}, r.masterMl.Id)
+	if err != nil {
+		return nil, err
+	}
+	index, err := s.newUserIndex(r.domain)
+	if err != nil {
+		return nil, err
+	}
+	masterIndex, err := plan.master.newUserIndex(Domain{Id: r.masterId, Name: r.domain.Name})
+	if err != nil {
+		return nil, err
+	}
+	known := map[string]bool{}
+	key := func(member UserOrEmail) string {
+		if member.HasId {
+			return string(member.Kind) + " " + string(member.UserId)
+		}
+		return string(member.Kind) + " " + strings.ToLower(member.EmailAddress)
+	}
+	for _, member := range existing {
+		known[key(member)] = true
+	}
+	var result UserOrEmailList
+	for _, member := range members {
+		if member.HasId {
+			user, ok := index.user(member.UserId)
+			if !ok {
+				continue
+			}
+			if masterUser, ok := masterIndex.lookup(user.LoginName); ok {
+				member.UserId = masterUser.Id
+			} else {
+				member = UserOrEmail{EmailAddress: index.primaryAddress(user), FullName: user.FullName, Kind: member.Kind}
+			}
+		}
+		if !known[key(member)] {
+			known[key(member)] = true
+			result = append(result, member)
+		}
+	}
+	return result, nil
+}
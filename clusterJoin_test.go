@@ -0,0 +1,174 @@
+package connect
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeClusterJoin holds handlers of the joining server and the master
+type fakeClusterJoin struct {
+	mu        sync.Mutex
+	connected bool
+	isError   bool
+	added     UserOrEmailList
+	renamed   string
+}
+
+func (f *fakeClusterJoin) local() map[string]fakeHandler {
+	return map[string]fakeHandler{
+		"DistributedDomain.getRole": func(json.RawMessage) interface{} {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			if f.connected {
+				return map[string]interface{}{"role": ClSlave}
+			}
+			return map[string]interface{}{"role": ClStandalone}
+		},
+		"DistributedDomain.getDistributable": result(map[string]interface{}{"domainNames": StringList{"example.com"}}),
+		"DistributedDomain.connect": func(json.RawMessage) interface{} {
+			f.mu.Lock()
+			f.connected = true
+			f.mu.Unlock()
+			return map[string]interface{}{"result": ClusterError{Type: ClSuccess}}
+		},
+		"DistributedDomain.getStatus": func(json.RawMessage) interface{} {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			return map[string]interface{}{"isInCluster": f.connected, "isError": f.isError}
+		},
+		"DistributedDomain.disconnect": func(json.RawMessage) interface{} {
+			f.mu.Lock()
+			f.connected = false
+			f.mu.Unlock()
+			return map[string]interface{}{}
+		},
+		"Domains.get": result(map[string]interface{}{"list": DomainList{{Id: "d1", Name: "example.com"}, {Id: "d2", Name: "other.example"}}}),
+		"Resources.get": result(map[string]interface{}{"list": ResourceList{
+			{Id: "r1", Name: "room"}, {Id: "r2", Name: "car"},
+		}}),
+		"Aliases.get": result(map[string]interface{}{"list": AliasList{
+			{Id: "a1", Name: "info", DeliverTo: "sales@example.com"},
+			{Id: "a2", Name: "help", DeliverTo: "support@example.com"},
+			{Id: "a3", Name: "unique", DeliverTo: "jdoe@example.com"},
+		}}),
+		"MailingLists.get": result(map[string]interface{}{"list": MlList{{Id: "l1", Name: "all"}, {Id: "l2", Name: "dev"}}}),
+		"MailingLists.getMlUserList": result(map[string]interface{}{"list": UserOrEmailList{
+			{HasId: true, UserId: "u1", Kind: Member},
+			{HasId: true, UserId: "u2", FullName: "Anna Smith", Kind: Member},
+			{EmailAddress: "ext@example.net", Kind: Member},
+		}}),
+		"Users.get": result(map[string]interface{}{"list": UserList{
+			{Id: "u1", LoginName: "jdoe"}, {Id: "u2", LoginName: "asmith", FullName: "Anna Smith"},
+		}}),
+		"Resources.create": func(params json.RawMessage) interface{} {
+			request := struct {
+				Resources ResourceList `json:"resources"`
+			}{}
+			json.Unmarshal(params, &request)
+			f.mu.Lock()
+			f.renamed = request.Resources[0].Name
+			f.mu.Unlock()
+			return map[string]interface{}{"errors": ErrorList{}, "result": CreateResultList{{Id: "r3"}}}
+		},
+		"Resources.remove":    result(map[string]interface{}{"errors": ErrorList{}}),
+		"Aliases.set":         result(map[string]interface{}{"errors": ErrorList{}}),
+		"Aliases.remove":      result(map[string]interface{}{"errors": ErrorList{}}),
+		"MailingLists.remove": result(map[string]interface{}{"errors": ErrorList{}}),
+	}
+}
+
+func (f *fakeClusterJoin) master() map[string]fakeHandler {
+	return map[string]fakeHandler{
+		"Domains.get":   result(map[string]interface{}{"list": DomainList{{Id: "m1", Name: "Example.com", IsDistributed: true}}}),
+		"Resources.get": result(map[string]interface{}{"list": ResourceList{{Id: "mr1", Name: "Room"}}}),
+		"Aliases.get": result(map[string]interface{}{"list": AliasList{
+			{Id: "ma1", Name: "info", DeliverTo: "Sales@example.com"},
+			{Id: "ma2", Name: "help", DeliverTo: "helpdesk@example.com"},
+		}}),
+		"MailingLists.get":           result(map[string]interface{}{"list": MlList{{Id: "ml1", Name: "all"}}}),
+		"MailingLists.getMlUserList": result(map[string]interface{}{"list": UserOrEmailList{{EmailAddress: "EXT@example.net", Kind: Member}}}),
+		"Users.get":                  result(map[string]interface{}{"list": UserList{{Id: "mu1", LoginName: "jdoe"}}}),
+		"MailingLists.addMlUserList": func(params json.RawMessage) interface{} {
+			request := struct {
+				Members UserOrEmailList `json:"members"`
+			}{}
+			json.Unmarshal(params, &request)
+			f.mu.Lock()
+			f.added = request.Members
+			f.mu.Unlock()
+			return map[string]interface{}{"errors": ErrorList{}}
+		},
+	}
+}
+
+func TestClusterJoin(t *testing.T) {
+	fake := &fakeClusterJoin{}
+	conn, local := newFakeConnection(t, fake.local())
+	master, _ := newFakeConnection(t, fake.master())
+	plan, err := conn.PlanClusterJoin(master, ClusterAuthentication{HostName: "master.example.com", AdminUser: "admin"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var descriptions []string
+	for _, resolution := range plan.Resolutions {
+		descriptions = append(descriptions, resolution.Description)
+	}
+	expected := []string{
+		"resolve resource room@example.com manually",
+		"remove alias info@example.com",
+		"rename alias help@example.com to help-local",
+		"merge members of mailing list all@example.com into the list of the cluster",
+	}
+	if strings.Join(descriptions, "\n") != strings.Join(expected, "\n") || len(plan.Conflicts) != 4 || plan.Conflicts[0].HomeServer != "master.example.com" {
+		t.Fatalf("unexpected plan %+v", plan)
+	}
+	if err = plan.SetResolution(plan.Conflicts[1], ClusterMerge, ""); err == nil {
+		t.Errorf("alias merged")
+	}
+	if _, err = conn.ApplyClusterJoin(plan); err == nil || err.Error() != "resolve resource room@example.com manually: conflict is not resolved" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err = plan.SetResolution(plan.Conflicts[0], ClusterRename, "meeting-room"); err == nil {
+		t.Errorf("resource renamed")
+	}
+	if err = plan.SetResolution(plan.Conflicts[0], ClusterRecreate, "meeting-room"); err != nil {
+		t.Fatal(err)
+	}
+	if plan.Resolutions[0].Description != "recreate resource room@example.com as meeting-room; existing bookings are lost" {
+		t.Errorf("unexpected description %q", plan.Resolutions[0].Description)
+	}
+	result, err := conn.ApplyClusterJoin(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fake.renamed != "meeting-room" || local.called("Resources.remove") != 1 || local.called("Aliases.remove") != 1 || local.called("MailingLists.remove") != 1 {
+		t.Errorf("unexpected resolutions %+v", result)
+	}
+	if len(fake.added) != 2 || fake.added[0].UserId != "mu1" || fake.added[1].HasId || fake.added[1].EmailAddress != "asmith@example.com" {
+		t.Errorf("unexpected merged members %+v", fake.added)
+	}
+	if len(result.Done) != 6 || result.RolledBack || result.Connect.Type != ClSuccess {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestClusterJoinRollback(t *testing.T) {
+	fake := &fakeClusterJoin{isError: true}
+	conn, local := newFakeConnection(t, fake.local())
+	master, _ := newFakeConnection(t, fake.master())
+	plan, err := conn.PlanClusterJoin(master, ClusterAuthentication{HostName: "master.example.com"}, &ClusterJoinOptions{RenameSuffix: "-old"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan.Resolutions = plan.Resolutions[:0]
+	result, err := conn.ApplyClusterJoin(plan)
+	if err == nil || !strings.Contains(err.Error(), "server was disconnected") || !result.RolledBack || local.called("DistributedDomain.disconnect") != 1 {
+		t.Errorf("unexpected result %+v, %v", result, err)
+	}
+	fake.connected = true
+	if _, err = conn.PlanClusterJoin(master, ClusterAuthentication{}, nil); err == nil || err.Error() != "server is already in cluster as Slave" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,263 @@
+package connect
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+const DefaultClusterMaxImbalance = 0.25 // allowed relative difference of users on home server from the average
+
+// ClusterReportOptions - Options of ClusterReport
+type ClusterReportOptions struct {
+	MaxImbalance float64 // home server with more or less users than average by this share is imbalanced; DefaultClusterMaxImbalance if 0
+}
+
+// ClusterHomeServerLoad - Users and mailing lists on one home server
+type ClusterHomeServerLoad struct {
+	HomeServer
+	Users        int     `json:"users"`        // count of users with this home server
+	MailingLists int     `json:"mailingLists"` // count of mailing lists with this home server
+	Deviation    float64 `json:"deviation"`    // relative difference of users from the average, e.g. 0.5 means 50 % above
+	Imbalanced   bool    `json:"imbalanced"`   // deviation is above MaxImbalance
+}
+
+type ClusterHomeServerLoadList []ClusterHomeServerLoad
+
+// ClusterReport - Topology and health of the distributed domain
+type ClusterReport struct {
+	Role               ClusterRole               `json:"role"`               // role of the connected server
+	IsMultiServer      bool                      `json:"isMultiServer"`      // cluster has more servers
+	IsInCluster        bool                      `json:"isInCluster"`        // connected server is not standalone
+	IsError            bool                      `json:"isError"`            // server reports error in cluster
+	Servers            ClusterServerList         `json:"servers"`            // servers of the cluster
+	HomeServers        ClusterHomeServerLoadList `json:"homeServers"`        // load of home servers
+	DistributedDomains StringList                `json:"distributedDomains"` // distributed domains of the connected server
+	Unassigned         int                       `json:"unassigned"`         // users of distributed domains without home server
+	Problems           StringList                `json:"problems"`           // human readable list of found problems, empty if cluster is healthy
+}
+
+// ClusterReport - Read role, servers and status of the cluster and count users and mailing lists of distributed domains by home server.
+//	options - nil means defaults
+// Return
+//	report - topology and health of the cluster; standalone server has only Role set
+func (s *ServerConnection) ClusterReport(options *ClusterReportOptions) (*ClusterReport, error) {
+	var o ClusterReportOptions
+	if options != nil {
+		o = *options
+	}
+	if o.MaxImbalance <= 0 {
+		o.MaxImbalance = DefaultClusterMaxImbalance
+	}
+	role, isMultiServer, err := s.DistributedDomainGetRole()
+	if err != nil {
+		return nil, err
+	}
+	report := &ClusterReport{Role: *role, IsMultiServer: isMultiServer, Servers: ClusterServerList{}, HomeServers: ClusterHomeServerLoadList{},
+		DistributedDomains: StringList{}, Problems: StringList{}}
+	if report.Role == ClStandalone || report.Role == "" {
+		return report, nil
+	}
+	if report.IsInCluster, report.IsError, err = s.DistributedDomainGetStatus(); err != nil {
+		return nil, err
+	}
+	if report.Servers, err = s.DistributedDomainGetServerList(); err != nil {
+		return nil, err
+	}
+	homeServers, err := s.DistributedDomainGetHomeServerList()
+	if err != nil {
+		return nil, err
+	}
+	domains, _, err := s.DomainsGet(SearchQuery{})
+	if err != nil {
+		return nil, err
+	}
+	var users UserList
+	var mls MlList
+	for _, domain := range domains {
+		if !domain.IsDistributed {
+			continue
+		}
+		report.DistributedDomains = append(report.DistributedDomains, domain.Name)
+		list, _, err := s.UsersGet(SearchQuery{}, domain.Id)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, list...)
+		mlList, _, err := s.MailingListsGet(SearchQuery{}, domain.Id)
+		if err != nil {
+			return nil, err
+		}
+		mls = append(mls, mlList...)
+	}
+	report.HomeServers, report.Unassigned = clusterHomeServerLoad(homeServers, users, mls, o.MaxImbalance)
+	report.Problems = report.findProblems()
+	return report, nil
+}
+
+// clusterHomeServerLoad counts users and mailing lists by home server and compares users with the average
+func clusterHomeServerLoad(homeServers HomeServerList, users UserList, mls MlList, maxImbalance float64) (ClusterHomeServerLoadList, int) {
+	loads := make(ClusterHomeServerLoadList, len(homeServers))
+	byId := make(map[KId]int, len(homeServers))
+	for i, server := range homeServers {
+		loads[i].HomeServer = server
+		byId[server.Id] = i
+	}
+	unassigned := 0
+	for _, user := range users {
+		if i, ok := byId[user.HomeServer.Id]; ok {
+			loads[i].Users++
+		} else {
+			unassigned++
+		}
+	}
+	for _, ml := range mls {
+		if i, ok := byId[ml.HomeServer.Id]; ok {
+			loads[i].MailingLists++
+		}
+	}
+	if len(loads) < 2 {
+		return loads, unassigned
+	}
+	average := float64(len(users)-unassigned) / float64(len(loads))
+	if average == 0 {
+		return loads, unassigned
+	}
+	for i := range loads {
+		loads[i].Deviation = (float64(loads[i].Users) - average) / average
+		loads[i].Imbalanced = math.Abs(loads[i].Deviation) > maxImbalance
+	}
+	return loads, unassigned
+}
+
+func (r *ClusterReport) findProblems() StringList {
+	problems := StringList{}
+	if r.IsError {
+		problems = append(problems, "server reports error in cluster")
+	}
+	for _, server := range r.Servers {
+		if server.Status == CsError {
+			messages := make([]string, len(server.ErrorMessages))
+			for i, message := range server.ErrorMessages {
+				messages[i] = formatMessage(message.Message, message.PositionalParameters)
+			}
+			problems = append(problems, fmt.Sprintf("server %s: %s", server.Hostname, strings.Join(messages, "; ")))
+		}
+		if server.DomainStatus == CsDomainDoesNotExist {
+			problems = append(problems, fmt.Sprintf("server %s: distributed domain does not exist", server.Hostname))
+		}
+	}
+	for _, load := range r.HomeServers {
+		if load.Imbalanced {
+			problems = append(problems, fmt.Sprintf("home server %s: %d users, %+.0f %% from average", load.Name, load.Users, load.Deviation*100))
+		}
+	}
+	if r.Unassigned > 0 {
+		problems = append(problems, fmt.Sprintf("%d users without known home server", r.Unassigned))
+	}
+	return problems
+}
+
+// homeServer returns load of the home server with the host name
+func (r *ClusterReport) homeServer(hostname string) (ClusterHomeServerLoad, bool) {
+	for _, load := range r.HomeServers {
+		if strings.EqualFold(load.Name, hostname) {
+			return load, true
+		}
+	}
+	return ClusterHomeServerLoad{}, false
+}
+
+// WriteClusterReport - Write the report as text for people
+func WriteClusterReport(w io.Writer, report *ClusterReport) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Role\t%s\n", strings.TrimPrefix(string(report.Role), "cl"))
+	if report.Role == ClStandalone {
+		return tw.Flush()
+	}
+	fmt.Fprintf(tw, "In cluster\t%t\n", report.IsInCluster)
+	fmt.Fprintf(tw, "Domains\t%s\n", strings.Join(report.DistributedDomains, ", "))
+	fmt.Fprintf(tw, "\nServer\tRole\tStatus\tDomain\tUsers\tMailing lists\n")
+	for _, server := range report.Servers {
+		role := "slave"
+		if server.IsPrimary {
+			role = "master"
+		}
+		if server.IsLocal {
+			role += " (local)"
+		}
+		load, _ := report.homeServer(server.Hostname)
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%d\n", server.Hostname, role, strings.TrimPrefix(string(server.Status), "cs"),
+			strings.TrimPrefix(string(server.DomainStatus), "csDomain"), load.Users, load.MailingLists)
+	}
+	if len(report.Problems) > 0 {
+		fmt.Fprintf(tw, "\nProblems\n")
+		for _, problem := range report.Problems {
+			fmt.Fprintf(tw, "%s\n", problem)
+		}
+	}
+	return tw.Flush()
+}
+
+// WriteClusterJson - Write the report as indented JSON
+func WriteClusterJson(w io.Writer, report *ClusterReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// WriteClusterDot - Write the cluster as Graphviz graph, master points to slaves.
+// Servers in error are red, servers without the domain or with imbalanced load are orange.
+func WriteClusterDot(w io.Writer, report *ClusterReport) error {
+	b := &strings.Builder{}
+	b.WriteString("digraph cluster {\n\tnode [shape=box];\n")
+	var master string
+	hostnames := StringList{}
+	for _, server := range report.Servers {
+		load, _ := report.homeServer(server.Hostname)
+		label := []string{server.Hostname}
+		if server.IsPrimary {
+			master = server.Hostname
+			label = append(label, "master")
+		}
+		label = append(label, fmt.Sprintf("%d users, %d mailing lists", load.Users, load.MailingLists))
+		color := "black"
+		if server.DomainStatus == CsDomainDoesNotExist || load.Imbalanced {
+			color = "orange"
+		}
+		if server.Status == CsError {
+			color = "red"
+		}
+		fmt.Fprintf(b, "\t%q [label=%q, color=%s];\n", server.Hostname, strings.Join(label, "\n"), color)
+		hostnames = append(hostnames, server.Hostname)
+	}
+	// home servers missing in the server list
+	for _, load := range report.HomeServers {
+		if !containsFold(hostnames, load.Name) {
+			fmt.Fprintf(b, "\t%q [label=%q, style=dashed];\n", load.Name, fmt.Sprintf("%s\n%d users, %d mailing lists", load.Name, load.Users, load.MailingLists))
+			hostnames = append(hostnames, load.Name)
+		}
+	}
+	sort.Strings(hostnames)
+	for _, hostname := range hostnames {
+		if master != "" && hostname != master {
+			fmt.Fprintf(b, "\t%q -> %q;\n", master, hostname)
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func containsFold(list StringList, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package connect
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func fakeClusterHandlers() map[string]fakeHandler {
+	homeServers := HomeServerList{{Id: "a", Name: "a.example.com"}, {Id: "b", Name: "b.example.com"}, {Id: "c", Name: "c.example.com"}}
+	var users UserList
+	for _, id := range []KId{"a", "a", "a", "a", "b", "b", "b", "c", ""} {
+		users = append(users, User{LoginName: "user", HomeServer: HomeServer{Id: id}})
+	}
+	return map[string]fakeHandler{
+		"DistributedDomain.getRole":   result(map[string]interface{}{"role": ClMaster, "isMultiServer": true}),
+		"DistributedDomain.getStatus": result(map[string]interface{}{"isInCluster": true, "isError": true}),
+		"DistributedDomain.getServerList": result(map[string]interface{}{"servers": ClusterServerList{
+			{Hostname: "a.example.com", IsPrimary: true, IsLocal: true, Status: CsReady, DomainStatus: CsDomainExists},
+			{Hostname: "b.example.com", Status: CsError, DomainStatus: CsDomainExists,
+				ErrorMessages: LocalizableMessageList{{Message: "Cannot reach %1", PositionalParameters: StringList{"b.example.com"}}}},
+			{Hostname: "c.example.com", Status: CsReady, DomainStatus: CsDomainDoesNotExist},
+		}}),
+		"DistributedDomain.getHomeServerList": result(map[string]interface{}{"servers": homeServers}),
+		"Domains.get": result(map[string]interface{}{"list": DomainList{
+			{Id: "d1", Name: "example.com", IsDistributed: true},
+			{Id: "d2", Name: "local.example"},
+		}}),
+		"Users.get":        result(map[string]interface{}{"list": users}),
+		"MailingLists.get": result(map[string]interface{}{"list": MlList{{Name: "all", HomeServer: HomeServer{Id: "b"}}}}),
+	}
+}
+
+func TestClusterReport(t *testing.T) {
+	conn, fake := newFakeConnection(t, fakeClusterHandlers())
+	report, err := conn.ClusterReport(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fake.called("Users.get") != 1 || len(report.DistributedDomains) != 1 || report.Unassigned != 1 {
+		t.Errorf("unexpected report %+v", report)
+	}
+	loads := report.HomeServers
+	if loads[0].Users != 4 || !loads[0].Imbalanced || loads[1].Imbalanced || loads[1].MailingLists != 1 || !loads[2].Imbalanced {
+		t.Errorf("unexpected loads %+v", loads)
+	}
+	expected := []string{
+		"server reports error in cluster",
+		"server b.example.com: Cannot reach b.example.com",
+		"server c.example.com: distributed domain does not exist",
+		"home server a.example.com: 4 users, +50 % from average",
+		"home server c.example.com: 1 users, -62 % from average",
+		"1 users without known home server",
+	}
+	if strings.Join(report.Problems, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected problems %q", report.Problems)
+	}
+	b := &bytes.Buffer{}
+	if err = WriteClusterReport(b, report); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "a.example.com  master (local)  Ready   Exists        4      0") {
+		t.Errorf("unexpected text\n%s", b)
+	}
+	b.Reset()
+	if err = WriteClusterDot(b, report); err != nil {
+		t.Fatal(err)
+	}
+	dot := b.String()
+	if !strings.Contains(dot, `"b.example.com" [label="b.example.com\n3 users, 1 mailing lists", color=red];`) ||
+		!strings.Contains(dot, `"a.example.com" -> "c.example.com";`) || strings.Contains(dot, `-> "a.example.com"`) {
+		t.Errorf("unexpected graph\n%s", dot)
+	}
+	b.Reset()
+	if err = WriteClusterJson(b, report); err != nil {
+		t.Fatal(err)
+	}
+	var decoded ClusterReport
+	if err = json.Unmarshal(b.Bytes(), &decoded); err != nil || decoded.Role != ClMaster || len(decoded.HomeServers) != 3 {
+		t.Errorf("unexpected JSON %v\n%s", err, b)
+	}
+}
+
+func TestClusterReportStandalone(t *testing.T) {
+	conn, fake := newFakeConnection(t, map[string]fakeHandler{
+		"DistributedDomain.getRole": result(map[string]interface{}{"role": ClStandalone}),
+	})
+	report, err := conn.ClusterReport(nil)
+	if err != nil || report.Role != ClStandalone || fake.called("DistributedDomain.getStatus") != 0 {
+		t.Errorf("unexpected report %+v, %v", report, err)
+	}
+}
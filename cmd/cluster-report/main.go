@@ -0,0 +1,56 @@
+// Command cluster-report prints topology and health of the distributed domain of Kerio Connect.
+// Usage:
+//	cluster-report -server mail.example.com -user admin [-format text|json|dot] [-max-imbalance 0.25]
+// The password is read from the KERIO_PASSWORD environment variable if -password is not given.
+// The exit code is 1 if problems were found, the dot format can be rendered by Graphviz, e.g. "dot -Tsvg".
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"github.com/igiant/connect"
+	"github.com/igiant/connect/internal/cmdflag"
+)
+
+func main() {
+	server := flag.String("server", "", "server address without schema, port 4040 is used if omitted")
+	user := flag.String("user", "", "administrator login name")
+	password := cmdflag.Secret("password", "KERIO_PASSWORD", "administrator password")
+	format := flag.String("format", "text", "output format: text, json or dot")
+	maxImbalance := flag.Float64("max-imbalance", connect.DefaultClusterMaxImbalance, "allowed relative difference of users on home server from the average")
+	cmdflag.Parse()
+	writers := map[string]func(io.Writer, *connect.ClusterReport) error{
+		"text": connect.WriteClusterReport,
+		"json": connect.WriteClusterJson,
+		"dot":  connect.WriteClusterDot,
+	}
+	write := writers[*format]
+	if *server == "" || *user == "" || write == nil {
+		flag.Usage()
+		os.Exit(2)
+	}
+	conn, err := connect.NewConfig(*server).NewConnection()
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = conn.Login(*user, *password, connect.NewApplication("cluster-report", "igiant", "v1.0.0"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	report, err := conn.ClusterReport(&connect.ClusterReportOptions{MaxImbalance: *maxImbalance})
+	if logoutErr := conn.Logout(); logoutErr != nil {
+		log.Println(logoutErr)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = write(os.Stdout, report); err != nil {
+		log.Fatal(err)
+	}
+	if len(report.Problems) > 0 {
+		os.Exit(1)
+	}
+}
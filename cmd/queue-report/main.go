@@ -0,0 +1,87 @@
+// Command queue-report exports the mail queue of Kerio Connect and prints its daily overview.
+// Usage:
+//	queue-report -server mail.example.com -user admin [-csv queue.csv] [-json queue.json] [-timezone Europe/Prague]
+// The password is read from the KERIO_PASSWORD environment variable if -password is not given.
+// The report is written to stdout, as text or as JSON with -report-json.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/igiant/connect"
+	"github.com/igiant/connect/internal/cmdflag"
+)
+
+func main() {
+	server := flag.String("server", "", "server address without schema, port 4040 is used if omitted")
+	user := flag.String("user", "", "administrator login name")
+	password := cmdflag.Secret("password", "KERIO_PASSWORD", "administrator password")
+	timezone := flag.String("timezone", "UTC", "time zone of the server")
+	csvFile := flag.String("csv", "", "file to export queued messages as CSV")
+	jsonFile := flag.String("json", "", "file to export queued messages as JSON")
+	top := flag.Int("top", connect.DefaultQueueReportTopDomains, "count of listed stuck domains")
+	retryAge := flag.Duration("retry-age", connect.DefaultQueueRetryAge, "age of message considered stuck in retry")
+	reportJson := flag.Bool("report-json", false, "write the report as JSON")
+	cmdflag.Parse()
+	if *server == "" || *user == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	location, err := time.LoadLocation(*timezone)
+	if err != nil {
+		log.Fatal(err)
+	}
+	conn, err := connect.NewConfig(*server).NewConnection()
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = conn.Login(*user, *password, connect.NewApplication("queue-report", "igiant", "v1.0.0"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	report, snapshot, err := conn.QueueReport(location, &connect.QueueReportOptions{TopDomains: *top, RetryAge: *retryAge})
+	if logoutErr := conn.Logout(); logoutErr != nil {
+		log.Println(logoutErr)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *csvFile != "" {
+		if err = writeFile(*csvFile, snapshot.Messages, connect.WriteQueueCsv); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *jsonFile != "" {
+		if err = writeFile(*jsonFile, snapshot.Messages, connect.WriteQueueJson); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *reportJson {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	} else {
+		err = connect.WriteQueueReport(os.Stdout, report)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// writeFile exports messages to the file by the writer
+func writeFile(name string, messages connect.QueueMessageList, write func(io.Writer, connect.QueueMessageList) error) error {
+	file, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err = write(file, messages); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
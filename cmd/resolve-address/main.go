@@ -0,0 +1,74 @@
+// Command resolve-address shows which mailboxes receive mail for an email address.
+// Usage:
+//	resolve-address -server mail.example.com -user admin [-json|-recipients] address...
+// The password is read from the KERIO_PASSWORD environment variable if -password is not given.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/igiant/connect"
+	"github.com/igiant/connect/internal/cmdflag"
+)
+
+func main() {
+	server := flag.String("server", "", "server address without schema, port 4040 is used if omitted")
+	user := flag.String("user", "", "administrator login name")
+	password := cmdflag.Secret("password", "KERIO_PASSWORD", "administrator password")
+	asJson := flag.Bool("json", false, "print expansion trees as JSON")
+	recipients := flag.Bool("recipients", false, "print final recipients only")
+	cmdflag.Parse()
+	if *server == "" || *user == "" || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	conn, err := connect.NewConfig(*server).NewConnection()
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = conn.Login(*user, *password, connect.NewApplication("resolve-address", "igiant", "v1.0.0"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = resolve(conn, flag.Args(), *asJson, *recipients)
+	if logoutErr := conn.Logout(); logoutErr != nil {
+		log.Println(logoutErr)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// resolve prints expansion of the addresses in the selected format
+func resolve(conn *connect.ServerConnection, addresses []string, asJson, recipients bool) error {
+	graph, err := conn.NewAddressGraph()
+	if err != nil {
+		return err
+	}
+	for _, address := range addresses {
+		expansion, err := graph.Expand(address)
+		if err != nil {
+			return err
+		}
+		switch {
+		case asJson:
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(expansion)
+		case recipients:
+			for _, recipient := range expansion.Recipients() {
+				fmt.Println(recipient)
+			}
+		default:
+			err = expansion.WriteTree(os.Stdout)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Command rotate-certificate replaces the active SSL certificate of Kerio Connect.
+// Usage:
+//	rotate-certificate -server mail.example.com -user admin -cert cert.pem -key key.pem [-chain chain.pem]
+//	rotate-certificate -server mail.example.com -user admin -acme https://acme.example.com/directory [-listen :80]
+//	rotate-certificate -server mail.example.com -user admin -acme https://localhost:14000/dir -acme-ca pebble.minica.pem -roots pebble-root.pem
+// The password is read from the KERIO_PASSWORD environment variable if -password is not given.
+// With -acme, http-01 challenges are served on -listen, which has to be reachable as port 80 of the server hostname.
+// A local ACME server like Pebble needs -acme-ca to trust its HTTPS endpoint and -roots to verify the issued chain.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/igiant/connect"
+	"github.com/igiant/connect/internal/cmdflag"
+)
+
+func main() {
+	server := flag.String("server", "", "server address without schema, port 4040 is used if omitted")
+	user := flag.String("user", "", "administrator login name")
+	password := cmdflag.Secret("password", "KERIO_PASSWORD", "administrator password")
+	certFile := flag.String("cert", "", "certificate in PEM format")
+	keyFile := flag.String("key", "", "private key in PEM format")
+	chainFile := flag.String("chain", "", "intermediate certificates in PEM format")
+	keyPassword := cmdflag.Secret("key-password", "KERIO_KEY_PASSWORD", "password of encrypted private key")
+	acmeUrl := flag.String("acme", "", "directory URL of ACME server to obtain the certificate from")
+	contact := flag.String("contact", "", "contact of ACME account, e.g. mailto:admin@example.com")
+	listen := flag.String("listen", ":80", "address serving http-01 challenges")
+	hostname := flag.String("hostname", "", "hostname the certificate has to cover; hostname of the server if empty")
+	acmeCa := flag.String("acme-ca", "", "authorities in PEM format trusted for HTTPS of the ACME server; system roots if empty")
+	rootsFile := flag.String("roots", "", "authorities in PEM format trusted when verifying the new certificate; system roots if empty")
+	keep := flag.Bool("keep", false, "keep superseded inactive certificates")
+	cmdflag.Parse()
+	if *server == "" || *user == "" || (*acmeUrl == "") == (*certFile == "" || *keyFile == "") {
+		flag.Usage()
+		os.Exit(2)
+	}
+	options := &connect.CertificateRotationOptions{Hostname: *hostname, KeyPassword: *keyPassword, KeepSuperseded: *keep}
+	var err error
+	if *rootsFile != "" {
+		if options.Roots, err = loadCertPool(*rootsFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	acme := &connect.AcmeOptions{DirectoryUrl: *acmeUrl}
+	if *contact != "" {
+		acme.Contact = connect.StringList{*contact}
+	}
+	if *acmeCa != "" {
+		pool, err := loadCertPool(*acmeCa)
+		if err != nil {
+			log.Fatal(err)
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+		acme.Client = &http.Client{Transport: transport}
+	}
+	conn, err := connect.NewConfig(*server).NewConnection()
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = conn.Login(*user, *password, connect.NewApplication("rotate-certificate", "igiant", "v1.0.0"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	var result *connect.CertificateRotationResult
+	if *acmeUrl != "" {
+		solver := connect.NewHttpChallengeSolver()
+		go func() { log.Println(http.ListenAndServe(*listen, solver)) }()
+		acme.Solver = solver
+		result, err = conn.RotateAcmeCertificate(context.Background(), acme, options)
+	} else {
+		result, err = rotateFiles(conn, *certFile, *keyFile, *chainFile, options)
+	}
+	if logoutErr := conn.Logout(); logoutErr != nil {
+		log.Println(logoutErr)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("certificate %s for %s is active, expires %s", result.Name, result.Hostname, result.NotAfter.Format("2006-01-02"))
+	for _, name := range result.Removed {
+		log.Printf("removed superseded certificate %s", name)
+	}
+}
+
+// rotateFiles reads PEM files and rotates the certificate
+func rotateFiles(conn *connect.ServerConnection, certFile, keyFile, chainFile string, options *connect.CertificateRotationOptions) (*connect.CertificateRotationResult, error) {
+	certPEM, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	var chain []byte
+	if chainFile != "" {
+		if chain, err = ioutil.ReadFile(chainFile); err != nil {
+			return nil, err
+		}
+	}
+	return conn.RotateCertificate(certPEM, keyPEM, chain, options)
+}
+
+// loadCertPool reads authorities from the PEM file
+func loadCertPool(file string) (*x509.CertPool, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("%s: no certificate found", file)
+	}
+	return pool, nil
+}
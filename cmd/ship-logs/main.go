@@ -0,0 +1,116 @@
+// Command ship-logs forwards Kerio Connect logs read through the API to syslog, local files or stdout.
+// Usage:
+//	ship-logs -server mail.example.com -user admin -logs mail,security -syslog tcp://collector:6514 [-cursor file]
+//	ship-logs -server mail.example.com -user admin -logs mail -dir /var/log/kerio -max-size 100
+//	ship-logs -server mail.example.com -user admin -logs spam -json -parse
+// The password is read from the KERIO_PASSWORD environment variable if -password is not given.
+// Positions of shipped rows are kept in the cursor file, so rows are not lost when the command is restarted.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/igiant/connect"
+	"github.com/igiant/connect/internal/cmdflag"
+)
+
+func main() {
+	server := flag.String("server", "", "server address without schema, port 4040 is used if omitted")
+	user := flag.String("user", "", "administrator login name")
+	password := cmdflag.Secret("password", "KERIO_PASSWORD", "administrator password")
+	logs := flag.String("logs", "mail,security", "comma separated names of logs to ship")
+	cursor := flag.String("cursor", "ship-logs.cursor", "file with positions of shipped rows")
+	startLines := flag.Int("start-lines", 0, "rows shipped from the end of a log without saved position")
+	syslogUrl := flag.String("syslog", "", "syslog collector as udp://host:port, tcp://host:port or tls://host:port")
+	ca := flag.String("ca", "", "PEM file with certificates of authorities trusted by tls syslog")
+	dir := flag.String("dir", "", "directory of rotated log files")
+	maxSize := flag.Int64("max-size", 100, "size of log file in megabytes when it is rotated")
+	maxFiles := flag.Int("max-files", 10, "count of kept rotated files")
+	asJson := flag.Bool("json", false, "write JSON lines to stdout")
+	parse := flag.Bool("parse", false, "attach parsed events to JSON lines")
+	cmdflag.Parse()
+	if *server == "" || *user == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	sink, err := newSink(*syslogUrl, *ca, *dir, *maxSize, *maxFiles, *asJson)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer sink.Close()
+	var logNames []connect.LogType
+	for _, name := range strings.Split(*logs, ",") {
+		logNames = append(logNames, connect.LogType(strings.TrimSpace(name)))
+	}
+	conn, err := connect.NewConfig(*server).NewConnection()
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = conn.Login(*user, *password, connect.NewApplication("ship-logs", "igiant", "v1.0.0"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		if err := conn.Logout(); err != nil {
+			log.Println(err)
+		}
+	}()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	shipper := conn.NewLogShipper(logNames, sink, &connect.LogShipperOptions{
+		CursorFile: *cursor,
+		StartLines: *startLines,
+		Parse:      *parse,
+		OnError:    func(err error) { log.Println(err) },
+	})
+	if err = shipper.Run(ctx); err != nil {
+		log.Println(err)
+	}
+}
+
+// newSink creates the sink selected by flags, exactly one of them has to be given
+func newSink(syslogUrl, ca, dir string, maxSize int64, maxFiles int, asJson bool) (connect.LogSink, error) {
+	selected := 0
+	for _, given := range []bool{syslogUrl != "", dir != "", asJson} {
+		if given {
+			selected++
+		}
+	}
+	if selected != 1 {
+		return nil, fmt.Errorf("exactly one of -syslog, -dir and -json has to be given")
+	}
+	switch {
+	case dir != "":
+		return connect.NewFileSink(dir, maxSize<<20, maxFiles)
+	case asJson:
+		return connect.NewJsonLinesSink(os.Stdout), nil
+	}
+	u, err := url.Parse(syslogUrl)
+	if err != nil {
+		return nil, err
+	}
+	options := &connect.SyslogSinkOptions{}
+	if ca != "" {
+		data, err := ioutil.ReadFile(ca)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificate found in %s", ca)
+		}
+		options.TlsConfig = &tls.Config{RootCAs: pool}
+	}
+	return connect.NewSyslogSink(u.Scheme, u.Host, options)
+}
@@ -0,0 +1,103 @@
+// Command watch-alerts polls alerts of Kerio Connect and notifies when they are raised or cleared.
+// Usage:
+//	watch-alerts -server mail.example.com -user admin -webhook https://hooks.example.com/kerio
+//	watch-alerts -server mail.example.com -user admin -smtp relay.example.com:25 -from kerio@example.com -to ops@example.com
+//	watch-alerts -server mail.example.com -user admin -command /usr/local/bin/page -severity CoredumpFound=Critical
+// The password is read from the KERIO_PASSWORD environment variable if -password is not given.
+// Active alerts are kept in the state file, so they are not notified again after restart.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/igiant/connect"
+	"github.com/igiant/connect/internal/cmdflag"
+)
+
+func main() {
+	server := flag.String("server", "", "server address without schema, port 4040 is used if omitted")
+	user := flag.String("user", "", "administrator login name")
+	password := cmdflag.Secret("password", "KERIO_PASSWORD", "administrator password")
+	state := flag.String("state", "watch-alerts.state", "file with active alerts")
+	interval := flag.Duration("interval", connect.DefaultAlertPollInterval, "pause between checks")
+	webhook := flag.String("webhook", "", "URL receiving events by POST")
+	relay := flag.String("smtp", "", "host:port of SMTP relay sending events by email")
+	from := flag.String("from", "", "sender address of emails")
+	to := flag.String("to", "", "comma separated recipients of emails")
+	command := flag.String("command", "", "command receiving events as JSON on standard input")
+	severity := flag.String("severity", "", "comma separated overrides of alert types, e.g. StorageSpaceLow=Critical")
+	silence := flag.String("silence", "", "comma separated silences, e.g. CoredumpFound=2021-02-01T00:00:00Z")
+	cmdflag.Parse()
+	if *server == "" || *user == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	var notifiers []connect.AlertNotifier
+	if *webhook != "" {
+		notifiers = append(notifiers, connect.NewWebhookNotifier(*webhook))
+	}
+	if *relay != "" {
+		notifiers = append(notifiers, connect.NewSmtpNotifier(*relay, *from, strings.Split(*to, ","), nil))
+	}
+	if *command != "" {
+		notifiers = append(notifiers, connect.NewCommandNotifier(*command))
+	}
+	if len(notifiers) == 0 {
+		log.Fatal("at least one of -webhook, -smtp and -command has to be given")
+	}
+	options := &connect.AlertWatcherOptions{
+		StateFile:    *state,
+		PollInterval: *interval,
+		Severity:     map[connect.AlertName]connect.TypeAlert{},
+		Host:         *server,
+		OnError:      func(err error) { log.Println(err) },
+	}
+	for name, value := range pairs(*severity) {
+		options.Severity[connect.AlertName(name)] = connect.TypeAlert(value)
+	}
+	for name, value := range pairs(*silence) {
+		until, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			log.Fatal(err)
+		}
+		options.Silences = append(options.Silences, connect.AlertSilence{AlertName: connect.AlertName(name), Until: until})
+	}
+	conn, err := connect.NewConfig(*server).NewConnection()
+	if err != nil {
+		log.Fatal(err)
+	}
+	watcher, err := conn.NewAlertWatcher(notifiers, options)
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = conn.Login(*user, *password, connect.NewApplication("watch-alerts", "igiant", "v1.0.0"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		if err := conn.Logout(); err != nil {
+			log.Println(err)
+		}
+	}()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	watcher.Run(ctx)
+}
+
+// pairs parses comma separated name=value pairs
+func pairs(value string) map[string]string {
+	result := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		if i := strings.Index(pair, "="); i > 0 {
+			result[strings.TrimSpace(pair[:i])] = strings.TrimSpace(pair[i+1:])
+		}
+	}
+	return result
+}
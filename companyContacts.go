@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 type CompanyContact struct {
 	Id       KId    `json:"id"`
 	Name     string `json:"name"` // name of company contact (caption of item in list of contacts)
@@ -40,7 +38,7 @@ func (s *ServerConnection) CompanyContactsCreate(companyContacts CompanyContactL
 			Result CreateResultList `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, errors.Result.Result, err
 }
 
@@ -64,7 +62,7 @@ func (s *ServerConnection) CompanyContactsGet(query SearchQuery) (CompanyContact
 			TotalItems int                `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -85,7 +83,7 @@ func (s *ServerConnection) CompanyContactsGetAvailable(domainId KId) (CompanyCon
 			CompanyContactList CompanyContactList `json:"companyContactList"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &companyContactList)
+	err = s.unmarshal(data, &companyContactList)
 	return companyContactList.Result.CompanyContactList, err
 }
 
@@ -105,7 +103,7 @@ func (s *ServerConnection) CompanyContactsRemove(companyContactsIds KIdList) (Er
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -128,6 +126,6 @@ func (s *ServerConnection) CompanyContactsSet(companyContactsIds KIdList, patter
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
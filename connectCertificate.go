@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 // ConnectCertificateExportCertificate - Note: "export" is a keyword in C++, so name of the method must be changed: exportCertificate
 //	id - ID of the certificate or certificate request
 // Return
@@ -19,7 +17,7 @@ func (s *ServerConnection) ConnectCertificateExportCertificate(id KId) (*Downloa
 			FileDownload Download `json:"fileDownload"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &fileDownload)
+	err = s.unmarshal(data, &fileDownload)
 	return &fileDownload.Result.FileDownload, err
 }
 
@@ -40,7 +38,7 @@ func (s *ServerConnection) ConnectCertificateExportPrivateKey(id KId) (*Download
 			FileDownload Download `json:"fileDownload"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &fileDownload)
+	err = s.unmarshal(data, &fileDownload)
 	return &fileDownload.Result.FileDownload, err
 }
 
@@ -57,7 +55,7 @@ func (s *ServerConnection) ConnectCertificateGet() (CertificateList, error) {
 			Certificates CertificateList `json:"certificates"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &certificates)
+	err = s.unmarshal(data, &certificates)
 	return certificates.Result.Certificates, err
 }
 
@@ -80,7 +78,7 @@ func (s *ServerConnection) ConnectCertificateGenerate(subject NamedValueList, va
 			Id KId `json:"id"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &id)
+	err = s.unmarshal(data, &id)
 	return &id.Result.Id, err
 }
 
@@ -101,7 +99,7 @@ func (s *ServerConnection) ConnectCertificateGenerateRequest(subject NamedValueL
 			Id KId `json:"id"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &id)
+	err = s.unmarshal(data, &id)
 	return &id.Result.Id, err
 }
 
@@ -138,7 +136,7 @@ func (s *ServerConnection) ConnectCertificateImportPrivateKey(fileId string) (bo
 			Id           KId  `json:"id"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &needPassword)
+	err = s.unmarshal(data, &needPassword)
 	return needPassword.Result.NeedPassword, &needPassword.Result.Id, err
 }
 
@@ -158,7 +156,7 @@ func (s *ServerConnection) ConnectCertificateRemove(certificateIds KIdList) (Err
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -189,6 +187,6 @@ func (s *ServerConnection) ConnectCertificateToSource(id KId) (string, error) {
 			Source string `json:"source"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &source)
+	err = s.unmarshal(data, &source)
 	return source.Result.Source, err
 }
@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 type AttachmentAction string
 
 const (
@@ -324,7 +322,7 @@ func (s *ServerConnection) ContentAddBlackLists(items BlackListList) (ErrorList,
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -341,7 +339,7 @@ func (s *ServerConnection) ContentGetAntiSpamSetting() (*AntiSpamSetting, error)
 			Setting AntiSpamSetting `json:"setting"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &setting)
+	err = s.unmarshal(data, &setting)
 	return &setting.Result.Setting, err
 }
 
@@ -358,7 +356,7 @@ func (s *ServerConnection) ContentGetAntivirusSetting() (*AntivirusSetting, erro
 			Setting AntivirusSetting `json:"setting"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &setting)
+	err = s.unmarshal(data, &setting)
 	return &setting.Result.Setting, err
 }
 
@@ -375,7 +373,7 @@ func (s *ServerConnection) ContentGetAttachmentRules() (AttachmentItemList, erro
 			FilterRules AttachmentItemList `json:"filterRules"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &filterRules)
+	err = s.unmarshal(data, &filterRules)
 	return filterRules.Result.FilterRules, err
 }
 
@@ -392,7 +390,7 @@ func (s *ServerConnection) ContentGetAttachmentSetting() (*AttachmentSetting, er
 			Setting AttachmentSetting `json:"setting"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &setting)
+	err = s.unmarshal(data, &setting)
 	return &setting.Result.Setting, err
 }
 
@@ -411,7 +409,7 @@ func (s *ServerConnection) ContentGetAvailableAttachments() (StringList, StringL
 			MimeTypes StringList `json:"mimeTypes"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &fileNames)
+	err = s.unmarshal(data, &fileNames)
 	return fileNames.Result.FileNames, fileNames.Result.MimeTypes, err
 }
 
@@ -428,7 +426,7 @@ func (s *ServerConnection) ContentGetBlackListList() (BlackListList, error) {
 			List BlackListList `json:"list"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, err
 }
 
@@ -452,7 +450,7 @@ func (s *ServerConnection) ContentGetCustomRuleList(query SearchQuery) (CustomRu
 			TotalItems int            `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -473,7 +471,7 @@ func (s *ServerConnection) ContentRemoveBlackLists(ids KIdList) (ErrorList, erro
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -516,7 +514,7 @@ func (s *ServerConnection) ContentSetAntivirusSetting(setting AntivirusSetting)
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -559,7 +557,7 @@ func (s *ServerConnection) ContentSetBlackLists(ids KIdList, pattern BlackList)
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -590,7 +588,7 @@ func (s *ServerConnection) ContentTestIntegratedAntiSpamEngine() (*IntegratedAnt
 			Status IntegratedAntiSpamStatus `json:"status"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &status)
+	err = s.unmarshal(data, &status)
 	return &status.Result.Status, err
 }
 
@@ -607,7 +605,7 @@ func (s *ServerConnection) ContentUpdateAntivirusStatus() (*IntegratedAvirUpdate
 			Status IntegratedAvirUpdateStatus `json:"status"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &status)
+	err = s.unmarshal(data, &status)
 	return &status.Result.Status, err
 }
 
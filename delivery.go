@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 type TriggerType string
 
 const (
@@ -149,7 +147,7 @@ func (s *ServerConnection) DeliveryAddEtrnDownloadList(downloads EtrnDownloadLis
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -170,7 +168,7 @@ func (s *ServerConnection) DeliveryAddPop3AccountList(accounts Pop3AccountList)
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -191,7 +189,7 @@ func (s *ServerConnection) DeliveryAddPop3SortingList(sortings Pop3SortingList)
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -212,7 +210,7 @@ func (s *ServerConnection) DeliveryAddScheduledActionList(actions ScheduledActio
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -242,7 +240,7 @@ func (s *ServerConnection) DeliveryGetEtrnDownloadList(query SearchQuery) (EtrnD
 			TotalItems int              `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -259,7 +257,7 @@ func (s *ServerConnection) DeliveryGetEtrnTimeout() (int, error) {
 			Seconds int `json:"seconds"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &seconds)
+	err = s.unmarshal(data, &seconds)
 	return seconds.Result.Seconds, err
 }
 
@@ -276,7 +274,7 @@ func (s *ServerConnection) DeliveryGetInternetSettings() (*InternetSettings, err
 			Settings InternetSettings `json:"settings"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &settings)
+	err = s.unmarshal(data, &settings)
 	return &settings.Result.Settings, err
 }
 
@@ -300,7 +298,7 @@ func (s *ServerConnection) DeliveryGetPop3AccountList(query SearchQuery) (Pop3Ac
 			TotalItems int             `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -324,7 +322,7 @@ func (s *ServerConnection) DeliveryGetPop3SortingList(query SearchQuery) (Pop3So
 			TotalItems int             `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -341,7 +339,7 @@ func (s *ServerConnection) DeliveryGetRasNames() (StringList, error) {
 			Names StringList `json:"names"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &names)
+	err = s.unmarshal(data, &names)
 	return names.Result.Names, err
 }
 
@@ -365,7 +363,7 @@ func (s *ServerConnection) DeliveryGetScheduledActionList(query SearchQuery) (Sc
 			TotalItems int                 `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -386,7 +384,7 @@ func (s *ServerConnection) DeliveryRemoveEtrnDownloadList(ids KIdList) (ErrorLis
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -407,7 +405,7 @@ func (s *ServerConnection) DeliveryRemovePop3AccountList(ids KIdList) (ErrorList
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -428,7 +426,7 @@ func (s *ServerConnection) DeliveryRemovePop3SortingList(ids KIdList) (ErrorList
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -449,7 +447,7 @@ func (s *ServerConnection) DeliveryRemoveScheduledActionList(ids KIdList) (Error
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
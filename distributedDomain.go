@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 // HomeServer - User's home server in a distributed domain.
 type HomeServer struct {
 	Id   KId    `json:"id"`   // server's id
@@ -13,27 +11,27 @@ type HomeServerList []HomeServer
 type ClusterErrorType string
 
 const (
-	clSuccess                 ClusterErrorType = "clSuccess"
-	clError                   ClusterErrorType = "clError"                   // Generic cluster error, see ClusterError.errorMessage for details
-	clSelfConnectError        ClusterErrorType = "clSelfConnectError"        // The master cannot be the same as slave
-	clConnectToSlaveError     ClusterErrorType = "clConnectToSlaveError"     // ServerConnection to slave is not allowed
-	clInaccessibleHost        ClusterErrorType = "clInaccessibleHost"        // Cannot connect to the specified host
-	clInvalidUserOrPassword   ClusterErrorType = "clInvalidUserOrPassword"   // User name or password are invalid or has insufficient rights
-	clIncorrectClusterVersion ClusterErrorType = "clIncorrectClusterVersion" // Remote server has incompatible implementation of cluster services
-	clDataConflict            ClusterErrorType = "clDataConflict"            // There are multiple resources/aliases or mailing lists with the same name, server cannot be connected to cluster
-	clDirServiceRemoteEmpty   ClusterErrorType = "clDirServiceRemoteEmpty"   // Specified distributed domain has no Directory Service configured on remote distributed domain host
-	clDirServiceLocalEmpty    ClusterErrorType = "clDirServiceLocalEmpty"    // Specified distributed domain has no Directory Service configured on local distributed domain host
-	clDirServiceDifferent     ClusterErrorType = "clDirServiceDifferent"     // Specified distributed domain has different Directory Service configured on local and remote distributed domain host
+	ClSuccess                 ClusterErrorType = "clSuccess"
+	ClError                   ClusterErrorType = "clError"                   // Generic cluster error, see ClusterError.errorMessage for details
+	ClSelfConnectError        ClusterErrorType = "clSelfConnectError"        // The master cannot be the same as slave
+	ClConnectToSlaveError     ClusterErrorType = "clConnectToSlaveError"     // ServerConnection to slave is not allowed
+	ClInaccessibleHost        ClusterErrorType = "clInaccessibleHost"        // Cannot connect to the specified host
+	ClInvalidUserOrPassword   ClusterErrorType = "clInvalidUserOrPassword"   // User name or password are invalid or has insufficient rights
+	ClIncorrectClusterVersion ClusterErrorType = "clIncorrectClusterVersion" // Remote server has incompatible implementation of cluster services
+	ClDataConflict            ClusterErrorType = "clDataConflict"            // There are multiple resources/aliases or mailing lists with the same name, server cannot be connected to cluster
+	ClDirServiceRemoteEmpty   ClusterErrorType = "clDirServiceRemoteEmpty"   // Specified distributed domain has no Directory Service configured on remote distributed domain host
+	ClDirServiceLocalEmpty    ClusterErrorType = "clDirServiceLocalEmpty"    // Specified distributed domain has no Directory Service configured on local distributed domain host
+	ClDirServiceDifferent     ClusterErrorType = "clDirServiceDifferent"     // Specified distributed domain has different Directory Service configured on local and remote distributed domain host
 )
 
 type ClusterConflictTarget string
 
 const (
-	clResource    ClusterConflictTarget = "clResource"
-	clAlias       ClusterConflictTarget = "clAlias"
-	clMailingList ClusterConflictTarget = "clMailingList"
-	clDomainAlias ClusterConflictTarget = "clDomainAlias"
-	clDomain      ClusterConflictTarget = "clDomain"
+	ClResource    ClusterConflictTarget = "clResource"
+	ClAlias       ClusterConflictTarget = "clAlias"
+	ClMailingList ClusterConflictTarget = "clMailingList"
+	ClDomainAlias ClusterConflictTarget = "clDomainAlias"
+	ClDomain      ClusterConflictTarget = "clDomain"
 )
 
 type ClusterConflict struct {
@@ -55,16 +53,16 @@ type ClusterError struct {
 type ClusterRole string
 
 const (
-	clStandalone ClusterRole = "clStandalone"
-	clMaster     ClusterRole = "clMaster"
-	clSlave      ClusterRole = "clSlave"
+	ClStandalone ClusterRole = "clStandalone"
+	ClMaster     ClusterRole = "clMaster"
+	ClSlave      ClusterRole = "clSlave"
 )
 
 type ClusterStatus string
 
 const (
-	csReady ClusterStatus = "csReady" // Server in claster work well.
-	csError ClusterStatus = "csError" // Server in claster don't work with some error, see errorMessages for details
+	CsReady ClusterStatus = "csReady" // Server in claster work well.
+	CsError ClusterStatus = "csError" // Server in claster don't work with some error, see errorMessages for details
 )
 
 type LocalizableMessageList []LocalizableMessage
@@ -72,9 +70,9 @@ type LocalizableMessageList []LocalizableMessage
 type ClusterDomainStatus string
 
 const (
-	csDomainNotChecked   ClusterDomainStatus = "csDomainNotChecked"
-	csDomainExists       ClusterDomainStatus = "csDomainExists"
-	csDomainDoesNotExist ClusterDomainStatus = "csDomainDoesNotExist"
+	CsDomainNotChecked   ClusterDomainStatus = "csDomainNotChecked"
+	CsDomainExists       ClusterDomainStatus = "csDomainExists"
+	CsDomainDoesNotExist ClusterDomainStatus = "csDomainDoesNotExist"
 )
 
 type ClusterServer struct {
@@ -115,7 +113,7 @@ func (s *ServerConnection) DistributedDomainConnect(hostName string, adminUser s
 			Result ClusterError `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &result)
+	err = s.unmarshal(data, &result)
 	return &result.Result.Result, err
 }
 
@@ -154,7 +152,7 @@ func (s *ServerConnection) DistributedDomainGetDistributable(authentication Clus
 			DomainNames StringList `json:"domainNames"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &domainNames)
+	err = s.unmarshal(data, &domainNames)
 	return domainNames.Result.DomainNames, err
 }
 
@@ -170,7 +168,7 @@ func (s *ServerConnection) DistributedDomainGetRole() (*ClusterRole, bool, error
 			IsMultiServer bool        `json:"isMultiServer"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &role)
+	err = s.unmarshal(data, &role)
 	return &role.Result.Role, role.Result.IsMultiServer, err
 }
 
@@ -187,7 +185,7 @@ func (s *ServerConnection) DistributedDomainGetServerList() (ClusterServerList,
 			Servers ClusterServerList `json:"servers"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &servers)
+	err = s.unmarshal(data, &servers)
 	return servers.Result.Servers, err
 }
 
@@ -204,7 +202,7 @@ func (s *ServerConnection) DistributedDomainGetHomeServerList() (HomeServerList,
 			Servers HomeServerList `json:"servers"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &servers)
+	err = s.unmarshal(data, &servers)
 	return servers.Result.Servers, err
 }
 
@@ -223,6 +221,6 @@ func (s *ServerConnection) DistributedDomainGetStatus() (bool, bool, error) {
 			IsError     bool `json:"isError"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &isInCluster)
+	err = s.unmarshal(data, &isInCluster)
 	return isInCluster.Result.IsInCluster, isInCluster.Result.IsError, err
 }
@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 // DeliveryType - Delivery Type
 type DeliveryType string
 
@@ -189,7 +187,7 @@ func (s *ServerConnection) DomainsCreate(domains DomainList) (ErrorList, CreateR
 			Result CreateResultList `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, errors.Result.Result, err
 }
 
@@ -210,7 +208,7 @@ func (s *ServerConnection) DomainsGeneratePassword(domainId KId) (string, error)
 			Password string `json:"password"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &password)
+	err = s.unmarshal(data, &password)
 	return password.Result.Password, err
 }
 
@@ -234,7 +232,7 @@ func (s *ServerConnection) DomainsGet(query SearchQuery) (DomainList, int, error
 			TotalItems int        `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -252,7 +250,7 @@ func (s *ServerConnection) DomainsGetDkimDnsRecord(domain string) (string, error
 			Detail string `json:"detail"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &detail)
+	err = s.unmarshal(data, &detail)
 	return detail.Result.Detail, err
 }
 
@@ -269,7 +267,7 @@ func (s *ServerConnection) DomainsGetSettings() (*DomainSetting, error) {
 			Setting DomainSetting `json:"setting"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &setting)
+	err = s.unmarshal(data, &setting)
 	return &setting.Result.Setting, err
 }
 
@@ -290,7 +288,7 @@ func (s *ServerConnection) DomainsGetUserCountInfo(domainId KId) (*UserDomainCou
 			CountInfo UserDomainCountInfo `json:"countInfo"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &countInfo)
+	err = s.unmarshal(data, &countInfo)
 	return &countInfo.Result.CountInfo, err
 }
 
@@ -311,7 +309,7 @@ func (s *ServerConnection) DomainsRemove(domainIds KIdList) (ErrorList, error) {
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -334,7 +332,7 @@ func (s *ServerConnection) DomainsRename(domainId KId, newName string) (*Cluster
 			Error ClusterError `json:"error"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &error)
+	err = s.unmarshal(data, &error)
 	return &error.Result.Error, err
 }
 
@@ -355,7 +353,7 @@ func (s *ServerConnection) DomainsSaveFooterImage(fileId string) (string, error)
 			ImgUrl string `json:"imgUrl"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &imgUrl)
+	err = s.unmarshal(data, &imgUrl)
 	return imgUrl.Result.ImgUrl, err
 }
 
@@ -378,7 +376,7 @@ func (s *ServerConnection) DomainsSaveWebMailLogo(fileId string, domainId KId) (
 			LogoUrl string `json:"logoUrl"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &logoUrl)
+	err = s.unmarshal(data, &logoUrl)
 	return logoUrl.Result.LogoUrl, err
 }
 
@@ -401,7 +399,7 @@ func (s *ServerConnection) DomainsSet(domainIds KIdList, pattern Domain) (ErrorL
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -436,7 +434,7 @@ func (s *ServerConnection) DomainsTestDomainController(hostnames StringList, con
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -457,7 +455,7 @@ func (s *ServerConnection) DomainsTestDkimDnsStatus(hostnames StringList) (Error
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -472,6 +470,6 @@ func (s *ServerConnection) DomainsGetDomainFooterPlaceholders() (NamedConstantLi
 			Placeholders NamedConstantList `json:"placeholders"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &placeholders)
+	err = s.unmarshal(data, &placeholders)
 	return placeholders.Result.Placeholders, err
 }
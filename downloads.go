@@ -1,5 +1,12 @@
 package connect
 
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
 type DownloadList []Download
 
 // Download management
@@ -13,3 +20,34 @@ func (s *ServerConnection) DownloadsRemove(url string) error {
 	_, err := s.CallRaw("Downloads.downloadsRemove", params)
 	return err
 }
+
+// DownloadFile - Read file prepared to download, e.g. by export methods.
+//	download - description of the file
+// Return
+//	content - file content
+func (s *ServerConnection) DownloadFile(download *Download) ([]byte, error) {
+	base, err := url.Parse(s.Config.url)
+	if err != nil {
+		return nil, err
+	}
+	location, err := base.Parse(download.Url)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("GET", location.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.Token != nil {
+		req.Header.Add("X-Token", *s.Token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download of %s failed: %s", download.Name, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
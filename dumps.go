@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 type CoreDump struct {
 	Size      ByteValueWithUnits `json:"size"`
 	Timestamp DateTimeStamp      `json:"timestamp"`
@@ -22,7 +20,7 @@ func (s *ServerConnection) DumpsGet() (DumpList, error) {
 			Dumps DumpList `json:"dumps"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &dumps)
+	err = s.unmarshal(data, &dumps)
 	return dumps.Result.Dumps, err
 }
 
@@ -0,0 +1,104 @@
+package connect
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Types defined by this library and MlMembership, which has the local value MlTrustee, are not server enums.
+//go:generate go run ./internal/enumgen -skip LogType,MlMembership,AddressNodeKind,AlertTransition,AliasIssueType,BackupFindingCode,CertificateFindingCode,ClusterResolutionAction,MetricType,MigrationItemState,OldAddressMode,QueueAnomalyType,QueuePolicyAction,SecurityProtection,SecurityReason,TraceEventType,UserMoveActionType
+
+// UnknownEnumError - Value is not one of the known constants of the enum type
+type UnknownEnumError struct {
+	Type  string // name of the enum type
+	Value string // unknown value
+}
+
+func (e *UnknownEnumError) Error() string {
+	return fmt.Sprintf("unknown %s value %q", e.Type, e.Value)
+}
+
+// enum is implemented by the generated enum types
+type enum interface {
+	Valid() bool
+}
+
+var enumType = reflect.TypeOf((*enum)(nil)).Elem()
+
+// enumReport collects distinct unknown enum values decoded by the connection
+type enumReport struct {
+	mu      sync.Mutex
+	unknown []UnknownEnumError
+}
+
+// UnknownEnums - Return distinct unknown enum values the server sent so far.
+// They are accepted unless StrictEnums is set, newer servers may send values this library does not know.
+func (s *ServerConnection) UnknownEnums() []UnknownEnumError {
+	s.enums.mu.Lock()
+	defer s.enums.mu.Unlock()
+	return append([]UnknownEnumError(nil), s.enums.unknown...)
+}
+
+// unmarshal decodes the response and checks values of all enums in it
+func (s *ServerConnection) unmarshal(data []byte, v interface{}) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	var unknown []UnknownEnumError
+	findUnknownEnums(reflect.ValueOf(v), &unknown)
+	if len(unknown) == 0 {
+		return nil
+	}
+	s.enums.mu.Lock()
+	defer s.enums.mu.Unlock()
+	for _, e := range unknown {
+		if !containsUnknownEnum(s.enums.unknown, e) {
+			s.enums.unknown = append(s.enums.unknown, e)
+		}
+	}
+	if s.StrictEnums {
+		return &unknown[0]
+	}
+	return nil
+}
+
+// findUnknownEnums walks the decoded value and appends enums with unknown value. Empty value means not set and is accepted.
+func findUnknownEnums(v reflect.Value, unknown *[]UnknownEnumError) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			findUnknownEnums(v.Elem(), unknown)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				findUnknownEnums(v.Field(i), unknown)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			findUnknownEnums(v.Index(i), unknown)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			findUnknownEnums(iter.Key(), unknown)
+			findUnknownEnums(iter.Value(), unknown)
+		}
+	case reflect.String:
+		if v.Type().Implements(enumType) && v.Len() > 0 && !v.Interface().(enum).Valid() {
+			*unknown = append(*unknown, UnknownEnumError{Type: v.Type().Name(), Value: v.String()})
+		}
+	}
+}
+
+func containsUnknownEnum(list []UnknownEnumError, e UnknownEnumError) bool {
+	for _, item := range list {
+		if item == e {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,2889 @@
+// Code generated by enumgen; DO NOT EDIT.
+
+package connect
+
+// AccessPolicyConnectionRuleTypeValues - All known values of AccessPolicyConnectionRuleType
+var AccessPolicyConnectionRuleTypeValues = []AccessPolicyConnectionRuleType{ServiceAllowed, ServiceDenied, ServiceIpAllowed, ServiceIpDenied}
+
+// Valid - Return true if the value is known
+func (v AccessPolicyConnectionRuleType) Valid() bool {
+	switch v {
+	case ServiceAllowed, ServiceDenied, ServiceIpAllowed, ServiceIpDenied:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v AccessPolicyConnectionRuleType) String() string {
+	return string(v)
+}
+
+// ParseAccessPolicyConnectionRuleType - Convert the string to AccessPolicyConnectionRuleType, unknown value is an error
+func ParseAccessPolicyConnectionRuleType(value string) (AccessPolicyConnectionRuleType, error) {
+	v := AccessPolicyConnectionRuleType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "AccessPolicyConnectionRuleType", Value: value}
+	}
+	return v, nil
+}
+
+// AddressTypeValues - All known values of AddressType
+var AddressTypeValues = []AddressType{AllAddresses, Localhost, RealIpAddress}
+
+// Valid - Return true if the value is known
+func (v AddressType) Valid() bool {
+	switch v {
+	case AllAddresses, Localhost, RealIpAddress:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v AddressType) String() string {
+	return string(v)
+}
+
+// ParseAddressType - Convert the string to AddressType, unknown value is an error
+func ParseAddressType(value string) (AddressType, error) {
+	v := AddressType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "AddressType", Value: value}
+	}
+	return v, nil
+}
+
+// AlertNameValues - All known values of AlertName
+var AlertNameValues = []AlertName{LicenseExpired, LicenseInvalidMinVersion, LicenseInvalidEdition, LicenseInvalidUser, LicenseInvalidDomain, LicenseInvalidOS, LicenseCheckForwardingEnabled, LicenseTooManyUsers, StorageSpaceLow, SubscriptionExpired, SubscriptionSoonExpire, LicenseSoonExpire, CoredumpFound, MacOSServicesKeepsPorts, RemoteUpgradeFailed, RemoteUpgradeSucceeded}
+
+// Valid - Return true if the value is known
+func (v AlertName) Valid() bool {
+	switch v {
+	case LicenseExpired, LicenseInvalidMinVersion, LicenseInvalidEdition, LicenseInvalidUser, LicenseInvalidDomain, LicenseInvalidOS, LicenseCheckForwardingEnabled, LicenseTooManyUsers, StorageSpaceLow, SubscriptionExpired, SubscriptionSoonExpire, LicenseSoonExpire, CoredumpFound, MacOSServicesKeepsPorts, RemoteUpgradeFailed, RemoteUpgradeSucceeded:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v AlertName) String() string {
+	return string(v)
+}
+
+// ParseAlertName - Convert the string to AlertName, unknown value is an error
+func ParseAlertName(value string) (AlertName, error) {
+	v := AlertName(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "AlertName", Value: value}
+	}
+	return v, nil
+}
+
+// AliasTargetTypeValues - All known values of AliasTargetType
+var AliasTargetTypeValues = []AliasTargetType{TypeUser, TypeGroup}
+
+// Valid - Return true if the value is known
+func (v AliasTargetType) Valid() bool {
+	switch v {
+	case TypeUser, TypeGroup:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v AliasTargetType) String() string {
+	return string(v)
+}
+
+// ParseAliasTargetType - Convert the string to AliasTargetType, unknown value is an error
+func ParseAliasTargetType(value string) (AliasTargetType, error) {
+	v := AliasTargetType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "AliasTargetType", Value: value}
+	}
+	return v, nil
+}
+
+// AliasTypeValues - All known values of AliasType
+var AliasTypeValues = []AliasType{TypePublicFolder, TypeEmailAddress}
+
+// Valid - Return true if the value is known
+func (v AliasType) Valid() bool {
+	switch v {
+	case TypePublicFolder, TypeEmailAddress:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v AliasType) String() string {
+	return string(v)
+}
+
+// ParseAliasType - Convert the string to AliasType, unknown value is an error
+func ParseAliasType(value string) (AliasType, error) {
+	v := AliasType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "AliasType", Value: value}
+	}
+	return v, nil
+}
+
+// AntivirusStatusValues - All known values of AntivirusStatus
+var AntivirusStatusValues = []AntivirusStatus{AntivirusOk, NoAntivirus, InternalFailure, ExternalFailure, DoubleFailer}
+
+// Valid - Return true if the value is known
+func (v AntivirusStatus) Valid() bool {
+	switch v {
+	case AntivirusOk, NoAntivirus, InternalFailure, ExternalFailure, DoubleFailer:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v AntivirusStatus) String() string {
+	return string(v)
+}
+
+// ParseAntivirusStatus - Convert the string to AntivirusStatus, unknown value is an error
+func ParseAntivirusStatus(value string) (AntivirusStatus, error) {
+	v := AntivirusStatus(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "AntivirusStatus", Value: value}
+	}
+	return v, nil
+}
+
+// AttachmentActionValues - All known values of AttachmentAction
+var AttachmentActionValues = []AttachmentAction{Block, Accept}
+
+// Valid - Return true if the value is known
+func (v AttachmentAction) Valid() bool {
+	switch v {
+	case Block, Accept:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v AttachmentAction) String() string {
+	return string(v)
+}
+
+// ParseAttachmentAction - Convert the string to AttachmentAction, unknown value is an error
+func ParseAttachmentAction(value string) (AttachmentAction, error) {
+	v := AttachmentAction(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "AttachmentAction", Value: value}
+	}
+	return v, nil
+}
+
+// AttachmentTypeValues - All known values of AttachmentType
+var AttachmentTypeValues = []AttachmentType{FileName, MimeType}
+
+// Valid - Return true if the value is known
+func (v AttachmentType) Valid() bool {
+	switch v {
+	case FileName, MimeType:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v AttachmentType) String() string {
+	return string(v)
+}
+
+// ParseAttachmentType - Convert the string to AttachmentType, unknown value is an error
+func ParseAttachmentType(value string) (AttachmentType, error) {
+	v := AttachmentType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "AttachmentType", Value: value}
+	}
+	return v, nil
+}
+
+// AuthResultValues - All known values of AuthResult
+var AuthResultValues = []AuthResult{AuthOK, AuthFail, AuthUserDisabled, AuthLicense, AuthDenied, AuthTryLater}
+
+// Valid - Return true if the value is known
+func (v AuthResult) Valid() bool {
+	switch v {
+	case AuthOK, AuthFail, AuthUserDisabled, AuthLicense, AuthDenied, AuthTryLater:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v AuthResult) String() string {
+	return string(v)
+}
+
+// ParseAuthResult - Convert the string to AuthResult, unknown value is an error
+func ParseAuthResult(value string) (AuthResult, error) {
+	v := AuthResult(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "AuthResult", Value: value}
+	}
+	return v, nil
+}
+
+// BackupTypeValues - All known values of BackupType
+var BackupTypeValues = []BackupType{BackupTypeFull, BackupTypeDifferential, BackupTypeMirror}
+
+// Valid - Return true if the value is known
+func (v BackupType) Valid() bool {
+	switch v {
+	case BackupTypeFull, BackupTypeDifferential, BackupTypeMirror:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v BackupType) String() string {
+	return string(v)
+}
+
+// ParseBackupType - Convert the string to BackupType, unknown value is an error
+func ParseBackupType(value string) (BackupType, error) {
+	v := BackupType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "BackupType", Value: value}
+	}
+	return v, nil
+}
+
+// BayesStateValues - All known values of BayesState
+var BayesStateValues = []BayesState{Disabled, Learning, Active}
+
+// Valid - Return true if the value is known
+func (v BayesState) Valid() bool {
+	switch v {
+	case Disabled, Learning, Active:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v BayesState) String() string {
+	return string(v)
+}
+
+// ParseBayesState - Convert the string to BayesState, unknown value is an error
+func ParseBayesState(value string) (BayesState, error) {
+	v := BayesState(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "BayesState", Value: value}
+	}
+	return v, nil
+}
+
+// BlockOrScoreValues - All known values of BlockOrScore
+var BlockOrScoreValues = []BlockOrScore{BlockMessage, ScoreMessage}
+
+// Valid - Return true if the value is known
+func (v BlockOrScore) Valid() bool {
+	switch v {
+	case BlockMessage, ScoreMessage:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v BlockOrScore) String() string {
+	return string(v)
+}
+
+// ParseBlockOrScore - Convert the string to BlockOrScore, unknown value is an error
+func ParseBlockOrScore(value string) (BlockOrScore, error) {
+	v := BlockOrScore(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "BlockOrScore", Value: value}
+	}
+	return v, nil
+}
+
+// BuildTypeValues - All known values of BuildType
+var BuildTypeValues = []BuildType{Alpha, Beta, Rc, Final, Patch}
+
+// Valid - Return true if the value is known
+func (v BuildType) Valid() bool {
+	switch v {
+	case Alpha, Beta, Rc, Final, Patch:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v BuildType) String() string {
+	return string(v)
+}
+
+// ParseBuildType - Convert the string to BuildType, unknown value is an error
+func ParseBuildType(value string) (BuildType, error) {
+	v := BuildType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "BuildType", Value: value}
+	}
+	return v, nil
+}
+
+// ByteUnitsValues - All known values of ByteUnits
+var ByteUnitsValues = []ByteUnits{Bytes, KiloBytes, MegaBytes, GigaBytes, TeraBytes, PetaBytes}
+
+// Valid - Return true if the value is known
+func (v ByteUnits) Valid() bool {
+	switch v {
+	case Bytes, KiloBytes, MegaBytes, GigaBytes, TeraBytes, PetaBytes:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v ByteUnits) String() string {
+	return string(v)
+}
+
+// ParseByteUnits - Convert the string to ByteUnits, unknown value is an error
+func ParseByteUnits(value string) (ByteUnits, error) {
+	v := ByteUnits(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "ByteUnits", Value: value}
+	}
+	return v, nil
+}
+
+// CertificateTypeValues - All known values of CertificateType
+var CertificateTypeValues = []CertificateType{ActiveCertificate, InactiveCertificate, CertificateRequest, Authority, LocalAuthority, BuiltInAuthority, ServerCertificate}
+
+// Valid - Return true if the value is known
+func (v CertificateType) Valid() bool {
+	switch v {
+	case ActiveCertificate, InactiveCertificate, CertificateRequest, Authority, LocalAuthority, BuiltInAuthority, ServerCertificate:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v CertificateType) String() string {
+	return string(v)
+}
+
+// ParseCertificateType - Convert the string to CertificateType, unknown value is an error
+func ParseCertificateType(value string) (CertificateType, error) {
+	v := CertificateType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "CertificateType", Value: value}
+	}
+	return v, nil
+}
+
+// ClusterConflictTargetValues - All known values of ClusterConflictTarget
+var ClusterConflictTargetValues = []ClusterConflictTarget{ClResource, ClAlias, ClMailingList, ClDomainAlias, ClDomain}
+
+// Valid - Return true if the value is known
+func (v ClusterConflictTarget) Valid() bool {
+	switch v {
+	case ClResource, ClAlias, ClMailingList, ClDomainAlias, ClDomain:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v ClusterConflictTarget) String() string {
+	return string(v)
+}
+
+// ParseClusterConflictTarget - Convert the string to ClusterConflictTarget, unknown value is an error
+func ParseClusterConflictTarget(value string) (ClusterConflictTarget, error) {
+	v := ClusterConflictTarget(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "ClusterConflictTarget", Value: value}
+	}
+	return v, nil
+}
+
+// ClusterDomainStatusValues - All known values of ClusterDomainStatus
+var ClusterDomainStatusValues = []ClusterDomainStatus{CsDomainNotChecked, CsDomainExists, CsDomainDoesNotExist}
+
+// Valid - Return true if the value is known
+func (v ClusterDomainStatus) Valid() bool {
+	switch v {
+	case CsDomainNotChecked, CsDomainExists, CsDomainDoesNotExist:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v ClusterDomainStatus) String() string {
+	return string(v)
+}
+
+// ParseClusterDomainStatus - Convert the string to ClusterDomainStatus, unknown value is an error
+func ParseClusterDomainStatus(value string) (ClusterDomainStatus, error) {
+	v := ClusterDomainStatus(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "ClusterDomainStatus", Value: value}
+	}
+	return v, nil
+}
+
+// ClusterErrorTypeValues - All known values of ClusterErrorType
+var ClusterErrorTypeValues = []ClusterErrorType{ClSuccess, ClError, ClSelfConnectError, ClConnectToSlaveError, ClInaccessibleHost, ClInvalidUserOrPassword, ClIncorrectClusterVersion, ClDataConflict, ClDirServiceRemoteEmpty, ClDirServiceLocalEmpty, ClDirServiceDifferent}
+
+// Valid - Return true if the value is known
+func (v ClusterErrorType) Valid() bool {
+	switch v {
+	case ClSuccess, ClError, ClSelfConnectError, ClConnectToSlaveError, ClInaccessibleHost, ClInvalidUserOrPassword, ClIncorrectClusterVersion, ClDataConflict, ClDirServiceRemoteEmpty, ClDirServiceLocalEmpty, ClDirServiceDifferent:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v ClusterErrorType) String() string {
+	return string(v)
+}
+
+// ParseClusterErrorType - Convert the string to ClusterErrorType, unknown value is an error
+func ParseClusterErrorType(value string) (ClusterErrorType, error) {
+	v := ClusterErrorType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "ClusterErrorType", Value: value}
+	}
+	return v, nil
+}
+
+// ClusterRoleValues - All known values of ClusterRole
+var ClusterRoleValues = []ClusterRole{ClStandalone, ClMaster, ClSlave}
+
+// Valid - Return true if the value is known
+func (v ClusterRole) Valid() bool {
+	switch v {
+	case ClStandalone, ClMaster, ClSlave:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v ClusterRole) String() string {
+	return string(v)
+}
+
+// ParseClusterRole - Convert the string to ClusterRole, unknown value is an error
+func ParseClusterRole(value string) (ClusterRole, error) {
+	v := ClusterRole(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "ClusterRole", Value: value}
+	}
+	return v, nil
+}
+
+// ClusterStatusValues - All known values of ClusterStatus
+var ClusterStatusValues = []ClusterStatus{CsReady, CsError}
+
+// Valid - Return true if the value is known
+func (v ClusterStatus) Valid() bool {
+	switch v {
+	case CsReady, CsError:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v ClusterStatus) String() string {
+	return string(v)
+}
+
+// ParseClusterStatus - Convert the string to ClusterStatus, unknown value is an error
+func ParseClusterStatus(value string) (ClusterStatus, error) {
+	v := ClusterStatus(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "ClusterStatus", Value: value}
+	}
+	return v, nil
+}
+
+// CompareOperatorValues - All known values of CompareOperator
+var CompareOperatorValues = []CompareOperator{Eq, NotEq, LessThan, GreaterThan, LessEq, GreaterEq}
+
+// Valid - Return true if the value is known
+func (v CompareOperator) Valid() bool {
+	switch v {
+	case Eq, NotEq, LessThan, GreaterThan, LessEq, GreaterEq:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v CompareOperator) String() string {
+	return string(v)
+}
+
+// ParseCompareOperator - Convert the string to CompareOperator, unknown value is an error
+func ParseCompareOperator(value string) (CompareOperator, error) {
+	v := CompareOperator(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "CompareOperator", Value: value}
+	}
+	return v, nil
+}
+
+// CustomRuleActionValues - All known values of CustomRuleAction
+var CustomRuleActionValues = []CustomRuleAction{TreatAsSpam, TreatAsNotSpam, IncreaseSpamScore}
+
+// Valid - Return true if the value is known
+func (v CustomRuleAction) Valid() bool {
+	switch v {
+	case TreatAsSpam, TreatAsNotSpam, IncreaseSpamScore:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v CustomRuleAction) String() string {
+	return string(v)
+}
+
+// ParseCustomRuleAction - Convert the string to CustomRuleAction, unknown value is an error
+func ParseCustomRuleAction(value string) (CustomRuleAction, error) {
+	v := CustomRuleAction(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "CustomRuleAction", Value: value}
+	}
+	return v, nil
+}
+
+// CustomRuleKindValues - All known values of CustomRuleKind
+var CustomRuleKindValues = []CustomRuleKind{Header, Body}
+
+// Valid - Return true if the value is known
+func (v CustomRuleKind) Valid() bool {
+	switch v {
+	case Header, Body:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v CustomRuleKind) String() string {
+	return string(v)
+}
+
+// ParseCustomRuleKind - Convert the string to CustomRuleKind, unknown value is an error
+func ParseCustomRuleKind(value string) (CustomRuleKind, error) {
+	v := CustomRuleKind(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "CustomRuleKind", Value: value}
+	}
+	return v, nil
+}
+
+// CustomRuleTypeValues - All known values of CustomRuleType
+var CustomRuleTypeValues = []CustomRuleType{IsEmpty, IsMissing, ContainsAddress, ContainsDomain, ContainsSubstring, ContainsBinary}
+
+// Valid - Return true if the value is known
+func (v CustomRuleType) Valid() bool {
+	switch v {
+	case IsEmpty, IsMissing, ContainsAddress, ContainsDomain, ContainsSubstring, ContainsBinary:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v CustomRuleType) String() string {
+	return string(v)
+}
+
+// ParseCustomRuleType - Convert the string to CustomRuleType, unknown value is an error
+func ParseCustomRuleType(value string) (CustomRuleType, error) {
+	v := CustomRuleType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "CustomRuleType", Value: value}
+	}
+	return v, nil
+}
+
+// DataSourceValues - All known values of DataSource
+var DataSourceValues = []DataSource{DSInternalSource, DSLDAPSource}
+
+// Valid - Return true if the value is known
+func (v DataSource) Valid() bool {
+	switch v {
+	case DSInternalSource, DSLDAPSource:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v DataSource) String() string {
+	return string(v)
+}
+
+// ParseDataSource - Convert the string to DataSource, unknown value is an error
+func ParseDataSource(value string) (DataSource, error) {
+	v := DataSource(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "DataSource", Value: value}
+	}
+	return v, nil
+}
+
+// DayTypeValues - All known values of DayType
+var DayTypeValues = []DayType{Monday, Tuesday, Wednesday, Thursday, Friday, Saturday, Sunday}
+
+// Valid - Return true if the value is known
+func (v DayType) Valid() bool {
+	switch v {
+	case Monday, Tuesday, Wednesday, Thursday, Friday, Saturday, Sunday:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v DayType) String() string {
+	return string(v)
+}
+
+// ParseDayType - Convert the string to DayType, unknown value is an error
+func ParseDayType(value string) (DayType, error) {
+	v := DayType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "DayType", Value: value}
+	}
+	return v, nil
+}
+
+// DayWeekMonthPeriodValues - All known values of DayWeekMonthPeriod
+var DayWeekMonthPeriodValues = []DayWeekMonthPeriod{PeriodDay, PeriodWeek, PeriodMonth}
+
+// Valid - Return true if the value is known
+func (v DayWeekMonthPeriod) Valid() bool {
+	switch v {
+	case PeriodDay, PeriodWeek, PeriodMonth:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v DayWeekMonthPeriod) String() string {
+	return string(v)
+}
+
+// ParseDayWeekMonthPeriod - Convert the string to DayWeekMonthPeriod, unknown value is an error
+func ParseDayWeekMonthPeriod(value string) (DayWeekMonthPeriod, error) {
+	v := DayWeekMonthPeriod(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "DayWeekMonthPeriod", Value: value}
+	}
+	return v, nil
+}
+
+// DeliveryTypeValues - All known values of DeliveryType
+var DeliveryTypeValues = []DeliveryType{Online, OfflineScheduler, OfflineEtrn}
+
+// Valid - Return true if the value is known
+func (v DeliveryType) Valid() bool {
+	switch v {
+	case Online, OfflineScheduler, OfflineEtrn:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v DeliveryType) String() string {
+	return string(v)
+}
+
+// ParseDeliveryType - Convert the string to DeliveryType, unknown value is an error
+func ParseDeliveryType(value string) (DeliveryType, error) {
+	v := DeliveryType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "DeliveryType", Value: value}
+	}
+	return v, nil
+}
+
+// DeployedTypeValues - All known values of DeployedType
+var DeployedTypeValues = []DeployedType{DeployedStandalone, DeployedCloud, DeployedKerioVA}
+
+// Valid - Return true if the value is known
+func (v DeployedType) Valid() bool {
+	switch v {
+	case DeployedStandalone, DeployedCloud, DeployedKerioVA:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v DeployedType) String() string {
+	return string(v)
+}
+
+// ParseDeployedType - Convert the string to DeployedType, unknown value is an error
+func ParseDeployedType(value string) (DeployedType, error) {
+	v := DeployedType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "DeployedType", Value: value}
+	}
+	return v, nil
+}
+
+// DeviceStatusValues - All known values of DeviceStatus
+var DeviceStatusValues = []DeviceStatus{OK, DeviceNotProvisioned, DeviceWipeInitiated, DeviceWipeInProgress, DeviceWipeFinished, DeviceConnected, DeviceDisconnected}
+
+// Valid - Return true if the value is known
+func (v DeviceStatus) Valid() bool {
+	switch v {
+	case OK, DeviceNotProvisioned, DeviceWipeInitiated, DeviceWipeInProgress, DeviceWipeFinished, DeviceConnected, DeviceDisconnected:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v DeviceStatus) String() string {
+	return string(v)
+}
+
+// ParseDeviceStatus - Convert the string to DeviceStatus, unknown value is an error
+func ParseDeviceStatus(value string) (DeviceStatus, error) {
+	v := DeviceStatus(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "DeviceStatus", Value: value}
+	}
+	return v, nil
+}
+
+// DirectoryAccessResultValues - All known values of DirectoryAccessResult
+var DirectoryAccessResultValues = []DirectoryAccessResult{DirectoryExists, DirectoryDoesNotExist, DirectoryExistAccessDenied, DirectoryUnaccessible}
+
+// Valid - Return true if the value is known
+func (v DirectoryAccessResult) Valid() bool {
+	switch v {
+	case DirectoryExists, DirectoryDoesNotExist, DirectoryExistAccessDenied, DirectoryUnaccessible:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v DirectoryAccessResult) String() string {
+	return string(v)
+}
+
+// ParseDirectoryAccessResult - Convert the string to DirectoryAccessResult, unknown value is an error
+func ParseDirectoryAccessResult(value string) (DirectoryAccessResult, error) {
+	v := DirectoryAccessResult(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "DirectoryAccessResult", Value: value}
+	}
+	return v, nil
+}
+
+// DirectoryServiceDeleteModeValues - All known values of DirectoryServiceDeleteMode
+var DirectoryServiceDeleteModeValues = []DirectoryServiceDeleteMode{DSModeDeactivate, DSModeDelete}
+
+// Valid - Return true if the value is known
+func (v DirectoryServiceDeleteMode) Valid() bool {
+	switch v {
+	case DSModeDeactivate, DSModeDelete:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v DirectoryServiceDeleteMode) String() string {
+	return string(v)
+}
+
+// ParseDirectoryServiceDeleteMode - Convert the string to DirectoryServiceDeleteMode, unknown value is an error
+func ParseDirectoryServiceDeleteMode(value string) (DirectoryServiceDeleteMode, error) {
+	v := DirectoryServiceDeleteMode(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "DirectoryServiceDeleteMode", Value: value}
+	}
+	return v, nil
+}
+
+// DirectoryServiceTypeValues - All known values of DirectoryServiceType
+var DirectoryServiceTypeValues = []DirectoryServiceType{WindowsActiveDirectory, AppleDirectoryKerberos, AppleDirectoryPassword, KerioDirectory, CustomLDAP}
+
+// Valid - Return true if the value is known
+func (v DirectoryServiceType) Valid() bool {
+	switch v {
+	case WindowsActiveDirectory, AppleDirectoryKerberos, AppleDirectoryPassword, KerioDirectory, CustomLDAP:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v DirectoryServiceType) String() string {
+	return string(v)
+}
+
+// ParseDirectoryServiceType - Convert the string to DirectoryServiceType, unknown value is an error
+func ParseDirectoryServiceType(value string) (DirectoryServiceType, error) {
+	v := DirectoryServiceType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "DirectoryServiceType", Value: value}
+	}
+	return v, nil
+}
+
+// DistanceTypeValues - All known values of DistanceType
+var DistanceTypeValues = []DistanceType{DtNull, DtTimeSpan}
+
+// Valid - Return true if the value is known
+func (v DistanceType) Valid() bool {
+	switch v {
+	case DtNull, DtTimeSpan:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v DistanceType) String() string {
+	return string(v)
+}
+
+// ParseDistanceType - Convert the string to DistanceType, unknown value is an error
+func ParseDistanceType(value string) (DistanceType, error) {
+	v := DistanceType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "DistanceType", Value: value}
+	}
+	return v, nil
+}
+
+// EmailAddressTypeValues - All known values of EmailAddressType
+var EmailAddressTypeValues = []EmailAddressType{EmailWork, EmailHome, EmailOther, EmailCustom, RefContact, RefDistributionList}
+
+// Valid - Return true if the value is known
+func (v EmailAddressType) Valid() bool {
+	switch v {
+	case EmailWork, EmailHome, EmailOther, EmailCustom, RefContact, RefDistributionList:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v EmailAddressType) String() string {
+	return string(v)
+}
+
+// ParseEmailAddressType - Convert the string to EmailAddressType, unknown value is an error
+func ParseEmailAddressType(value string) (EmailAddressType, error) {
+	v := EmailAddressType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "EmailAddressType", Value: value}
+	}
+	return v, nil
+}
+
+// EntityValues - All known values of Entity
+var EntityValues = []Entity{EntityUser, EntityAlias, EntityGroup, EntityMailingList, EntityResource, EntityTimeRange, EntityTimeRangeGroup, EntityIpAddress, EntityIpAddressGroup, EntityService, EntityDomain}
+
+// Valid - Return true if the value is known
+func (v Entity) Valid() bool {
+	switch v {
+	case EntityUser, EntityAlias, EntityGroup, EntityMailingList, EntityResource, EntityTimeRange, EntityTimeRangeGroup, EntityIpAddress, EntityIpAddressGroup, EntityService, EntityDomain:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v Entity) String() string {
+	return string(v)
+}
+
+// ParseEntity - Convert the string to Entity, unknown value is an error
+func ParseEntity(value string) (Entity, error) {
+	v := Entity(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "Entity", Value: value}
+	}
+	return v, nil
+}
+
+// EvaluationModeTypeValues - All known values of EvaluationModeType
+var EvaluationModeTypeValues = []EvaluationModeType{EmAnyOf, EmAllOf}
+
+// Valid - Return true if the value is known
+func (v EvaluationModeType) Valid() bool {
+	switch v {
+	case EmAnyOf, EmAllOf:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v EvaluationModeType) String() string {
+	return string(v)
+}
+
+// ParseEvaluationModeType - Convert the string to EvaluationModeType, unknown value is an error
+func ParseEvaluationModeType(value string) (EvaluationModeType, error) {
+	v := EvaluationModeType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "EvaluationModeType", Value: value}
+	}
+	return v, nil
+}
+
+// ExpireTypeValues - All known values of ExpireType
+var ExpireTypeValues = []ExpireType{License, Subscription}
+
+// Valid - Return true if the value is known
+func (v ExpireType) Valid() bool {
+	switch v {
+	case License, Subscription:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v ExpireType) String() string {
+	return string(v)
+}
+
+// ParseExpireType - Convert the string to ExpireType, unknown value is an error
+func ParseExpireType(value string) (ExpireType, error) {
+	v := ExpireType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "ExpireType", Value: value}
+	}
+	return v, nil
+}
+
+// ExportFormatValues - All known values of ExportFormat
+var ExportFormatValues = []ExportFormat{PlainText, Html}
+
+// Valid - Return true if the value is known
+func (v ExportFormat) Valid() bool {
+	switch v {
+	case PlainText, Html:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v ExportFormat) String() string {
+	return string(v)
+}
+
+// ParseExportFormat - Convert the string to ExportFormat, unknown value is an error
+func ParseExportFormat(value string) (ExportFormat, error) {
+	v := ExportFormat(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "ExportFormat", Value: value}
+	}
+	return v, nil
+}
+
+// FacilityUnitValues - All known values of FacilityUnit
+var FacilityUnitValues = []FacilityUnit{FacilityKernel, FacilityUserLevel, FacilityMailSystem, FacilitySystemDaemons, FacilitySecurity1, FacilityInternal, FacilityLinePrinter, FacilityNetworkNews, FacilityUucpSubsystem, FacilityClockDaemon1, FacilitySecurity2, FacilityFtpDaemon, FacilityNtpSubsystem, FacilityLogAudit, FacilityLogAlert, FacilityClockDaemon2, FacilityLocal0, FacilityLocal1, FacilityLocal2, FacilityLocal3, FacilityLocal4, FacilityLocal5, FacilityLocal6, FacilityLocal7}
+
+// Valid - Return true if the value is known
+func (v FacilityUnit) Valid() bool {
+	switch v {
+	case FacilityKernel, FacilityUserLevel, FacilityMailSystem, FacilitySystemDaemons, FacilitySecurity1, FacilityInternal, FacilityLinePrinter, FacilityNetworkNews, FacilityUucpSubsystem, FacilityClockDaemon1, FacilitySecurity2, FacilityFtpDaemon, FacilityNtpSubsystem, FacilityLogAudit, FacilityLogAlert, FacilityClockDaemon2, FacilityLocal0, FacilityLocal1, FacilityLocal2, FacilityLocal3, FacilityLocal4, FacilityLocal5, FacilityLocal6, FacilityLocal7:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v FacilityUnit) String() string {
+	return string(v)
+}
+
+// ParseFacilityUnit - Convert the string to FacilityUnit, unknown value is an error
+func ParseFacilityUnit(value string) (FacilityUnit, error) {
+	v := FacilityUnit(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "FacilityUnit", Value: value}
+	}
+	return v, nil
+}
+
+// FileFormatTypeValues - All known values of FileFormatType
+var FileFormatTypeValues = []FileFormatType{TypeXml, TypeCsv}
+
+// Valid - Return true if the value is known
+func (v FileFormatType) Valid() bool {
+	switch v {
+	case TypeXml, TypeCsv:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v FileFormatType) String() string {
+	return string(v)
+}
+
+// ParseFileFormatType - Convert the string to FileFormatType, unknown value is an error
+func ParseFileFormatType(value string) (FileFormatType, error) {
+	v := FileFormatType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "FileFormatType", Value: value}
+	}
+	return v, nil
+}
+
+// FilterActionTypeValues - All known values of FilterActionType
+var FilterActionTypeValues = []FilterActionType{FaAddHeader, FaSetHeader, FaRemoveHeader, FaAddRecipient, FaCopyToAddress, FaReject, FaFileInto, FaRedirect, FaDiscard, FaKeep, FaNotify, FaSetReadFlag, FaAutoReply, FaStop}
+
+// Valid - Return true if the value is known
+func (v FilterActionType) Valid() bool {
+	switch v {
+	case FaAddHeader, FaSetHeader, FaRemoveHeader, FaAddRecipient, FaCopyToAddress, FaReject, FaFileInto, FaRedirect, FaDiscard, FaKeep, FaNotify, FaSetReadFlag, FaAutoReply, FaStop:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v FilterActionType) String() string {
+	return string(v)
+}
+
+// ParseFilterActionType - Convert the string to FilterActionType, unknown value is an error
+func ParseFilterActionType(value string) (FilterActionType, error) {
+	v := FilterActionType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "FilterActionType", Value: value}
+	}
+	return v, nil
+}
+
+// FilterComparatorTypeValues - All known values of FilterComparatorType
+var FilterComparatorTypeValues = []FilterComparatorType{CcEqual, CcContain, CcNotContain, CcNotEqual, CcUnder, CcOver, CcNoComparator}
+
+// Valid - Return true if the value is known
+func (v FilterComparatorType) Valid() bool {
+	switch v {
+	case CcEqual, CcContain, CcNotContain, CcNotEqual, CcUnder, CcOver, CcNoComparator:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v FilterComparatorType) String() string {
+	return string(v)
+}
+
+// ParseFilterComparatorType - Convert the string to FilterComparatorType, unknown value is an error
+func ParseFilterComparatorType(value string) (FilterComparatorType, error) {
+	v := FilterComparatorType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "FilterComparatorType", Value: value}
+	}
+	return v, nil
+}
+
+// FilterConditionTypeValues - All known values of FilterConditionType
+var FilterConditionTypeValues = []FilterConditionType{CtEnvelopeRecipient, CtEnvelopeSender, CtRecipient, CtSender, CtFrom, CtCc, CtTo, CtSubject, CtAttachment, CtSize, CtSpam, CtAll}
+
+// Valid - Return true if the value is known
+func (v FilterConditionType) Valid() bool {
+	switch v {
+	case CtEnvelopeRecipient, CtEnvelopeSender, CtRecipient, CtSender, CtFrom, CtCc, CtTo, CtSubject, CtAttachment, CtSize, CtSpam, CtAll:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v FilterConditionType) String() string {
+	return string(v)
+}
+
+// ParseFilterConditionType - Convert the string to FilterConditionType, unknown value is an error
+func ParseFilterConditionType(value string) (FilterConditionType, error) {
+	v := FilterConditionType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "FilterConditionType", Value: value}
+	}
+	return v, nil
+}
+
+// FolderIconValues - All known values of FolderIcon
+var FolderIconValues = []FolderIcon{FIMail, FIContact, FICalendar, FITodo, FIJournal, FINote, FIInbox, FIDeleted}
+
+// Valid - Return true if the value is known
+func (v FolderIcon) Valid() bool {
+	switch v {
+	case FIMail, FIContact, FICalendar, FITodo, FIJournal, FINote, FIInbox, FIDeleted:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v FolderIcon) String() string {
+	return string(v)
+}
+
+// ParseFolderIcon - Convert the string to FolderIcon, unknown value is an error
+func ParseFolderIcon(value string) (FolderIcon, error) {
+	v := FolderIcon(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "FolderIcon", Value: value}
+	}
+	return v, nil
+}
+
+// FulltextScopeValues - All known values of FulltextScope
+var FulltextScopeValues = []FulltextScope{IndexAll, IndexDomain, IndexUser}
+
+// Valid - Return true if the value is known
+func (v FulltextScope) Valid() bool {
+	switch v {
+	case IndexAll, IndexDomain, IndexUser:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v FulltextScope) String() string {
+	return string(v)
+}
+
+// ParseFulltextScope - Convert the string to FulltextScope, unknown value is an error
+func ParseFulltextScope(value string) (FulltextScope, error) {
+	v := FulltextScope(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "FulltextScope", Value: value}
+	}
+	return v, nil
+}
+
+// FulltextStatusValues - All known values of FulltextStatus
+var FulltextStatusValues = []FulltextStatus{IndexRebuilding, IndexMessages, IndexFinished, IndexDisabled, IndexError, IndexErrorLowSpace}
+
+// Valid - Return true if the value is known
+func (v FulltextStatus) Valid() bool {
+	switch v {
+	case IndexRebuilding, IndexMessages, IndexFinished, IndexDisabled, IndexError, IndexErrorLowSpace:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v FulltextStatus) String() string {
+	return string(v)
+}
+
+// ParseFulltextStatus - Convert the string to FulltextStatus, unknown value is an error
+func ParseFulltextStatus(value string) (FulltextStatus, error) {
+	v := FulltextStatus(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "FulltextStatus", Value: value}
+	}
+	return v, nil
+}
+
+// GreylistingStatusValues - All known values of GreylistingStatus
+var GreylistingStatusValues = []GreylistingStatus{GreylistingOff, GreylistingOn, GreylistingError}
+
+// Valid - Return true if the value is known
+func (v GreylistingStatus) Valid() bool {
+	switch v {
+	case GreylistingOff, GreylistingOn, GreylistingError:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v GreylistingStatus) String() string {
+	return string(v)
+}
+
+// ParseGreylistingStatus - Convert the string to GreylistingStatus, unknown value is an error
+func ParseGreylistingStatus(value string) (GreylistingStatus, error) {
+	v := GreylistingStatus(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "GreylistingStatus", Value: value}
+	}
+	return v, nil
+}
+
+// HistogramIntervalTypeValues - All known values of HistogramIntervalType
+var HistogramIntervalTypeValues = []HistogramIntervalType{HistogramInterval5m, HistogramInterval20s, HistogramInterval30m, HistogramInterval2h}
+
+// Valid - Return true if the value is known
+func (v HistogramIntervalType) Valid() bool {
+	switch v {
+	case HistogramInterval5m, HistogramInterval20s, HistogramInterval30m, HistogramInterval2h:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v HistogramIntervalType) String() string {
+	return string(v)
+}
+
+// ParseHistogramIntervalType - Convert the string to HistogramIntervalType, unknown value is an error
+func ParseHistogramIntervalType(value string) (HistogramIntervalType, error) {
+	v := HistogramIntervalType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "HistogramIntervalType", Value: value}
+	}
+	return v, nil
+}
+
+// HistogramTypeValues - All known values of HistogramType
+var HistogramTypeValues = []HistogramType{HistogramOneDay, HistogramTwoHours, HistogramOneWeek, HistogramOneMonth}
+
+// Valid - Return true if the value is known
+func (v HistogramType) Valid() bool {
+	switch v {
+	case HistogramOneDay, HistogramTwoHours, HistogramOneWeek, HistogramOneMonth:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v HistogramType) String() string {
+	return string(v)
+}
+
+// ParseHistogramType - Convert the string to HistogramType, unknown value is an error
+func ParseHistogramType(value string) (HistogramType, error) {
+	v := HistogramType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "HistogramType", Value: value}
+	}
+	return v, nil
+}
+
+// HourOrDayValues - All known values of HourOrDay
+var HourOrDayValues = []HourOrDay{Hour, Day}
+
+// Valid - Return true if the value is known
+func (v HourOrDay) Valid() bool {
+	switch v {
+	case Hour, Day:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v HourOrDay) String() string {
+	return string(v)
+}
+
+// ParseHourOrDay - Convert the string to HourOrDay, unknown value is an error
+func ParseHourOrDay(value string) (HourOrDay, error) {
+	v := HourOrDay(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "HourOrDay", Value: value}
+	}
+	return v, nil
+}
+
+// HttpExtensionValues - All known values of HttpExtension
+var HttpExtensionValues = []HttpExtension{NoExtension, WebGeneric, WebDav, CalDav, ActiveSync, KocOffline, KBC, EWS}
+
+// Valid - Return true if the value is known
+func (v HttpExtension) Valid() bool {
+	switch v {
+	case NoExtension, WebGeneric, WebDav, CalDav, ActiveSync, KocOffline, KBC, EWS:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v HttpExtension) String() string {
+	return string(v)
+}
+
+// ParseHttpExtension - Convert the string to HttpExtension, unknown value is an error
+func ParseHttpExtension(value string) (HttpExtension, error) {
+	v := HttpExtension(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "HttpExtension", Value: value}
+	}
+	return v, nil
+}
+
+// IntegratedAntiSpamStatusValues - All known values of IntegratedAntiSpamStatus
+var IntegratedAntiSpamStatusValues = []IntegratedAntiSpamStatus{AntiSpamReady, AntiSpamDisabled, AntiSpamNotLicenced, AntiSpamNotInitialized, AntiSpamNotConnected}
+
+// Valid - Return true if the value is known
+func (v IntegratedAntiSpamStatus) Valid() bool {
+	switch v {
+	case AntiSpamReady, AntiSpamDisabled, AntiSpamNotLicenced, AntiSpamNotInitialized, AntiSpamNotConnected:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v IntegratedAntiSpamStatus) String() string {
+	return string(v)
+}
+
+// ParseIntegratedAntiSpamStatus - Convert the string to IntegratedAntiSpamStatus, unknown value is an error
+func ParseIntegratedAntiSpamStatus(value string) (IntegratedAntiSpamStatus, error) {
+	v := IntegratedAntiSpamStatus(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "IntegratedAntiSpamStatus", Value: value}
+	}
+	return v, nil
+}
+
+// InternetConnectionValues - All known values of InternetConnection
+var InternetConnectionValues = []InternetConnection{Permanent, Triggered, TriggeredOnRas}
+
+// Valid - Return true if the value is known
+func (v InternetConnection) Valid() bool {
+	switch v {
+	case Permanent, Triggered, TriggeredOnRas:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v InternetConnection) String() string {
+	return string(v)
+}
+
+// ParseInternetConnection - Convert the string to InternetConnection, unknown value is an error
+func ParseInternetConnection(value string) (InternetConnection, error) {
+	v := InternetConnection(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "InternetConnection", Value: value}
+	}
+	return v, nil
+}
+
+// IpAddressGroupTypeValues - All known values of IpAddressGroupType
+var IpAddressGroupTypeValues = []IpAddressGroupType{Host, Network, Range, ChildGroup, ThisMachine, IpPrefix}
+
+// Valid - Return true if the value is known
+func (v IpAddressGroupType) Valid() bool {
+	switch v {
+	case Host, Network, Range, ChildGroup, ThisMachine, IpPrefix:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v IpAddressGroupType) String() string {
+	return string(v)
+}
+
+// ParseIpAddressGroupType - Convert the string to IpAddressGroupType, unknown value is an error
+func ParseIpAddressGroupType(value string) (IpAddressGroupType, error) {
+	v := IpAddressGroupType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "IpAddressGroupType", Value: value}
+	}
+	return v, nil
+}
+
+// ItemNameValues - All known values of ItemName
+var ItemNameValues = []ItemName{Name, Description, Email, FullName, TimeItem, DateItem, DomainName}
+
+// Valid - Return true if the value is known
+func (v ItemName) Valid() bool {
+	switch v {
+	case Name, Description, Email, FullName, TimeItem, DateItem, DomainName:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v ItemName) String() string {
+	return string(v)
+}
+
+// ParseItemName - Convert the string to ItemName, unknown value is an error
+func ParseItemName(value string) (ItemName, error) {
+	v := ItemName(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "ItemName", Value: value}
+	}
+	return v, nil
+}
+
+// KoffUpgradePolicyValues - All known values of KoffUpgradePolicy
+var KoffUpgradePolicyValues = []KoffUpgradePolicy{KoffUPolicyAskVoluntary, KoffUPolicyAskRequired, KoffUPolicyAlwaysSilent, KoffUPolicyOnStartSilent, KoffUPolicyOnlyIfNecessaryAsk, KoffUPolicyOnlyIfNecessarySilent}
+
+// Valid - Return true if the value is known
+func (v KoffUpgradePolicy) Valid() bool {
+	switch v {
+	case KoffUPolicyAskVoluntary, KoffUPolicyAskRequired, KoffUPolicyAlwaysSilent, KoffUPolicyOnStartSilent, KoffUPolicyOnlyIfNecessaryAsk, KoffUPolicyOnlyIfNecessarySilent:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v KoffUpgradePolicy) String() string {
+	return string(v)
+}
+
+// ParseKoffUpgradePolicy - Convert the string to KoffUpgradePolicy, unknown value is an error
+func ParseKoffUpgradePolicy(value string) (KoffUpgradePolicy, error) {
+	v := KoffUpgradePolicy(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "KoffUpgradePolicy", Value: value}
+	}
+	return v, nil
+}
+
+// LastBackupStatusValues - All known values of LastBackupStatus
+var LastBackupStatusValues = []LastBackupStatus{BackupStatusNone, BackupStatusSuccessful, BackupStatusFailed}
+
+// Valid - Return true if the value is known
+func (v LastBackupStatus) Valid() bool {
+	switch v {
+	case BackupStatusNone, BackupStatusSuccessful, BackupStatusFailed:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v LastBackupStatus) String() string {
+	return string(v)
+}
+
+// ParseLastBackupStatus - Convert the string to LastBackupStatus, unknown value is an error
+func ParseLastBackupStatus(value string) (LastBackupStatus, error) {
+	v := LastBackupStatus(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "LastBackupStatus", Value: value}
+	}
+	return v, nil
+}
+
+// LogicalOperatorValues - All known values of LogicalOperator
+var LogicalOperatorValues = []LogicalOperator{Or, And}
+
+// Valid - Return true if the value is known
+func (v LogicalOperator) Valid() bool {
+	switch v {
+	case Or, And:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v LogicalOperator) String() string {
+	return string(v)
+}
+
+// ParseLogicalOperator - Convert the string to LogicalOperator, unknown value is an error
+func ParseLogicalOperator(value string) (LogicalOperator, error) {
+	v := LogicalOperator(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "LogicalOperator", Value: value}
+	}
+	return v, nil
+}
+
+// MessageStatusValues - All known values of MessageStatus
+var MessageStatusValues = []MessageStatus{MsExecuting, MsBackup, MsContentFiltering, MsAntivirusControl, MsLocalDelivering, MsSmtpDelivering, MsFinishing}
+
+// Valid - Return true if the value is known
+func (v MessageStatus) Valid() bool {
+	switch v {
+	case MsExecuting, MsBackup, MsContentFiltering, MsAntivirusControl, MsLocalDelivering, MsSmtpDelivering, MsFinishing:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v MessageStatus) String() string {
+	return string(v)
+}
+
+// ParseMessageStatus - Convert the string to MessageStatus, unknown value is an error
+func ParseMessageStatus(value string) (MessageStatus, error) {
+	v := MessageStatus(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "MessageStatus", Value: value}
+	}
+	return v, nil
+}
+
+// MigrationStatusEnumValues - All known values of MigrationStatusEnum
+var MigrationStatusEnumValues = []MigrationStatusEnum{MigNotStarted, MigCompressionStarted, MigCompressionFinished, MigTransferStarted, MigTransferFinished, MigDecompressionStarted, MigDecompressionFinished, MigFinished, MigCanceled, MigError}
+
+// Valid - Return true if the value is known
+func (v MigrationStatusEnum) Valid() bool {
+	switch v {
+	case MigNotStarted, MigCompressionStarted, MigCompressionFinished, MigTransferStarted, MigTransferFinished, MigDecompressionStarted, MigDecompressionFinished, MigFinished, MigCanceled, MigError:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v MigrationStatusEnum) String() string {
+	return string(v)
+}
+
+// ParseMigrationStatusEnum - Convert the string to MigrationStatusEnum, unknown value is an error
+func ParseMigrationStatusEnum(value string) (MigrationStatusEnum, error) {
+	v := MigrationStatusEnum(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "MigrationStatusEnum", Value: value}
+	}
+	return v, nil
+}
+
+// MlPermissionValues - All known values of MlPermission
+var MlPermissionValues = []MlPermission{Allowed, Moderated, Denied}
+
+// Valid - Return true if the value is known
+func (v MlPermission) Valid() bool {
+	switch v {
+	case Allowed, Moderated, Denied:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v MlPermission) String() string {
+	return string(v)
+}
+
+// ParseMlPermission - Convert the string to MlPermission, unknown value is an error
+func ParseMlPermission(value string) (MlPermission, error) {
+	v := MlPermission(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "MlPermission", Value: value}
+	}
+	return v, nil
+}
+
+// MlReplyToValues - All known values of MlReplyTo
+var MlReplyToValues = []MlReplyTo{Sender, ThisList, OtherAddress, SenderThisList}
+
+// Valid - Return true if the value is known
+func (v MlReplyTo) Valid() bool {
+	switch v {
+	case Sender, ThisList, OtherAddress, SenderThisList:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v MlReplyTo) String() string {
+	return string(v)
+}
+
+// ParseMlReplyTo - Convert the string to MlReplyTo, unknown value is an error
+func ParseMlReplyTo(value string) (MlReplyTo, error) {
+	v := MlReplyTo(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "MlReplyTo", Value: value}
+	}
+	return v, nil
+}
+
+// ModeratorPermissionValues - All known values of ModeratorPermission
+var ModeratorPermissionValues = []ModeratorPermission{PostAllowed, PostModerated, PostAccordingMembership}
+
+// Valid - Return true if the value is known
+func (v ModeratorPermission) Valid() bool {
+	switch v {
+	case PostAllowed, PostModerated, PostAccordingMembership:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v ModeratorPermission) String() string {
+	return string(v)
+}
+
+// ParseModeratorPermission - Convert the string to ModeratorPermission, unknown value is an error
+func ParseModeratorPermission(value string) (ModeratorPermission, error) {
+	v := ModeratorPermission(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "ModeratorPermission", Value: value}
+	}
+	return v, nil
+}
+
+// NotificationTypeValues - All known values of NotificationType
+var NotificationTypeValues = []NotificationType{NotifyOnce, NotifyEvery}
+
+// Valid - Return true if the value is known
+func (v NotificationType) Valid() bool {
+	switch v {
+	case NotifyOnce, NotifyEvery:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v NotificationType) String() string {
+	return string(v)
+}
+
+// ParseNotificationType - Convert the string to NotificationType, unknown value is an error
+func ParseNotificationType(value string) (NotificationType, error) {
+	v := NotificationType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "NotificationType", Value: value}
+	}
+	return v, nil
+}
+
+// PhoneNumberTypeValues - All known values of PhoneNumberType
+var PhoneNumberTypeValues = []PhoneNumberType{TypeAssistant, TypeWorkVoice, TypeWorkFax, TypeCallback, TypeCar, TypeCompany, TypeHomeVoice, TypeHomeFax, TypeIsdn, TypeMobile, TypeOtherVoice, TypeOtherFax, TypePager, TypePrimary, TypeRadio, TypeTelex, TypeTtyTdd, TypeCustom}
+
+// Valid - Return true if the value is known
+func (v PhoneNumberType) Valid() bool {
+	switch v {
+	case TypeAssistant, TypeWorkVoice, TypeWorkFax, TypeCallback, TypeCar, TypeCompany, TypeHomeVoice, TypeHomeFax, TypeIsdn, TypeMobile, TypeOtherVoice, TypeOtherFax, TypePager, TypePrimary, TypeRadio, TypeTelex, TypeTtyTdd, TypeCustom:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v PhoneNumberType) String() string {
+	return string(v)
+}
+
+// ParsePhoneNumberType - Convert the string to PhoneNumberType, unknown value is an error
+func ParsePhoneNumberType(value string) (PhoneNumberType, error) {
+	v := PhoneNumberType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "PhoneNumberType", Value: value}
+	}
+	return v, nil
+}
+
+// Pop3AuthenticationValues - All known values of Pop3Authentication
+var Pop3AuthenticationValues = []Pop3Authentication{PlainPop3, Apop}
+
+// Valid - Return true if the value is known
+func (v Pop3Authentication) Valid() bool {
+	switch v {
+	case PlainPop3, Apop:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v Pop3Authentication) String() string {
+	return string(v)
+}
+
+// ParsePop3Authentication - Convert the string to Pop3Authentication, unknown value is an error
+func ParsePop3Authentication(value string) (Pop3Authentication, error) {
+	v := Pop3Authentication(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "Pop3Authentication", Value: value}
+	}
+	return v, nil
+}
+
+// PostalAddressTypeValues - All known values of PostalAddressType
+var PostalAddressTypeValues = []PostalAddressType{AddressHome, AddressWork, AddressOther, AddressCustom}
+
+// Valid - Return true if the value is known
+func (v PostalAddressType) Valid() bool {
+	switch v {
+	case AddressHome, AddressWork, AddressOther, AddressCustom:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v PostalAddressType) String() string {
+	return string(v)
+}
+
+// ParsePostalAddressType - Convert the string to PostalAddressType, unknown value is an error
+func ParsePostalAddressType(value string) (PostalAddressType, error) {
+	v := PostalAddressType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "PostalAddressType", Value: value}
+	}
+	return v, nil
+}
+
+// PrincipalTypeValues - All known values of PrincipalType
+var PrincipalTypeValues = []PrincipalType{AuthDomainPrincipal, AuthUserPrincipal, AnyonePrincipal, UserPrincipal, GroupPrincipal, NonePrincipal}
+
+// Valid - Return true if the value is known
+func (v PrincipalType) Valid() bool {
+	switch v {
+	case AuthDomainPrincipal, AuthUserPrincipal, AnyonePrincipal, UserPrincipal, GroupPrincipal, NonePrincipal:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v PrincipalType) String() string {
+	return string(v)
+}
+
+// ParsePrincipalType - Convert the string to PrincipalType, unknown value is an error
+func ParsePrincipalType(value string) (PrincipalType, error) {
+	v := PrincipalType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "PrincipalType", Value: value}
+	}
+	return v, nil
+}
+
+// ProtocolValues - All known values of Protocol
+var ProtocolValues = []Protocol{ProtocolAdmin, ProtocolSmtp, ProtocolSmtps, ProtocolSubmission, ProtocolPop3, ProtocolPop3s, ProtocolImap, ProtocolImaps, ProtocolNntp, ProtocolNntps, ProtocolLdap, ProtocolLdaps, ProtocolHttp, ProtocolHttps, ProtocolXmpp, ProtocolXmpps}
+
+// Valid - Return true if the value is known
+func (v Protocol) Valid() bool {
+	switch v {
+	case ProtocolAdmin, ProtocolSmtp, ProtocolSmtps, ProtocolSubmission, ProtocolPop3, ProtocolPop3s, ProtocolImap, ProtocolImaps, ProtocolNntp, ProtocolNntps, ProtocolLdap, ProtocolLdaps, ProtocolHttp, ProtocolHttps, ProtocolXmpp, ProtocolXmpps:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v Protocol) String() string {
+	return string(v)
+}
+
+// ParseProtocol - Convert the string to Protocol, unknown value is an error
+func ParseProtocol(value string) (Protocol, error) {
+	v := Protocol(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "Protocol", Value: value}
+	}
+	return v, nil
+}
+
+// ProtocolTypeValues - All known values of ProtocolType
+var ProtocolTypeValues = []ProtocolType{ProtocolASync, ProtocolKBC}
+
+// Valid - Return true if the value is known
+func (v ProtocolType) Valid() bool {
+	switch v {
+	case ProtocolASync, ProtocolKBC:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v ProtocolType) String() string {
+	return string(v)
+}
+
+// ParseProtocolType - Convert the string to ProtocolType, unknown value is an error
+func ParseProtocolType(value string) (ProtocolType, error) {
+	v := ProtocolType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "ProtocolType", Value: value}
+	}
+	return v, nil
+}
+
+// ReactionOnNotScannedValues - All known values of ReactionOnNotScanned
+var ReactionOnNotScannedValues = []ReactionOnNotScanned{DeliverWithWarning, SameAsVirus}
+
+// Valid - Return true if the value is known
+func (v ReactionOnNotScanned) Valid() bool {
+	switch v {
+	case DeliverWithWarning, SameAsVirus:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v ReactionOnNotScanned) String() string {
+	return string(v)
+}
+
+// ParseReactionOnNotScanned - Convert the string to ReactionOnNotScanned, unknown value is an error
+func ParseReactionOnNotScanned(value string) (ReactionOnNotScanned, error) {
+	v := ReactionOnNotScanned(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "ReactionOnNotScanned", Value: value}
+	}
+	return v, nil
+}
+
+// ReactionOnVirusValues - All known values of ReactionOnVirus
+var ReactionOnVirusValues = []ReactionOnVirus{DiscardMessage, RemoveVirus}
+
+// Valid - Return true if the value is known
+func (v ReactionOnVirus) Valid() bool {
+	switch v {
+	case DiscardMessage, RemoveVirus:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v ReactionOnVirus) String() string {
+	return string(v)
+}
+
+// ParseReactionOnVirus - Convert the string to ReactionOnVirus, unknown value is an error
+func ParseReactionOnVirus(value string) (ReactionOnVirus, error) {
+	v := ReactionOnVirus(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "ReactionOnVirus", Value: value}
+	}
+	return v, nil
+}
+
+// RegistrationFinishTypeValues - All known values of RegistrationFinishType
+var RegistrationFinishTypeValues = []RegistrationFinishType{RfCreate, RfModify, RfDownload, RfStore}
+
+// Valid - Return true if the value is known
+func (v RegistrationFinishType) Valid() bool {
+	switch v {
+	case RfCreate, RfModify, RfDownload, RfStore:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v RegistrationFinishType) String() string {
+	return string(v)
+}
+
+// ParseRegistrationFinishType - Convert the string to RegistrationFinishType, unknown value is an error
+func ParseRegistrationFinishType(value string) (RegistrationFinishType, error) {
+	v := RegistrationFinishType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "RegistrationFinishType", Value: value}
+	}
+	return v, nil
+}
+
+// RegistrationTypeValues - All known values of RegistrationType
+var RegistrationTypeValues = []RegistrationType{RsNoRegistration, RsTrialRegistered, RsTrialExpired, RsProductRegistered}
+
+// Valid - Return true if the value is known
+func (v RegistrationType) Valid() bool {
+	switch v {
+	case RsNoRegistration, RsTrialRegistered, RsTrialExpired, RsProductRegistered:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v RegistrationType) String() string {
+	return string(v)
+}
+
+// ParseRegistrationType - Convert the string to RegistrationType, unknown value is an error
+func ParseRegistrationType(value string) (RegistrationType, error) {
+	v := RegistrationType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "RegistrationType", Value: value}
+	}
+	return v, nil
+}
+
+// RelayRuleComparatorTypeValues - All known values of RelayRuleComparatorType
+var RelayRuleComparatorTypeValues = []RelayRuleComparatorType{RelayCompEqual, RelayCompNotEqual}
+
+// Valid - Return true if the value is known
+func (v RelayRuleComparatorType) Valid() bool {
+	switch v {
+	case RelayCompEqual, RelayCompNotEqual:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v RelayRuleComparatorType) String() string {
+	return string(v)
+}
+
+// ParseRelayRuleComparatorType - Convert the string to RelayRuleComparatorType, unknown value is an error
+func ParseRelayRuleComparatorType(value string) (RelayRuleComparatorType, error) {
+	v := RelayRuleComparatorType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "RelayRuleComparatorType", Value: value}
+	}
+	return v, nil
+}
+
+// RelayRuleCondTypeValues - All known values of RelayRuleCondType
+var RelayRuleCondTypeValues = []RelayRuleCondType{RelayCondNone, RelayCondRecipient, RelayCondSender}
+
+// Valid - Return true if the value is known
+func (v RelayRuleCondType) Valid() bool {
+	switch v {
+	case RelayCondNone, RelayCondRecipient, RelayCondSender:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v RelayRuleCondType) String() string {
+	return string(v)
+}
+
+// ParseRelayRuleCondType - Convert the string to RelayRuleCondType, unknown value is an error
+func ParseRelayRuleCondType(value string) (RelayRuleCondType, error) {
+	v := RelayRuleCondType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "RelayRuleCondType", Value: value}
+	}
+	return v, nil
+}
+
+// ResourceTypeValues - All known values of ResourceType
+var ResourceTypeValues = []ResourceType{Room, Equipment}
+
+// Valid - Return true if the value is known
+func (v ResourceType) Valid() bool {
+	switch v {
+	case Room, Equipment:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v ResourceType) String() string {
+	return string(v)
+}
+
+// ParseResourceType - Convert the string to ResourceType, unknown value is an error
+func ParseResourceType(value string) (ResourceType, error) {
+	v := ResourceType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "ResourceType", Value: value}
+	}
+	return v, nil
+}
+
+// RestrictionKindValues - All known values of RestrictionKind
+var RestrictionKindValues = []RestrictionKind{Regex, ByteLength, ForbiddenNameList, ForbiddenPrefixList, ForbiddenSuffixList, ForbiddenCharacterList}
+
+// Valid - Return true if the value is known
+func (v RestrictionKind) Valid() bool {
+	switch v {
+	case Regex, ByteLength, ForbiddenNameList, ForbiddenPrefixList, ForbiddenSuffixList, ForbiddenCharacterList:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v RestrictionKind) String() string {
+	return string(v)
+}
+
+// ParseRestrictionKind - Convert the string to RestrictionKind, unknown value is an error
+func ParseRestrictionKind(value string) (RestrictionKind, error) {
+	v := RestrictionKind(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "RestrictionKind", Value: value}
+	}
+	return v, nil
+}
+
+// RotationPeriodValues - All known values of RotationPeriod
+var RotationPeriodValues = []RotationPeriod{RotateNever, RotateHourly, RotateDaily, RotateWeekly, RotateMonthly}
+
+// Valid - Return true if the value is known
+func (v RotationPeriod) Valid() bool {
+	switch v {
+	case RotateNever, RotateHourly, RotateDaily, RotateWeekly, RotateMonthly:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v RotationPeriod) String() string {
+	return string(v)
+}
+
+// ParseRotationPeriod - Convert the string to RotationPeriod, unknown value is an error
+func ParseRotationPeriod(value string) (RotationPeriod, error) {
+	v := RotationPeriod(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "RotationPeriod", Value: value}
+	}
+	return v, nil
+}
+
+// SearchStatusValues - All known values of SearchStatus
+var SearchStatusValues = []SearchStatus{ResultFound, Searching, Cancelled, ResultNotFound}
+
+// Valid - Return true if the value is known
+func (v SearchStatus) Valid() bool {
+	switch v {
+	case ResultFound, Searching, Cancelled, ResultNotFound:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v SearchStatus) String() string {
+	return string(v)
+}
+
+// ParseSearchStatus - Convert the string to SearchStatus, unknown value is an error
+func ParseSearchStatus(value string) (SearchStatus, error) {
+	v := SearchStatus(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "SearchStatus", Value: value}
+	}
+	return v, nil
+}
+
+// SecurityPolicyModeValues - All known values of SecurityPolicyMode
+var SecurityPolicyModeValues = []SecurityPolicyMode{SPNoRestrictions, SPAuthenticationRequired, SPEncryptionRequired}
+
+// Valid - Return true if the value is known
+func (v SecurityPolicyMode) Valid() bool {
+	switch v {
+	case SPNoRestrictions, SPAuthenticationRequired, SPEncryptionRequired:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v SecurityPolicyMode) String() string {
+	return string(v)
+}
+
+// ParseSecurityPolicyMode - Convert the string to SecurityPolicyMode, unknown value is an error
+func ParseSecurityPolicyMode(value string) (SecurityPolicyMode, error) {
+	v := SecurityPolicyMode(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "SecurityPolicyMode", Value: value}
+	}
+	return v, nil
+}
+
+// ServerDirectoryTypeValues - All known values of ServerDirectoryType
+var ServerDirectoryTypeValues = []ServerDirectoryType{WinNT, ActiveDirectory, NovellEDirectory}
+
+// Valid - Return true if the value is known
+func (v ServerDirectoryType) Valid() bool {
+	switch v {
+	case WinNT, ActiveDirectory, NovellEDirectory:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v ServerDirectoryType) String() string {
+	return string(v)
+}
+
+// ParseServerDirectoryType - Convert the string to ServerDirectoryType, unknown value is an error
+func ParseServerDirectoryType(value string) (ServerDirectoryType, error) {
+	v := ServerDirectoryType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "ServerDirectoryType", Value: value}
+	}
+	return v, nil
+}
+
+// ServerOsValues - All known values of ServerOs
+var ServerOsValues = []ServerOs{Windows, MacOs, Linux}
+
+// Valid - Return true if the value is known
+func (v ServerOs) Valid() bool {
+	switch v {
+	case Windows, MacOs, Linux:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v ServerOs) String() string {
+	return string(v)
+}
+
+// ParseServerOs - Convert the string to ServerOs, unknown value is an error
+func ParseServerOs(value string) (ServerOs, error) {
+	v := ServerOs(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "ServerOs", Value: value}
+	}
+	return v, nil
+}
+
+// ServiceTypeValues - All known values of ServiceType
+var ServiceTypeValues = []ServiceType{ServiceActiveSync, ServiceEWS, ServiceIMAP, ServiceKoff, ServicePOP3, ServiceWebDAV, ServiceWebMail, ServiceXMPP}
+
+// Valid - Return true if the value is known
+func (v ServiceType) Valid() bool {
+	switch v {
+	case ServiceActiveSync, ServiceEWS, ServiceIMAP, ServiceKoff, ServicePOP3, ServiceWebDAV, ServiceWebMail, ServiceXMPP:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v ServiceType) String() string {
+	return string(v)
+}
+
+// ParseServiceType - Convert the string to ServiceType, unknown value is an error
+func ParseServiceType(value string) (ServiceType, error) {
+	v := ServiceType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "ServiceType", Value: value}
+	}
+	return v, nil
+}
+
+// SeverityUnitValues - All known values of SeverityUnit
+var SeverityUnitValues = []SeverityUnit{SeverityEmergency, SeverityAlert, SeverityCritical, SeverityError, SeverityWarning, SeverityNotice, SeverityInformational, SeverityDebug}
+
+// Valid - Return true if the value is known
+func (v SeverityUnit) Valid() bool {
+	switch v {
+	case SeverityEmergency, SeverityAlert, SeverityCritical, SeverityError, SeverityWarning, SeverityNotice, SeverityInformational, SeverityDebug:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v SeverityUnit) String() string {
+	return string(v)
+}
+
+// ParseSeverityUnit - Convert the string to SeverityUnit, unknown value is an error
+func ParseSeverityUnit(value string) (SeverityUnit, error) {
+	v := SeverityUnit(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "SeverityUnit", Value: value}
+	}
+	return v, nil
+}
+
+// SmtpAuthenticationValues - All known values of SmtpAuthentication
+var SmtpAuthenticationValues = []SmtpAuthentication{Auth, Pop3Based}
+
+// Valid - Return true if the value is known
+func (v SmtpAuthentication) Valid() bool {
+	switch v {
+	case Auth, Pop3Based:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v SmtpAuthentication) String() string {
+	return string(v)
+}
+
+// ParseSmtpAuthentication - Convert the string to SmtpAuthentication, unknown value is an error
+func ParseSmtpAuthentication(value string) (SmtpAuthentication, error) {
+	v := SmtpAuthentication(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "SmtpAuthentication", Value: value}
+	}
+	return v, nil
+}
+
+// SortDirectionValues - All known values of SortDirection
+var SortDirectionValues = []SortDirection{Asc, Desc}
+
+// Valid - Return true if the value is known
+func (v SortDirection) Valid() bool {
+	switch v {
+	case Asc, Desc:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v SortDirection) String() string {
+	return string(v)
+}
+
+// ParseSortDirection - Convert the string to SortDirection, unknown value is an error
+func ParseSortDirection(value string) (SortDirection, error) {
+	v := SortDirection(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "SortDirection", Value: value}
+	}
+	return v, nil
+}
+
+// SpamActionValues - All known values of SpamAction
+var SpamActionValues = []SpamAction{LogToSecurity, BlockAction, ScoreAction}
+
+// Valid - Return true if the value is known
+func (v SpamAction) Valid() bool {
+	switch v {
+	case LogToSecurity, BlockAction, ScoreAction:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v SpamAction) String() string {
+	return string(v)
+}
+
+// ParseSpamAction - Convert the string to SpamAction, unknown value is an error
+func ParseSpamAction(value string) (SpamAction, error) {
+	v := SpamAction(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "SpamAction", Value: value}
+	}
+	return v, nil
+}
+
+// SslModeValues - All known values of SslMode
+var SslModeValues = []SslMode{NoSsl, SpecialPort, StlsCommand}
+
+// Valid - Return true if the value is known
+func (v SslMode) Valid() bool {
+	switch v {
+	case NoSsl, SpecialPort, StlsCommand:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v SslMode) String() string {
+	return string(v)
+}
+
+// ParseSslMode - Convert the string to SslMode, unknown value is an error
+func ParseSslMode(value string) (SslMode, error) {
+	v := SslMode(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "SslMode", Value: value}
+	}
+	return v, nil
+}
+
+// StartUpValues - All known values of StartUp
+var StartUpValues = []StartUp{Manual, Automatic}
+
+// Valid - Return true if the value is known
+func (v StartUp) Valid() bool {
+	switch v {
+	case Manual, Automatic:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v StartUp) String() string {
+	return string(v)
+}
+
+// ParseStartUp - Convert the string to StartUp, unknown value is an error
+func ParseStartUp(value string) (StartUp, error) {
+	v := StartUp(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "StartUp", Value: value}
+	}
+	return v, nil
+}
+
+// StoreStatusValues - All known values of StoreStatus
+var StoreStatusValues = []StoreStatus{StoreStatusClean, StoreStatusModified, StoreStatusNew}
+
+// Valid - Return true if the value is known
+func (v StoreStatus) Valid() bool {
+	switch v {
+	case StoreStatusClean, StoreStatusModified, StoreStatusNew:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v StoreStatus) String() string {
+	return string(v)
+}
+
+// ParseStoreStatus - Convert the string to StoreStatus, unknown value is an error
+func ParseStoreStatus(value string) (StoreStatus, error) {
+	v := StoreStatus(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "StoreStatus", Value: value}
+	}
+	return v, nil
+}
+
+// SyncMethodValues - All known values of SyncMethod
+var SyncMethodValues = []SyncMethod{ServerWins, ClientWins}
+
+// Valid - Return true if the value is known
+func (v SyncMethod) Valid() bool {
+	switch v {
+	case ServerWins, ClientWins:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v SyncMethod) String() string {
+	return string(v)
+}
+
+// ParseSyncMethod - Convert the string to SyncMethod, unknown value is an error
+func ParseSyncMethod(value string) (SyncMethod, error) {
+	v := SyncMethod(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "SyncMethod", Value: value}
+	}
+	return v, nil
+}
+
+// TiedTimeUnitValues - All known values of TiedTimeUnit
+var TiedTimeUnitValues = []TiedTimeUnit{TMinutes, THours}
+
+// Valid - Return true if the value is known
+func (v TiedTimeUnit) Valid() bool {
+	switch v {
+	case TMinutes, THours:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v TiedTimeUnit) String() string {
+	return string(v)
+}
+
+// ParseTiedTimeUnit - Convert the string to TiedTimeUnit, unknown value is an error
+func ParseTiedTimeUnit(value string) (TiedTimeUnit, error) {
+	v := TiedTimeUnit(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "TiedTimeUnit", Value: value}
+	}
+	return v, nil
+}
+
+// TimeRangeTypeValues - All known values of TimeRangeType
+var TimeRangeTypeValues = []TimeRangeType{TimeRangeDaily, TimeRangeWeekly, TimeRangeAbsolute, TimeRangeChildGroup}
+
+// Valid - Return true if the value is known
+func (v TimeRangeType) Valid() bool {
+	switch v {
+	case TimeRangeDaily, TimeRangeWeekly, TimeRangeAbsolute, TimeRangeChildGroup:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v TimeRangeType) String() string {
+	return string(v)
+}
+
+// ParseTimeRangeType - Convert the string to TimeRangeType, unknown value is an error
+func ParseTimeRangeType(value string) (TimeRangeType, error) {
+	v := TimeRangeType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "TimeRangeType", Value: value}
+	}
+	return v, nil
+}
+
+// TimeUnitValues - All known values of TimeUnit
+var TimeUnitValues = []TimeUnit{Minutes, Hours, Days, Weeks}
+
+// Valid - Return true if the value is known
+func (v TimeUnit) Valid() bool {
+	switch v {
+	case Minutes, Hours, Days, Weeks:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v TimeUnit) String() string {
+	return string(v)
+}
+
+// ParseTimeUnit - Convert the string to TimeUnit, unknown value is an error
+func ParseTimeUnit(value string) (TimeUnit, error) {
+	v := TimeUnit(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "TimeUnit", Value: value}
+	}
+	return v, nil
+}
+
+// TriggerTypeValues - All known values of TriggerType
+var TriggerTypeValues = []TriggerType{Every, At}
+
+// Valid - Return true if the value is known
+func (v TriggerType) Valid() bool {
+	switch v {
+	case Every, At:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v TriggerType) String() string {
+	return string(v)
+}
+
+// ParseTriggerType - Convert the string to TriggerType, unknown value is an error
+func ParseTriggerType(value string) (TriggerType, error) {
+	v := TriggerType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "TriggerType", Value: value}
+	}
+	return v, nil
+}
+
+// TrusteeKindValues - All known values of TrusteeKind
+var TrusteeKindValues = []TrusteeKind{TrusteeUser, TrusteeGroup}
+
+// Valid - Return true if the value is known
+func (v TrusteeKind) Valid() bool {
+	switch v {
+	case TrusteeUser, TrusteeGroup:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v TrusteeKind) String() string {
+	return string(v)
+}
+
+// ParseTrusteeKind - Convert the string to TrusteeKind, unknown value is an error
+func ParseTrusteeKind(value string) (TrusteeKind, error) {
+	v := TrusteeKind(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "TrusteeKind", Value: value}
+	}
+	return v, nil
+}
+
+// TypeAlertValues - All known values of TypeAlert
+var TypeAlertValues = []TypeAlert{Warning, Critical, Info}
+
+// Valid - Return true if the value is known
+func (v TypeAlert) Valid() bool {
+	switch v {
+	case Warning, Critical, Info:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v TypeAlert) String() string {
+	return string(v)
+}
+
+// ParseTypeAlert - Convert the string to TypeAlert, unknown value is an error
+func ParseTypeAlert(value string) (TypeAlert, error) {
+	v := TypeAlert(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "TypeAlert", Value: value}
+	}
+	return v, nil
+}
+
+// TypeExpStatisticsValues - All known values of TypeExpStatistics
+var TypeExpStatisticsValues = []TypeExpStatistics{ExpStatShort, ExpStatFull}
+
+// Valid - Return true if the value is known
+func (v TypeExpStatistics) Valid() bool {
+	switch v {
+	case ExpStatShort, ExpStatFull:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v TypeExpStatistics) String() string {
+	return string(v)
+}
+
+// ParseTypeExpStatistics - Convert the string to TypeExpStatistics, unknown value is an error
+func ParseTypeExpStatistics(value string) (TypeExpStatistics, error) {
+	v := TypeExpStatistics(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "TypeExpStatistics", Value: value}
+	}
+	return v, nil
+}
+
+// UpdateCheckerStatusValues - All known values of UpdateCheckerStatus
+var UpdateCheckerStatusValues = []UpdateCheckerStatus{UpdNoUpdate, UpdNewVersion, UpdError}
+
+// Valid - Return true if the value is known
+func (v UpdateCheckerStatus) Valid() bool {
+	switch v {
+	case UpdNoUpdate, UpdNewVersion, UpdError:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v UpdateCheckerStatus) String() string {
+	return string(v)
+}
+
+// ParseUpdateCheckerStatus - Convert the string to UpdateCheckerStatus, unknown value is an error
+func ParseUpdateCheckerStatus(value string) (UpdateCheckerStatus, error) {
+	v := UpdateCheckerStatus(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "UpdateCheckerStatus", Value: value}
+	}
+	return v, nil
+}
+
+// UpdateStatusValues - All known values of UpdateStatus
+var UpdateStatusValues = []UpdateStatus{UpdateStarted, UpdateFinished, UpdateError, UpdateDownloadIni, UpdateDownloadData, UpdateUpToDate}
+
+// Valid - Return true if the value is known
+func (v UpdateStatus) Valid() bool {
+	switch v {
+	case UpdateStarted, UpdateFinished, UpdateError, UpdateDownloadIni, UpdateDownloadData, UpdateUpToDate:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v UpdateStatus) String() string {
+	return string(v)
+}
+
+// ParseUpdateStatus - Convert the string to UpdateStatus, unknown value is an error
+func ParseUpdateStatus(value string) (UpdateStatus, error) {
+	v := UpdateStatus(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "UpdateStatus", Value: value}
+	}
+	return v, nil
+}
+
+// UrlTypeValues - All known values of UrlType
+var UrlTypeValues = []UrlType{UrlHome, UrlWork, UrlOther, UrlCustom}
+
+// Valid - Return true if the value is known
+func (v UrlType) Valid() bool {
+	switch v {
+	case UrlHome, UrlWork, UrlOther, UrlCustom:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v UrlType) String() string {
+	return string(v)
+}
+
+// ParseUrlType - Convert the string to UrlType, unknown value is an error
+func ParseUrlType(value string) (UrlType, error) {
+	v := UrlType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "UrlType", Value: value}
+	}
+	return v, nil
+}
+
+// UserAuthTypeValues - All known values of UserAuthType
+var UserAuthTypeValues = []UserAuthType{UInternalAuth, UWindowsNTAuth, UPamAuth, UKerberosAuth, UAppleAuth, ULDAPAuth}
+
+// Valid - Return true if the value is known
+func (v UserAuthType) Valid() bool {
+	switch v {
+	case UInternalAuth, UWindowsNTAuth, UPamAuth, UKerberosAuth, UAppleAuth, ULDAPAuth:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v UserAuthType) String() string {
+	return string(v)
+}
+
+// ParseUserAuthType - Convert the string to UserAuthType, unknown value is an error
+func ParseUserAuthType(value string) (UserAuthType, error) {
+	v := UserAuthType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "UserAuthType", Value: value}
+	}
+	return v, nil
+}
+
+// UserDeleteFolderModeValues - All known values of UserDeleteFolderMode
+var UserDeleteFolderModeValues = []UserDeleteFolderMode{UDeleteUser, UDeleteFolder, UMoveFolder}
+
+// Valid - Return true if the value is known
+func (v UserDeleteFolderMode) Valid() bool {
+	switch v {
+	case UDeleteUser, UDeleteFolder, UMoveFolder:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v UserDeleteFolderMode) String() string {
+	return string(v)
+}
+
+// ParseUserDeleteFolderMode - Convert the string to UserDeleteFolderMode, unknown value is an error
+func ParseUserDeleteFolderMode(value string) (UserDeleteFolderMode, error) {
+	v := UserDeleteFolderMode(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "UserDeleteFolderMode", Value: value}
+	}
+	return v, nil
+}
+
+// UserForwardModeValues - All known values of UserForwardMode
+var UserForwardModeValues = []UserForwardMode{UForwardNone, UForwardYes, UForwardDeliver}
+
+// Valid - Return true if the value is known
+func (v UserForwardMode) Valid() bool {
+	switch v {
+	case UForwardNone, UForwardYes, UForwardDeliver:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v UserForwardMode) String() string {
+	return string(v)
+}
+
+// ParseUserForwardMode - Convert the string to UserForwardMode, unknown value is an error
+func ParseUserForwardMode(value string) (UserForwardMode, error) {
+	v := UserForwardMode(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "UserForwardMode", Value: value}
+	}
+	return v, nil
+}
+
+// UserLimitTypeValues - All known values of UserLimitType
+var UserLimitTypeValues = []UserLimitType{DomainLimit, LicenseLimit}
+
+// Valid - Return true if the value is known
+func (v UserLimitType) Valid() bool {
+	switch v {
+	case DomainLimit, LicenseLimit:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v UserLimitType) String() string {
+	return string(v)
+}
+
+// ParseUserLimitType - Convert the string to UserLimitType, unknown value is an error
+func ParseUserLimitType(value string) (UserLimitType, error) {
+	v := UserLimitType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "UserLimitType", Value: value}
+	}
+	return v, nil
+}
+
+// UserRoleTypeValues - All known values of UserRoleType
+var UserRoleTypeValues = []UserRoleType{UserRole, Auditor, AccountAdmin, FullAdmin, BuiltInAdmin, BuiltInDomainAdmin}
+
+// Valid - Return true if the value is known
+func (v UserRoleType) Valid() bool {
+	switch v {
+	case UserRole, Auditor, AccountAdmin, FullAdmin, BuiltInAdmin, BuiltInDomainAdmin:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v UserRoleType) String() string {
+	return string(v)
+}
+
+// ParseUserRoleType - Convert the string to UserRoleType, unknown value is an error
+func ParseUserRoleType(value string) (UserRoleType, error) {
+	v := UserRoleType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "UserRoleType", Value: value}
+	}
+	return v, nil
+}
+
+// ValidForValues - All known values of ValidFor
+var ValidForValues = []ValidFor{OneDomain, AllDomains}
+
+// Valid - Return true if the value is known
+func (v ValidFor) Valid() bool {
+	switch v {
+	case OneDomain, AllDomains:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v ValidFor) String() string {
+	return string(v)
+}
+
+// ParseValidFor - Convert the string to ValidFor, unknown value is an error
+func ParseValidFor(value string) (ValidFor, error) {
+	v := ValidFor(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "ValidFor", Value: value}
+	}
+	return v, nil
+}
+
+// ValidTypeValues - All known values of ValidType
+var ValidTypeValues = []ValidType{Valid, NotValidYet, ExpireSoon, Expired}
+
+// Valid - Return true if the value is known
+func (v ValidType) Valid() bool {
+	switch v {
+	case Valid, NotValidYet, ExpireSoon, Expired:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v ValidType) String() string {
+	return string(v)
+}
+
+// ParseValidType - Convert the string to ValidType, unknown value is an error
+func ParseValidType(value string) (ValidType, error) {
+	v := ValidType(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "ValidType", Value: value}
+	}
+	return v, nil
+}
+
+// WebComponentValues - All known values of WebComponent
+var WebComponentValues = []WebComponent{WebComponentWEBMAIL, WebComponentADMIN, WebComponentMINI}
+
+// Valid - Return true if the value is known
+func (v WebComponent) Valid() bool {
+	switch v {
+	case WebComponentWEBMAIL, WebComponentADMIN, WebComponentMINI:
+		return true
+	}
+	return false
+}
+
+// String - Return the value as sent by the server
+func (v WebComponent) String() string {
+	return string(v)
+}
+
+// ParseWebComponent - Convert the string to WebComponent, unknown value is an error
+func ParseWebComponent(value string) (WebComponent, error) {
+	v := WebComponent(value)
+	if !v.Valid() {
+		return v, &UnknownEnumError{Type: "WebComponent", Value: value}
+	}
+	return v, nil
+}
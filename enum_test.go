@@ -0,0 +1,51 @@
+package connect
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseEnum(t *testing.T) {
+	backupType, err := ParseBackupType("backupTypeFull")
+	if err != nil || backupType != BackupTypeFull || backupType.String() != "backupTypeFull" {
+		t.Errorf("unexpected backup type %q, %v", backupType, err)
+	}
+	if _, err = ParseBackupType("backupTypeIncremental"); err == nil || err.Error() != `unknown BackupType value "backupTypeIncremental"` {
+		t.Errorf("unexpected error %v", err)
+	}
+	if len(ClusterRoleValues) != 3 || !ClusterRoleValues[2].Valid() || ClusterRole("").Valid() {
+		t.Errorf("unexpected cluster roles %v", ClusterRoleValues)
+	}
+}
+
+func TestUnmarshalEnum(t *testing.T) {
+	status := map[string]interface{}{"lastBackupStatus": "backupStatusFailed", "percents": 5}
+	conn, _ := newFakeConnection(t, map[string]fakeHandler{
+		"Backup.getStatus": func(params json.RawMessage) interface{} {
+			return map[string]interface{}{"status": status}
+		},
+	})
+	backup, err := conn.BackupGetStatus()
+	if err != nil || backup.LastBackupStatus != BackupStatusFailed || len(conn.UnknownEnums()) != 0 {
+		t.Errorf("unexpected status %+v, %v", backup, err)
+	}
+	status["lastBackupStatus"] = ""
+	if backup, err = conn.BackupGetStatus(); err != nil || backup.LastBackupStatus != "" || len(conn.UnknownEnums()) != 0 {
+		t.Errorf("empty value not accepted: %v", err)
+	}
+	status["lastBackupStatus"] = "backupStatusPartial"
+	for i := 0; i < 2; i++ {
+		if backup, err = conn.BackupGetStatus(); err != nil || backup.LastBackupStatus.Valid() {
+			t.Errorf("unknown value not accepted: %v", err)
+		}
+	}
+	expected := UnknownEnumError{Type: "LastBackupStatus", Value: "backupStatusPartial"}
+	if unknown := conn.UnknownEnums(); len(unknown) != 1 || unknown[0] != expected {
+		t.Errorf("unexpected unknown values %+v", unknown)
+	}
+	conn.StrictEnums = true
+	_, err = conn.BackupGetStatus()
+	if unknown, ok := err.(*UnknownEnumError); !ok || *unknown != expected {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -1,5 +1,5 @@
 module github.com/igiant/connect
 
-go 1.16
+go 1.18
 
 require gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
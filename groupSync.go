@@ -0,0 +1,219 @@
+package connect
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultMaxRemovePercent - Default safety limit for removing members during synchronization
+const DefaultMaxRemovePercent = 20
+
+// GroupSyncOptions - Safety limits of group membership synchronization
+type GroupSyncOptions struct {
+	MaxRemovePercent int  // refuse to remove more than given percent of current members; 0 means DefaultMaxRemovePercent, 100 disables the limit
+	DryRun           bool // compute and report the delta without changing membership
+}
+
+// GroupRoster - Desired membership of a group, e.g. exported from an HR system
+type GroupRoster struct {
+	Group   string     `json:"group"`   // group ID, name or name@domain
+	Members StringList `json:"members"` // login names or email addresses of desired members
+}
+
+// GroupRosterList - List of group rosters
+type GroupRosterList []GroupRoster
+
+// GroupSyncReport - Result of synchronization of one group. Lists are sorted, so repeated runs produce comparable reports.
+type GroupSyncReport struct {
+	GroupId    KId        `json:"groupId"`           // global identification of group
+	GroupName  string     `json:"groupName"`         // group name with domain
+	Kept       StringList `json:"kept"`              // login names of members which stay in group
+	Added      StringList `json:"added"`             // login names of users added to group
+	Removed    StringList `json:"removed"`           // login names of users removed from group
+	Unresolved StringList `json:"unresolved"`        // desired identifiers which do not match any user
+	Applied    bool       `json:"applied"`           // delta was sent to the server
+	Errors     ErrorList  `json:"errors"`            // errors returned by the server for particular members
+	Failure    string     `json:"failure,omitempty"` // reason why the group was not synchronized
+}
+
+// GroupSyncReportList - List of group synchronization reports
+type GroupSyncReportList []GroupSyncReport
+
+// IsChanged returns true if the synchronization adds or removes any member
+func (r *GroupSyncReport) IsChanged() bool {
+	return len(r.Added) > 0 || len(r.Removed) > 0
+}
+
+// SyncGroupMembers - Converge membership of the group to the desired list of users.
+//	groupId - global group identifier
+//	desired - login names or email addresses of users who should be members of the group
+//	options - safety limits, nil means defaults
+// Return
+//	report - computed delta and result of applying it
+func (s *ServerConnection) SyncGroupMembers(groupId KId, desired StringList, options *GroupSyncOptions) (*GroupSyncReport, error) {
+	group, domain, err := s.findGroup(string(groupId))
+	if err != nil {
+		return nil, err
+	}
+	return s.syncGroup(*group, *domain, desired, options)
+}
+
+// SyncGroups - Converge membership of several groups in one run.
+// A failure of one group does not stop the others; it is recorded in report of that group.
+//	rosters - desired membership of groups
+//	options - safety limits applied to every group, nil means defaults
+// Return
+//	reports - one report per roster in the same order
+func (s *ServerConnection) SyncGroups(rosters GroupRosterList, options *GroupSyncOptions) (GroupSyncReportList, error) {
+	reports := make(GroupSyncReportList, 0, len(rosters))
+	failed := 0
+	for _, roster := range rosters {
+		report := &GroupSyncReport{GroupName: roster.Group}
+		group, domain, err := s.findGroup(roster.Group)
+		if err == nil {
+			report, err = s.syncGroup(*group, *domain, roster.Members, options)
+		}
+		if err != nil {
+			failed++
+			report.Failure = err.Error()
+		}
+		reports = append(reports, *report)
+	}
+	if failed > 0 {
+		return reports, fmt.Errorf("%d of %d groups were not synchronized", failed, len(rosters))
+	}
+	return reports, nil
+}
+
+func (s *ServerConnection) syncGroup(group Group, domain Domain, desired StringList, options *GroupSyncOptions) (*GroupSyncReport, error) {
+	if options == nil {
+		options = &GroupSyncOptions{}
+	}
+	limit := options.MaxRemovePercent
+	if limit == 0 {
+		limit = DefaultMaxRemovePercent
+	}
+	report := &GroupSyncReport{
+		GroupId:   group.Id,
+		GroupName: group.Name + "@" + domain.Name,
+	}
+	index, err := s.newUserIndex(domain)
+	if err != nil {
+		return report, err
+	}
+	wanted := make(map[KId]bool)
+	for _, identifier := range desired {
+		if strings.TrimSpace(identifier) == "" {
+			continue
+		}
+		user, ok, err := s.resolveUser(index, identifier)
+		if err != nil {
+			return report, err
+		}
+		if !ok {
+			report.Unresolved = append(report.Unresolved, identifier)
+			continue
+		}
+		wanted[user.Id] = true
+	}
+	current := make(map[KId]bool)
+	var toRemove KIdList
+	for _, user := range index.users {
+		if !isGroupMember(user, group.Id) {
+			continue
+		}
+		current[user.Id] = true
+		if wanted[user.Id] {
+			report.Kept = append(report.Kept, user.LoginName)
+			continue
+		}
+		toRemove = append(toRemove, user.Id)
+		report.Removed = append(report.Removed, user.LoginName)
+	}
+	var toAdd KIdList
+	for _, user := range index.users {
+		if wanted[user.Id] && !current[user.Id] {
+			toAdd = append(toAdd, user.Id)
+			report.Added = append(report.Added, user.LoginName)
+		}
+	}
+	sort.Strings(report.Kept)
+	sort.Strings(report.Added)
+	sort.Strings(report.Removed)
+	sort.Strings(report.Unresolved)
+	if len(toRemove)*100 > len(current)*limit {
+		return report, fmt.Errorf("group %s: refusing to remove %d of %d members (limit is %d%%)", report.GroupName, len(toRemove), len(current), limit)
+	}
+	if options.DryRun || !report.IsChanged() {
+		return report, nil
+	}
+	if len(toAdd) > 0 {
+		errors, err := s.GroupsAddMemberList(group.Id, toAdd)
+		if err != nil {
+			return report, err
+		}
+		report.Errors = append(report.Errors, errors...)
+	}
+	if len(toRemove) > 0 {
+		errors, err := s.GroupsRemoveMemberList(group.Id, toRemove)
+		if err != nil {
+			return report, err
+		}
+		report.Errors = append(report.Errors, errors...)
+	}
+	report.Applied = true
+	return report, nil
+}
+
+// findGroup looks the group up by ID, name or name@domain in all domains.
+// A name without domain has to be unique among domains.
+func (s *ServerConnection) findGroup(ref string) (*Group, *Domain, error) {
+	name, domainName := splitAddress(ref)
+	domains, _, err := s.DomainsGet(SearchQuery{})
+	if err != nil {
+		return nil, nil, err
+	}
+	var found *Group
+	var foundDomain *Domain
+	var foundIn StringList
+	for i := range domains {
+		domain := &domains[i]
+		if domainName != "" && strings.ToLower(domain.Name) != domainName {
+			continue
+		}
+		groups, _, err := s.GroupsGet(SearchQuery{}, domain.Id)
+		if err != nil {
+			return nil, nil, err
+		}
+		for j := range groups {
+			group := &groups[j]
+			if string(group.Id) == ref {
+				return group, domain, nil
+			}
+			if strings.ToLower(group.Name) == name {
+				if found == nil {
+					found, foundDomain = group, domain
+				}
+				foundIn = append(foundIn, domain.Name)
+			}
+		}
+	}
+	if len(foundIn) > 1 {
+		return nil, nil, fmt.Errorf("group %q is ambiguous, it exists in domains %s; use name@domain", ref, strings.Join(foundIn, ", "))
+	}
+	if found == nil {
+		return nil, nil, fmt.Errorf("group %q not found", ref)
+	}
+	return found, foundDomain, nil
+}
+
+// isGroupMember reports whether the user is a member of the group
+func isGroupMember(user User, groupId KId) bool {
+	for _, group := range user.UserGroups {
+		if group.Id == groupId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package connect
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func groupSyncHandlers(added, removed *KIdList) map[string]fakeHandler {
+	member := UserGroupList{{Id: "g1", Name: "sales"}}
+	users := UserList{
+		{Id: "u1", LoginName: "alice", UserGroups: member},
+		{Id: "u2", LoginName: "bob", UserGroups: member, EmailAddresses: UserEmailAddressList{"robert"}},
+		{Id: "u3", LoginName: "carol"},
+		{Id: "u4", LoginName: "dave", UserGroups: member},
+		{Id: "u5", LoginName: "erin", UserGroups: member},
+		{Id: "u6", LoginName: "frank", UserGroups: member},
+	}
+	return map[string]fakeHandler{
+		"Domains.get": result(map[string]interface{}{"list": DomainList{{Id: "d1", Name: "example.com", AliasList: StringList{"example.org"}}}}),
+		"Groups.get":  result(map[string]interface{}{"list": GroupList{{Id: "g1", DomainId: "d1", Name: "sales"}}}),
+		"Users.get":   result(map[string]interface{}{"list": users}),
+		"Server.findEntityByEmail": result(map[string]interface{}{"entities": EntityDuplicateList{
+			{Kind: EntityAlias, Name: "sales-head"},
+		}}),
+		"Groups.addMemberList": func(params json.RawMessage) interface{} {
+			p := struct {
+				UserList KIdList `json:"userList"`
+			}{}
+			_ = json.Unmarshal(params, &p)
+			*added = append(*added, p.UserList...)
+			return map[string]interface{}{"errors": ErrorList{}}
+		},
+		"Groups.removeMemberList": func(params json.RawMessage) interface{} {
+			p := struct {
+				UserIds KIdList `json:"userIds"`
+			}{}
+			_ = json.Unmarshal(params, &p)
+			*removed = append(*removed, p.UserIds...)
+			return map[string]interface{}{"errors": ErrorList{}}
+		},
+	}
+}
+
+func TestSyncGroupMembers(t *testing.T) {
+	var added, removed KIdList
+	conn, _ := newFakeConnection(t, groupSyncHandlers(&added, &removed))
+	desired := StringList{"Alice", "robert@example.org", "carol@example.com", "dave", "erin", "nobody@example.com"}
+	report, err := conn.SyncGroupMembers("g1", desired, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(report.Added, StringList{"carol"}) || !reflect.DeepEqual(report.Removed, StringList{"frank"}) {
+		t.Errorf("unexpected delta: %+v", report)
+	}
+	if !reflect.DeepEqual(report.Unresolved, StringList{"nobody@example.com"}) {
+		t.Errorf("unexpected unresolved: %v", report.Unresolved)
+	}
+	if !report.Applied || !reflect.DeepEqual(added, KIdList{"u3"}) || !reflect.DeepEqual(removed, KIdList{"u6"}) {
+		t.Errorf("delta not applied: added %v, removed %v", added, removed)
+	}
+}
+
+func TestSyncGroupMembersLimit(t *testing.T) {
+	var added, removed KIdList
+	conn, fake := newFakeConnection(t, groupSyncHandlers(&added, &removed))
+	report, err := conn.SyncGroupMembers("g1", StringList{"alice", "bob"}, nil)
+	if err == nil {
+		t.Fatal("expected removal limit error")
+	}
+	if len(report.Removed) != 3 || fake.called("Groups.removeMemberList") != 0 {
+		t.Errorf("limit not enforced: %+v", report)
+	}
+	reports, err := conn.SyncGroups(GroupRosterList{{Group: "sales@example.com", Members: StringList{"alice", "bob"}}}, &GroupSyncOptions{MaxRemovePercent: 100, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 || reports[0].Applied || len(reports[0].Removed) != 3 {
+		t.Errorf("unexpected dry run report: %+v", reports)
+	}
+}
+
+func TestSyncGroupsAmbiguousName(t *testing.T) {
+	var added, removed KIdList
+	handlers := groupSyncHandlers(&added, &removed)
+	handlers["Domains.get"] = result(map[string]interface{}{"list": DomainList{{Id: "d1", Name: "example.com"}, {Id: "d2", Name: "example.net"}}})
+	handlers["Groups.get"] = func(params json.RawMessage) interface{} {
+		p := struct {
+			DomainId KId `json:"domainId"`
+		}{}
+		_ = json.Unmarshal(params, &p)
+		return map[string]interface{}{"list": GroupList{{Id: "g" + p.DomainId, DomainId: p.DomainId, Name: "sales"}}}
+	}
+	conn, _ := newFakeConnection(t, handlers)
+	if _, err := conn.SyncGroups(GroupRosterList{{Group: "sales", Members: StringList{"alice"}}}, &GroupSyncOptions{DryRun: true}); err == nil {
+		t.Error("expected error for group name existing in two domains")
+	}
+	group, domain, err := conn.findGroup("sales@example.net")
+	if err != nil || group.Id != "gd2" || domain.Id != "d2" {
+		t.Errorf("unexpected group %+v in %+v: %v", group, domain, err)
+	}
+}
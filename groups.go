@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 // Group - Group details
 type Group struct {
 	Id                   KId          `json:"id"`                   // global identification of group
@@ -43,7 +41,7 @@ func (s *ServerConnection) GroupsActivate(groupIdList KIdList) (ErrorList, error
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -66,7 +64,7 @@ func (s *ServerConnection) GroupsAddMemberList(groupId KId, userList KIdList) (E
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -89,7 +87,7 @@ func (s *ServerConnection) GroupsCreate(groups GroupList) (ErrorList, CreateResu
 			Result CreateResultList `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, errors.Result.Result, err
 }
 
@@ -112,7 +110,7 @@ func (s *ServerConnection) GroupsCreateLdap(groups GroupList) (ErrorList, Create
 			Result CreateResultList `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, errors.Result.Result, err
 }
 
@@ -137,7 +135,7 @@ func (s *ServerConnection) GroupsGet(query SearchQuery, domainId KId) (GroupList
 			TotalItems int       `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -157,7 +155,7 @@ func (s *ServerConnection) GroupsRemove(requests GroupRemovalRequestList) (Error
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -180,7 +178,7 @@ func (s *ServerConnection) GroupsRemoveMemberList(groupId KId, userIds KIdList)
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -203,6 +201,6 @@ func (s *ServerConnection) GroupsSet(groupIds KIdList, pattern Group) (ErrorList
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
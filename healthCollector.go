@@ -0,0 +1,366 @@
+package connect
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultHealthPollInterval - Pause between rounds of HealthCollector, shorter than the window of HistogramInterval20s
+const DefaultHealthPollInterval = time.Minute
+
+// HealthIntervals - All histogram intervals in order from the finest
+var HealthIntervals = []HistogramIntervalType{HistogramInterval20s, HistogramInterval5m, HistogramInterval30m, HistogramInterval2h}
+
+// HealthSample - One sample of system health with reconstructed time
+type HealthSample struct {
+	Time        time.Time `json:"time"`
+	Cpu         float64   `json:"cpu"`         // 0-100%
+	Memory      float64   `json:"memory"`      // 0-100%
+	MemoryTotal float64   `json:"memoryTotal"` // total memory when the samples were read
+	DiskTotal   float64   `json:"diskTotal"`   // bytes on data partition when the samples were read
+	DiskFree    float64   `json:"diskFree"`    // free bytes on data partition when the samples were read
+}
+
+// HealthSampleList - Samples ordered by time
+type HealthSampleList []HealthSample
+
+// NewHealthSamples - Assign times to samples returned by SystemHealthGetInc.
+// The last sample was taken at sampleTime, each previous one an interval earlier.
+//	data - histograms of the interval
+//	sampleTime - time of the last sample returned by SystemHealthGetInc
+//	interval - interval of histograms
+// Return
+//	samples - samples ordered by time
+func NewHealthSamples(data *SystemHealthData, sampleTime DateTimeStamp, interval HistogramIntervalType) HealthSampleList {
+	count := len(data.Cpu)
+	if len(data.Memory) > count {
+		count = len(data.Memory)
+	}
+	last := time.Unix(int64(sampleTime), 0).UTC()
+	samples := make(HealthSampleList, count)
+	for i := range samples {
+		samples[i] = HealthSample{Time: last.Add(-time.Duration(count-1-i) * interval.Duration()),
+			MemoryTotal: data.MemoryTotal, DiskTotal: data.DiskTotal, DiskFree: data.DiskFree}
+		// histograms are aligned to the last sample if one of them is shorter
+		if j := i - (count - len(data.Cpu)); j >= 0 {
+			samples[i].Cpu = data.Cpu[j]
+		}
+		if j := i - (count - len(data.Memory)); j >= 0 {
+			samples[i].Memory = data.Memory[j]
+		}
+	}
+	return samples
+}
+
+// HealthStore - Append-only store of health samples, one file with JSON lines per histogram interval
+type HealthStore struct {
+	dir  string
+	mu   sync.Mutex
+	last map[HistogramIntervalType]time.Time
+}
+
+// OpenHealthStore - Open the store in the directory, it is created if it does not exist.
+// An incomplete line left by interrupted write is removed.
+func OpenHealthStore(dir string) (*HealthStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	h := &HealthStore{dir: dir, last: map[HistogramIntervalType]time.Time{}}
+	for _, interval := range HealthIntervals {
+		if err := h.repair(interval); err != nil {
+			return nil, err
+		}
+		samples, err := h.read(interval)
+		if err != nil {
+			return nil, err
+		}
+		if len(samples) > 0 {
+			h.last[interval] = samples[len(samples)-1].Time
+		}
+	}
+	return h, nil
+}
+
+func (h *HealthStore) fileName(interval HistogramIntervalType) string {
+	return filepath.Join(h.dir, string(interval)+".jsonl")
+}
+
+// repair truncates the file after the last complete line
+func (h *HealthStore) repair(interval HistogramIntervalType) error {
+	data, err := ioutil.ReadFile(h.fileName(interval))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil || len(data) == 0 || data[len(data)-1] == '\n' {
+		return err
+	}
+	return os.Truncate(h.fileName(interval), int64(bytes.LastIndexByte(data, '\n')+1))
+}
+
+func (h *HealthStore) read(interval HistogramIntervalType) (HealthSampleList, error) {
+	file, err := os.Open(h.fileName(interval))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var samples HealthSampleList
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		var sample HealthSample
+		if err = json.Unmarshal(scanner.Bytes(), &sample); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", h.fileName(interval), line, err)
+		}
+		samples = append(samples, sample)
+	}
+	return samples, scanner.Err()
+}
+
+// Last - Time of the newest stored sample of the interval, zero if there is none
+func (h *HealthStore) Last(interval HistogramIntervalType) time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.last[interval]
+}
+
+// Append - Store samples newer than the newest stored one.
+//	interval - interval of samples
+//	samples - samples ordered by time
+// Return
+//	appended - count of stored samples
+func (h *HealthStore) Append(interval HistogramIntervalType, samples HealthSampleList) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	last := h.last[interval]
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	appended := 0
+	for _, sample := range samples {
+		if !sample.Time.After(last) {
+			continue
+		}
+		if err := encoder.Encode(sample); err != nil {
+			return 0, err
+		}
+		last = sample.Time
+		appended++
+	}
+	if appended == 0 {
+		return 0, nil
+	}
+	file, err := os.OpenFile(h.fileName(interval), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return 0, err
+	}
+	if _, err = file.Write(buffer.Bytes()); err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+	h.last[interval] = last
+	return appended, nil
+}
+
+// Query - Read stored samples of the interval in the time range.
+//	interval - interval of samples
+//	from, to - inclusive time range; zero values are unlimited
+// Return
+//	samples - samples ordered by time
+func (h *HealthStore) Query(interval HistogramIntervalType, from, to time.Time) (HealthSampleList, error) {
+	h.mu.Lock()
+	samples, err := h.read(interval)
+	h.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	selected := HealthSampleList{}
+	for _, sample := range samples {
+		if (from.IsZero() || !sample.Time.Before(from)) && (to.IsZero() || !sample.Time.After(to)) {
+			selected = append(selected, sample)
+		}
+	}
+	return selected, nil
+}
+
+// HealthCollectorOptions - Options of NewHealthCollector
+type HealthCollectorOptions struct {
+	Intervals    []HistogramIntervalType // collected intervals; HealthIntervals if empty
+	PollInterval time.Duration           // pause between rounds of Run; DefaultHealthPollInterval if 0
+	OnError      func(err error)         // called when a round fails, Run continues
+}
+
+// HealthCollector - Keeps history of system health longer than the server does
+type HealthCollector struct {
+	s       *ServerConnection
+	store   *HealthStore
+	options HealthCollectorOptions
+	cursors map[HistogramIntervalType]DateTimeStamp
+}
+
+// NewHealthCollector - Create collector saving samples to the store.
+// Collection resumes after the newest stored sample, so samples the server still keeps fill the gap after restart.
+//	store - store of samples
+//	options - nil means defaults
+func (s *ServerConnection) NewHealthCollector(store *HealthStore, options *HealthCollectorOptions) *HealthCollector {
+	c := &HealthCollector{s: s, store: store, cursors: map[HistogramIntervalType]DateTimeStamp{}}
+	if options != nil {
+		c.options = *options
+	}
+	if len(c.options.Intervals) == 0 {
+		c.options.Intervals = HealthIntervals
+	}
+	if c.options.PollInterval <= 0 {
+		c.options.PollInterval = DefaultHealthPollInterval
+	}
+	for _, interval := range c.options.Intervals {
+		if last := store.Last(interval); !last.IsZero() {
+			c.cursors[interval] = DateTimeStamp(last.Unix())
+		}
+	}
+	return c
+}
+
+// Collect - Read new samples of all intervals and store them.
+// Return
+//	appended - count of stored samples
+func (c *HealthCollector) Collect() (int, error) {
+	appended := 0
+	for _, interval := range c.options.Intervals {
+		data, sampleTime, err := c.s.SystemHealthGetInc(interval, c.cursors[interval])
+		if err != nil {
+			return appended, fmt.Errorf("%s: %v", interval, err)
+		}
+		count, err := c.store.Append(interval, NewHealthSamples(data, *sampleTime, interval))
+		appended += count
+		if err != nil {
+			return appended, fmt.Errorf("%s: %v", interval, err)
+		}
+		if *sampleTime > c.cursors[interval] {
+			c.cursors[interval] = *sampleTime
+		}
+	}
+	return appended, nil
+}
+
+// Run - Collect samples periodically until ctx is done
+func (c *HealthCollector) Run(ctx context.Context) {
+	for {
+		if _, err := c.Collect(); err != nil && c.options.OnError != nil {
+			c.options.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(c.options.PollInterval):
+		}
+	}
+}
+
+// HealthMetric - Selects value of the sample
+type HealthMetric func(sample HealthSample) float64
+
+// HealthCpu - Select CPU usage
+func HealthCpu(sample HealthSample) float64 { return sample.Cpu }
+
+// HealthMemory - Select memory usage
+func HealthMemory(sample HealthSample) float64 { return sample.Memory }
+
+// Percentile - Return p-th percentile (0-100) of the metric with linear interpolation; NaN for empty list
+func (l HealthSampleList) Percentile(p float64, metric HealthMetric) float64 {
+	if len(l) == 0 {
+		return math.NaN()
+	}
+	values := make([]float64, len(l))
+	for i, sample := range l {
+		values[i] = metric(sample)
+	}
+	sort.Float64s(values)
+	rank := math.Max(0, math.Min(100, p)) / 100 * float64(len(values)-1)
+	lower := int(math.Floor(rank))
+	if lower == len(values)-1 {
+		return values[lower]
+	}
+	return values[lower] + (rank-float64(lower))*(values[lower+1]-values[lower])
+}
+
+// HealthAggregate - Samples of one period
+type HealthAggregate struct {
+	Time       time.Time `json:"time"`  // start of the period
+	Count      int       `json:"count"` // count of samples, 0 marks a gap
+	CpuMean    float64   `json:"cpuMean"`
+	CpuMax     float64   `json:"cpuMax"`
+	MemoryMean float64   `json:"memoryMean"`
+	MemoryMax  float64   `json:"memoryMax"`
+}
+
+// HealthAggregateList - Periods ordered by time
+type HealthAggregateList []HealthAggregate
+
+// Downsample - Aggregate samples to periods of the step.
+// Periods start at times of samples truncated by time.Time.Truncate, i.e. multiples of the step since the zero time;
+// a step dividing a day gives periods aligned to midnight UTC.
+// Periods without samples between the first and the last sample are included with Count 0.
+func (l HealthSampleList) Downsample(step time.Duration) HealthAggregateList {
+	if len(l) == 0 || step <= 0 {
+		return HealthAggregateList{}
+	}
+	first := l[0].Time.Truncate(step)
+	periods := int(l[len(l)-1].Time.Truncate(step).Sub(first)/step) + 1
+	aggregates := make(HealthAggregateList, periods)
+	for i := range aggregates {
+		aggregates[i].Time = first.Add(time.Duration(i) * step)
+	}
+	for _, sample := range l {
+		a := &aggregates[int(sample.Time.Truncate(step).Sub(first)/step)]
+		a.Count++
+		a.CpuMean += sample.Cpu
+		a.MemoryMean += sample.Memory
+		a.CpuMax = math.Max(a.CpuMax, sample.Cpu)
+		a.MemoryMax = math.Max(a.MemoryMax, sample.Memory)
+	}
+	for i := range aggregates {
+		if aggregates[i].Count > 0 {
+			aggregates[i].CpuMean /= float64(aggregates[i].Count)
+			aggregates[i].MemoryMean /= float64(aggregates[i].Count)
+		}
+	}
+	return aggregates
+}
+
+// HealthGap - Period without samples
+type HealthGap struct {
+	From time.Time `json:"from"` // time of the last sample before the gap
+	To   time.Time `json:"to"`   // time of the first sample after the gap
+}
+
+// HealthGapList - Gaps ordered by time
+type HealthGapList []HealthGap
+
+// Gaps - Return periods where samples are further apart than the step of the interval
+func (l HealthSampleList) Gaps(interval HistogramIntervalType) HealthGapList {
+	gaps := HealthGapList{}
+	for i := 1; i < len(l); i++ {
+		if l[i].Time.Sub(l[i-1].Time) > interval.Duration() {
+			gaps = append(gaps, HealthGap{From: l[i-1].Time, To: l[i].Time})
+		}
+	}
+	return gaps
+}
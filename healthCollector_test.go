@@ -0,0 +1,112 @@
+package connect
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewHealthSamples(t *testing.T) {
+	data := &SystemHealthData{Cpu: PercentHistogram{10, 20, 30}, Memory: PercentHistogram{50, 60}, DiskFree: 5}
+	samples := NewHealthSamples(data, 1611835200, HistogramInterval5m)
+	if len(samples) != 3 {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+	if !samples[0].Time.Equal(time.Unix(1611835200-600, 0)) || !samples[2].Time.Equal(time.Unix(1611835200, 0)) {
+		t.Errorf("unexpected times %v, %v", samples[0].Time, samples[2].Time)
+	}
+	if samples[0].Memory != 0 || samples[1].Memory != 50 || samples[2].Cpu != 30 || samples[2].DiskFree != 5 {
+		t.Errorf("unexpected samples %+v", samples)
+	}
+}
+
+func TestHealthCollector(t *testing.T) {
+	const start = 1611835200
+	var cursors []DateTimeStamp
+	conn, _ := newFakeConnection(t, map[string]fakeHandler{
+		"SystemHealth.getInc": func(params json.RawMessage) interface{} {
+			var p struct {
+				StartSampleTime DateTimeStamp `json:"startSampleTime"`
+			}
+			_ = json.Unmarshal(params, &p)
+			cursors = append(cursors, p.StartSampleTime)
+			if p.StartSampleTime == 0 {
+				return map[string]interface{}{"data": SystemHealthData{Cpu: PercentHistogram{1, 2, 3}, Memory: PercentHistogram{4, 5, 6}}, "sampleTime": start}
+			}
+			// the server returns the sample at the cursor once more
+			return map[string]interface{}{"data": SystemHealthData{Cpu: PercentHistogram{3, 7, 8}, Memory: PercentHistogram{6, 7, 8}}, "sampleTime": start + 40}
+		},
+	})
+	dir, err := ioutil.TempDir("", "health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store, err := OpenHealthStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := &HealthCollectorOptions{Intervals: []HistogramIntervalType{HistogramInterval20s}}
+	collector := conn.NewHealthCollector(store, options)
+	if appended, err := collector.Collect(); err != nil || appended != 3 {
+		t.Fatalf("first round stored %d samples, %v", appended, err)
+	}
+	// restart with a partial line left by interrupted write
+	file, err := os.OpenFile(filepath.Join(dir, string(HistogramInterval20s)+".jsonl"), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = file.WriteString(`{"time":`)
+	file.Close()
+	store, err = OpenHealthStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !store.Last(HistogramInterval20s).Equal(time.Unix(start, 0)) {
+		t.Fatalf("unexpected last sample %v", store.Last(HistogramInterval20s))
+	}
+	if appended, err := conn.NewHealthCollector(store, options).Collect(); err != nil || appended != 2 {
+		t.Fatalf("second round stored %d samples, %v", appended, err)
+	}
+	if len(cursors) != 2 || cursors[0] != 0 || cursors[1] != start {
+		t.Errorf("unexpected cursors %v", cursors)
+	}
+	samples, err := store.Query(HistogramInterval20s, time.Unix(start-20, 0), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 4 || samples[1].Cpu != 3 || samples[3].Cpu != 8 || !samples[3].Time.Equal(time.Unix(start+40, 0)) {
+		t.Errorf("unexpected samples %+v", samples)
+	}
+}
+
+func TestHealthSampleQueries(t *testing.T) {
+	base := time.Date(2021, 1, 28, 12, 0, 0, 0, time.UTC)
+	var samples HealthSampleList
+	for i, cpu := range []float64{10, 20, 30, 40} {
+		samples = append(samples, HealthSample{Time: base.Add(time.Duration(i) * 5 * time.Minute), Cpu: cpu, Memory: 50})
+	}
+	samples = append(samples, HealthSample{Time: base.Add(time.Hour), Cpu: 100, Memory: 70})
+	if p := samples.Percentile(50, HealthCpu); p != 30 {
+		t.Errorf("unexpected median %v", p)
+	}
+	if p := samples.Percentile(90, HealthCpu); p != 76 {
+		t.Errorf("unexpected 90th percentile %v", p)
+	}
+	if p := (HealthSampleList{}).Percentile(50, HealthMemory); !math.IsNaN(p) {
+		t.Errorf("unexpected percentile of no samples %v", p)
+	}
+	aggregates := samples.Downsample(30 * time.Minute)
+	if len(aggregates) != 3 || aggregates[0].Count != 4 || aggregates[0].CpuMean != 25 || aggregates[0].CpuMax != 40 ||
+		aggregates[1].Count != 0 || aggregates[2].MemoryMax != 70 {
+		t.Errorf("unexpected aggregates %+v", aggregates)
+	}
+	gaps := samples.Gaps(HistogramInterval5m)
+	if len(gaps) != 1 || !gaps[0].From.Equal(base.Add(15*time.Minute)) || !gaps[0].To.Equal(base.Add(time.Hour)) {
+		t.Errorf("unexpected gaps %+v", gaps)
+	}
+}
@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 // InitGetHostname - Returns FQDN (fully qualified domain name) of the server (e.g. mail.companyname.com).
 // Return
 //	hostname - name of the server
@@ -15,7 +13,7 @@ func (s *ServerConnection) InitGetHostname() (string, error) {
 			Hostname string `json:"hostname"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &hostname)
+	err = s.unmarshal(data, &hostname)
 	return hostname.Result.Hostname, err
 }
 
@@ -66,7 +64,7 @@ func (s *ServerConnection) InitGetDistributableDomains(authentication ClusterAut
 			DomainNames StringList `json:"domainNames"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &domainNames)
+	err = s.unmarshal(data, &domainNames)
 	return domainNames.Result.DomainNames, err
 }
 
@@ -89,7 +87,7 @@ func (s *ServerConnection) InitCreateDistributableDomain(domainName string, auth
 			Result ClusterError `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &result)
+	err = s.unmarshal(data, &result)
 	return &result.Result.Result, err
 }
 
@@ -130,7 +128,7 @@ func (s *ServerConnection) InitGetMessageStorePath() (string, int, error) {
 			FreeSpace int    `json:"freeSpace"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &path)
+	err = s.unmarshal(data, &path)
 	return path.Result.Path, path.Result.FreeSpace, err
 }
 
@@ -161,7 +159,7 @@ func (s *ServerConnection) InitGetDirs(fullPath string) (DirectoryList, error) {
 			DirList DirectoryList `json:"dirList"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &dirList)
+	err = s.unmarshal(data, &dirList)
 	return dirList.Result.DirList, err
 }
 
@@ -184,7 +182,7 @@ func (s *ServerConnection) InitCheckMessageStorePath(path string) (*DirectoryAcc
 			FreeSpace int                   `json:"freeSpace"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &result)
+	err = s.unmarshal(data, &result)
 	return &result.Result.Result, result.Result.FreeSpace, err
 }
 
@@ -217,7 +215,7 @@ func (s *ServerConnection) InitGetNamedConstantList() (NamedConstantList, error)
 			Constants NamedConstantList `json:"constants"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &constants)
+	err = s.unmarshal(data, &constants)
 	return constants.Result.Constants, err
 }
 
@@ -234,7 +232,7 @@ func (s *ServerConnection) InitGetBrowserLanguages() (StringList, error) {
 			CalculatedLanguage StringList `json:"calculatedLanguage"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &calculatedLanguage)
+	err = s.unmarshal(data, &calculatedLanguage)
 	return calculatedLanguage.Result.CalculatedLanguage, err
 }
 
@@ -251,7 +249,7 @@ func (s *ServerConnection) InitGetProductInfo() (*ProductInfo, error) {
 			Info ProductInfo `json:"info"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &info)
+	err = s.unmarshal(data, &info)
 	return &info.Result.Info, err
 }
 
@@ -268,6 +266,6 @@ func (s *ServerConnection) InitGetEula() (string, error) {
 			Content string `json:"content"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &content)
+	err = s.unmarshal(data, &content)
 	return content.Result.Content, err
 }
@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 type XmppSettings struct {
 	SendOutsideEnabled          bool `json:"sendOutsideEnabled"`          // Sending messages outside the company is enabled
 	SendOutsideEnabledIsRunning bool `json:"sendOutsideEnabledIsRunning"` // [READ-ONLY] Sending messages outside the company is really running and is functional
@@ -27,7 +25,7 @@ func (s *ServerConnection) InstantMessagingGet() (*XmppSettings, error) {
 			Settings XmppSettings `json:"settings"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &settings)
+	err = s.unmarshal(data, &settings)
 	return &settings.Result.Settings, err
 }
 
@@ -55,6 +53,6 @@ func (s *ServerConnection) InstantMessagingCheckXMPPConfiguration(domainId KId)
 			Configuration XMPPConfiguration `json:"configuration"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &configuration)
+	err = s.unmarshal(data, &configuration)
 	return &configuration.Result.Configuration, err
 }
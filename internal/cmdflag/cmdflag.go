@@ -0,0 +1,35 @@
+// Package cmdflag holds command line handling shared by the commands of the module.
+package cmdflag
+
+import (
+	"flag"
+	"os"
+)
+
+type secret struct {
+	value *string
+	env   string
+}
+
+var secrets []secret
+
+// Secret - Define string flag which is read from the environment variable by Parse if it is not given.
+// The variable is not the default of the flag, so usage never prints the secret.
+//	name - name of the flag
+//	env - name of the environment variable
+//	usage - description of the flag
+func Secret(name, env, usage string) *string {
+	value := flag.String(name, "", usage+"; $"+env+" if not given")
+	secrets = append(secrets, secret{value: value, env: env})
+	return value
+}
+
+// Parse - Parse the command line and fill secrets which were not given from their environment variables
+func Parse() {
+	flag.Parse()
+	for _, s := range secrets {
+		if *s.value == "" {
+			*s.value = os.Getenv(s.env)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Command enumgen writes Valid, String, and Parse<Type> for string enums of the package.
+// Every string type with typed constants is an enum, except the types given by -skip.
+// Usage:
+//	go run ./internal/enumgen [-skip LogType,...] [-output enumValues.go]
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// enum - String type with its constants in order of declaration
+type enum struct {
+	name      string
+	constants []string
+	values    map[string]bool
+}
+
+func main() {
+	skip := flag.String("skip", "", "comma separated types which are not server enums, e.g. open sets of names or types of the library")
+	output := flag.String("output", "enumValues.go", "generated file")
+	flag.Parse()
+	skipped := map[string]bool{}
+	for _, name := range strings.Split(*skip, ",") {
+		skipped[strings.TrimSpace(name)] = true
+	}
+	fset := token.NewFileSet()
+	packages, err := parser.ParseDir(fset, ".", func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go") && info.Name() != *output
+	}, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(packages) != 1 {
+		log.Fatalf("expected one package, found %d", len(packages))
+	}
+	var packageName string
+	var files []*ast.File
+	for name, p := range packages {
+		packageName = name
+		fileNames := make([]string, 0, len(p.Files))
+		for fileName := range p.Files {
+			fileNames = append(fileNames, fileName)
+		}
+		sort.Strings(fileNames)
+		for _, fileName := range fileNames {
+			files = append(files, p.Files[fileName])
+		}
+	}
+	enums := collect(files, skipped)
+	source, err := format.Source(generate(packageName, enums))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = ioutil.WriteFile(*output, source, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// collect finds string types and their constants
+func collect(files []*ast.File, skipped map[string]bool) []*enum {
+	stringTypes := map[string]bool{}
+	for _, file := range files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			if spec, ok := node.(*ast.TypeSpec); ok && !spec.Assign.IsValid() {
+				if ident, ok := spec.Type.(*ast.Ident); ok && ident.Name == "string" && !skipped[spec.Name.Name] {
+					stringTypes[spec.Name.Name] = true
+				}
+			}
+			return true
+		})
+	}
+	byName := map[string]*enum{}
+	var enums []*enum
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				value := spec.(*ast.ValueSpec)
+				ident, ok := value.Type.(*ast.Ident)
+				if !ok || !stringTypes[ident.Name] {
+					continue
+				}
+				e := byName[ident.Name]
+				if e == nil {
+					e = &enum{name: ident.Name, values: map[string]bool{}}
+					byName[ident.Name] = e
+					enums = append(enums, e)
+				}
+				for i, name := range value.Names {
+					literal, ok := value.Values[i].(*ast.BasicLit)
+					if !ok || literal.Kind != token.STRING {
+						continue
+					}
+					text, err := strconv.Unquote(literal.Value)
+					if err != nil || e.values[text] {
+						continue
+					}
+					e.values[text] = true
+					e.constants = append(e.constants, name.Name)
+				}
+			}
+		}
+	}
+	sort.Slice(enums, func(i, j int) bool { return enums[i].name < enums[j].name })
+	return enums
+}
+
+func generate(packageName string, enums []*enum) []byte {
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "// Code generated by enumgen; DO NOT EDIT.\n\npackage %s\n", packageName)
+	for _, e := range enums {
+		fmt.Fprintf(b, "\n// %sValues - All known values of %s\n", e.name, e.name)
+		fmt.Fprintf(b, "var %sValues = []%s{%s}\n", e.name, e.name, strings.Join(e.constants, ", "))
+		fmt.Fprintf(b, "\n// Valid - Return true if the value is known\n")
+		fmt.Fprintf(b, "func (v %s) Valid() bool {\n\tswitch v {\n\tcase %s:\n\t\treturn true\n\t}\n\treturn false\n}\n", e.name, strings.Join(e.constants, ", "))
+		fmt.Fprintf(b, "\n// String - Return the value as sent by the server\n")
+		fmt.Fprintf(b, "func (v %s) String() string {\n\treturn string(v)\n}\n", e.name)
+		fmt.Fprintf(b, "\n// Parse%s - Convert the string to %s, unknown value is an error\n", e.name, e.name)
+		fmt.Fprintf(b, "func Parse%s(value string) (%s, error) {\n\tv := %s(value)\n\tif !v.Valid() {\n\t\treturn v, &UnknownEnumError{Type: %q, Value: value}\n\t}\n\treturn v, nil\n}\n",
+			e.name, e.name, e.name, e.name)
+	}
+	return b.Bytes()
+}
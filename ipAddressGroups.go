@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 // @defgroup SUBGROUP3 Definitions
 
 // IpAddressGroupType -
@@ -64,7 +62,7 @@ func (s *ServerConnection) IpAddressGroupsCreate(groups IpAddressEntryList) (Err
 			Result CreateResultList `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, errors.Result.Result, err
 }
 
@@ -90,7 +88,7 @@ func (s *ServerConnection) IpAddressGroupsGet(query SearchQuery) (IpAddressEntry
 			TotalItems int                `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -107,7 +105,7 @@ func (s *ServerConnection) IpAddressGroupsGetGroupList() (IpAddressGroupList, er
 			Groups IpAddressGroupList `json:"groups"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &groups)
+	err = s.unmarshal(data, &groups)
 	return groups.Result.Groups, err
 }
 
@@ -128,7 +126,7 @@ func (s *ServerConnection) IpAddressGroupsRemove(groupIds KIdList) (ErrorList, e
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -151,7 +149,7 @@ func (s *ServerConnection) IpAddressGroupsSet(groupIds KIdList, details IpAddres
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -173,7 +171,7 @@ func (s *ServerConnection) IpAddressGroupsValidateRemove(groupIds KIdList) (Erro
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -196,6 +194,6 @@ func (s *ServerConnection) IpAddressGroupsValidateSet(groupIds KIdList, details
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
@@ -0,0 +1,385 @@
+package connect
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Log names used by the parsers
+const (
+	MailLog     LogType = "mail"
+	SecurityLog LogType = "security"
+	SpamLog     LogType = "spam"
+	ConfigLog   LogType = "config"
+)
+
+// logTimeLayout - Format of timestamp at the beginning of each log row, e.g. [28/Jan/2021 10:15:33]
+const logTimeLayout = "02/Jan/2006 15:04:05"
+
+// LogMessage - Row of log without further structure (warning, error, debug, audit, operations)
+type LogMessage struct {
+	Time time.Time `json:"time"`
+	Text string    `json:"text"`
+}
+
+// MailLogEvent - Message accepted or delivered by the server, from rows like
+//	Recv: Queue-ID: 6012a0b5-00000001, Service: SMTP, From: <a@example.com>, To: <b@example.com>, Size: 1234, ...
+//	Sent: Queue-ID: 6012a0b5-00000001, Recipient: <b@example.com>, Result: delivered, Status: 2.0.0, ...
+type MailLogEvent struct {
+	Time       time.Time         `json:"time"`
+	Action     string            `json:"action"`               // Recv, Sent, ...
+	QueueId    string            `json:"queueId"`              // queue ID of the message
+	Service    string            `json:"service,omitempty"`    // service which received the message
+	From       string            `json:"from,omitempty"`       // sender address without angle brackets
+	To         string            `json:"to,omitempty"`         // recipient address without angle brackets
+	Size       int64             `json:"size,omitempty"`       // message size in bytes
+	Result     string            `json:"result,omitempty"`     // delivery result, e.g. delivered, relayed or failed
+	Status     string            `json:"status,omitempty"`     // SMTP status of the delivery
+	RemoteHost string            `json:"remoteHost,omitempty"` // host the message was delivered to
+	SenderHost string            `json:"senderHost,omitempty"` // host the message was received from
+	Subject    string            `json:"subject,omitempty"`
+	MessageId  string            `json:"messageId,omitempty"`
+	Fields     map[string]string `json:"fields"` // all fields of the row
+}
+
+// SecurityProtection - Protection which produced the security event
+type SecurityProtection string
+
+const (
+	SecurityProtectionNone          SecurityProtection = ""
+	SecurityProtectionAntibombing   SecurityProtection = "Antibombing"
+	SecurityProtectionAntihammering SecurityProtection = "Antihammering"
+)
+
+// SecurityReason - Reason of the security event
+type SecurityReason string
+
+const (
+	SecurityInvalidPassword SecurityReason = "InvalidPassword" // user exists, but the password is wrong
+	SecurityUnknownUser     SecurityReason = "UnknownUser"     // user does not exist
+	SecurityLoginFailed     SecurityReason = "LoginFailed"     // login failed for other or unknown reason
+	SecurityBlocked         SecurityReason = "Blocked"         // connection or address blocked by a protection
+	SecurityOther           SecurityReason = "Other"           // any other event
+)
+
+// SecurityLogEvent - Failed login or blocked client, from rows like
+//	IMAP: Invalid password for user john@example.com. Attempt from IP address 10.0.0.1.
+//	Failed SMTP login from 10.0.0.1 with SASL method LOGIN.
+type SecurityLogEvent struct {
+	Time       time.Time          `json:"time"`
+	Service    string             `json:"service,omitempty"`    // service the client connected to
+	Ip         string             `json:"ip,omitempty"`         // client address
+	User       string             `json:"user,omitempty"`       // login name used by the client
+	Reason     SecurityReason     `json:"reason"`               // classification of the event
+	Protection SecurityProtection `json:"protection,omitempty"` // antibombing or antihammering if it produced the event
+	Message    string             `json:"message"`              // text of the row without timestamp
+}
+
+// SpamLogEvent - Verdict of spam filter, from rows like
+//	Message marked as spam with score: 8.50, To: b@example.com, Message size: 2035, From: s@example.org, ...
+type SpamLogEvent struct {
+	Time       time.Time         `json:"time"`
+	Verdict    string            `json:"verdict"` // e.g. marked as spam, rejected as spam
+	IsSpam     bool              `json:"isSpam"`
+	Score      float64           `json:"score"`
+	QueueId    string            `json:"queueId,omitempty"`
+	From       string            `json:"from,omitempty"`
+	To         string            `json:"to,omitempty"`
+	Size       int64             `json:"size,omitempty"`
+	Subject    string            `json:"subject,omitempty"`
+	RemoteHost string            `json:"remoteHost,omitempty"`
+	MessageId  string            `json:"messageId,omitempty"`
+	Fields     map[string]string `json:"fields"` // all fields of the row
+}
+
+// ConfigValueChange - Changed configuration value
+type ConfigValueChange struct {
+	Name string `json:"name"`
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new"`
+}
+
+// ConfigValueChangeList - List of changed values
+type ConfigValueChangeList []ConfigValueChange
+
+// ConfigChangeEvent - Change made by administrator, from rows like
+//	admin - 10.0.0.1: update User set FullName='John Smith' where Name='john'
+//	admin - 10.0.0.1: Domain example.com: changed 'Quota' from '0' to '100'
+//	admin - session opened for host 10.0.0.1
+type ConfigChangeEvent struct {
+	Time      time.Time             `json:"time"`
+	Admin     string                `json:"admin"`               // login name of administrator
+	Host      string                `json:"host,omitempty"`      // address the administrator connected from
+	Operation string                `json:"operation"`           // insert, update, delete, change, session or other
+	Object    string                `json:"object,omitempty"`    // changed configuration object
+	Condition string                `json:"condition,omitempty"` // which records of object were changed
+	Changes   ConfigValueChangeList `json:"changes,omitempty"`   // changed values
+	Message   string                `json:"message"`             // text of the row without timestamp
+}
+
+// ParseLogRow - Parse the row of the log to the typed event.
+//	logName - name of the log the row comes from
+//	row - log row
+//	location - time zone of the server; nil means UTC
+// Return
+//	event - *MailLogEvent, *SecurityLogEvent, *SpamLogEvent, *ConfigChangeEvent or *LogMessage for other logs
+func ParseLogRow(logName LogType, row LogRow, location *time.Location) (interface{}, error) {
+	switch logName {
+	case MailLog:
+		return ParseMailLogRow(row, location)
+	case SecurityLog:
+		return ParseSecurityLogRow(row, location)
+	case SpamLog:
+		return ParseSpamLogRow(row, location)
+	case ConfigLog:
+		return ParseConfigLogRow(row, location)
+	}
+	return ParseLogMessage(row, location)
+}
+
+// ParseLogMessage - Split the row of any log to timestamp and text
+func ParseLogMessage(row LogRow, location *time.Location) (*LogMessage, error) {
+	timestamp, text, err := splitLogRow(row.Content, location)
+	if err != nil {
+		return nil, err
+	}
+	return &LogMessage{Time: timestamp, Text: text}, nil
+}
+
+// ParseMailLogRow - Parse the row of mail log
+func ParseMailLogRow(row LogRow, location *time.Location) (*MailLogEvent, error) {
+	timestamp, text, err := splitLogRow(row.Content, location)
+	if err != nil {
+		return nil, err
+	}
+	colon := strings.Index(text, ": ")
+	if colon <= 0 || strings.ContainsAny(text[:colon], " ,") {
+		return nil, fmt.Errorf("mail log row without action: %q", text)
+	}
+	event := &MailLogEvent{Time: timestamp, Action: text[:colon], Fields: parseLogFields(text[colon+2:])}
+	event.QueueId = event.Fields["Queue-ID"]
+	if event.QueueId == "" {
+		return nil, fmt.Errorf("mail log row without queue id: %q", text)
+	}
+	event.Service = event.Fields["Service"]
+	event.From = trimAngleBrackets(event.Fields["From"])
+	event.To = trimAngleBrackets(event.Fields["To"])
+	if event.To == "" {
+		event.To = trimAngleBrackets(event.Fields["Recipient"])
+	}
+	if size, ok := event.Fields["Size"]; ok {
+		if event.Size, err = strconv.ParseInt(size, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid size in mail log row: %q", text)
+		}
+	}
+	event.Result = event.Fields["Result"]
+	event.Status = event.Fields["Status"]
+	event.RemoteHost = event.Fields["Remote-Host"]
+	event.SenderHost = event.Fields["Sender-Host"]
+	event.Subject = event.Fields["Subject"]
+	event.MessageId = trimAngleBrackets(event.Fields["Msg-Id"])
+	return event, nil
+}
+
+var (
+	securityInvalidPassword = regexp.MustCompile(`^([\w/-]+): Invalid password for user (\S+?)\. Attempt from IP address (\S+?)\.?$`)
+	securityUnknownUser     = regexp.MustCompile(`^([\w/-]+): User (\S+?) doesn't exist\. Attempt from IP address (\S+?)\.?$`)
+	securityFailedLogin     = regexp.MustCompile(`^Failed ([\w/-]+) login from (\S+?)[.,]?(?: |$)`)
+	securityUser            = regexp.MustCompile(`(?i)\buser (\S+?)[.,]?(?: |$)`)
+	securityAddress         = regexp.MustCompile(`[0-9A-Fa-f:.]*[0-9A-Fa-f][.:][0-9A-Fa-f:.]+`)
+)
+
+// ParseSecurityLogRow - Parse the row of security log. Unknown rows are returned with reason SecurityOther,
+// the first IP address and user found in the text.
+func ParseSecurityLogRow(row LogRow, location *time.Location) (*SecurityLogEvent, error) {
+	timestamp, text, err := splitLogRow(row.Content, location)
+	if err != nil {
+		return nil, err
+	}
+	event := &SecurityLogEvent{Time: timestamp, Message: text, Reason: SecurityOther}
+	lower := strings.ToLower(text)
+	switch {
+	case strings.Contains(lower, "hammering"):
+		event.Protection = SecurityProtectionAntihammering
+	case strings.Contains(lower, "bombing") || strings.Contains(lower, "connection rate") || strings.Contains(lower, "too many connections"):
+		event.Protection = SecurityProtectionAntibombing
+	}
+	if match := securityInvalidPassword.FindStringSubmatch(text); match != nil {
+		event.Service, event.User, event.Ip, event.Reason = match[1], match[2], match[3], SecurityInvalidPassword
+	} else if match = securityUnknownUser.FindStringSubmatch(text); match != nil {
+		event.Service, event.User, event.Ip, event.Reason = match[1], match[2], match[3], SecurityUnknownUser
+	} else if match = securityFailedLogin.FindStringSubmatch(text); match != nil {
+		event.Service, event.Ip, event.Reason = match[1], match[2], SecurityLoginFailed
+		if user := securityUser.FindStringSubmatch(text); user != nil {
+			event.User = user[1]
+		}
+	} else {
+		if user := securityUser.FindStringSubmatch(text); user != nil {
+			event.User = user[1]
+		}
+		for _, candidate := range securityAddress.FindAllString(text, -1) {
+			candidate = strings.TrimRight(candidate, ".:")
+			if net.ParseIP(candidate) != nil {
+				event.Ip = candidate
+				break
+			}
+		}
+		if event.Protection != SecurityProtectionNone || strings.Contains(lower, "blocked") || strings.Contains(lower, "rejected") {
+			event.Reason = SecurityBlocked
+		}
+	}
+	return event, nil
+}
+
+var spamVerdict = regexp.MustCompile(`^Message (.+?) with score:? ?(-?[0-9]+(?:\.[0-9]+)?)(?:, (.*))?$`)
+
+// ParseSpamLogRow - Parse the row of spam log
+func ParseSpamLogRow(row LogRow, location *time.Location) (*SpamLogEvent, error) {
+	timestamp, text, err := splitLogRow(row.Content, location)
+	if err != nil {
+		return nil, err
+	}
+	match := spamVerdict.FindStringSubmatch(text)
+	if match == nil {
+		return nil, fmt.Errorf("spam log row without verdict: %q", text)
+	}
+	event := &SpamLogEvent{Time: timestamp, Verdict: match[1], Fields: parseLogFields(match[3])}
+	event.IsSpam = !strings.Contains(match[1], "not spam")
+	if event.Score, err = strconv.ParseFloat(match[2], 64); err != nil {
+		return nil, fmt.Errorf("invalid score in spam log row: %q", text)
+	}
+	if size, ok := event.Fields["Message size"]; ok {
+		if event.Size, err = strconv.ParseInt(size, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid size in spam log row: %q", text)
+		}
+	}
+	event.QueueId = event.Fields["Queue-ID"]
+	event.From = trimAngleBrackets(event.Fields["From"])
+	event.To = trimAngleBrackets(event.Fields["To"])
+	event.Subject = event.Fields["Subject"]
+	event.RemoteHost = event.Fields["Remote-Host"]
+	event.MessageId = trimAngleBrackets(event.Fields["Msg-Id"])
+	return event, nil
+}
+
+var (
+	configAdmin   = regexp.MustCompile(`^(\S+) - (?:([^\s:]+|\[[^\]]+\]): )?(.*)$`)
+	configSession = regexp.MustCompile(`^session (opened|closed) for host (\S+)$`)
+	configInsert  = regexp.MustCompile(`^insert (?:into )?(\S+) set (.*)$`)
+	configUpdate  = regexp.MustCompile(`^update (\S+) set (.*?)(?: where (.*))?$`)
+	configDelete  = regexp.MustCompile(`^delete from (\S+)(?: where (.*))?$`)
+	configChanged = regexp.MustCompile(`^(?:(.*?): )?(?:changed |set )?'([^']*)' from '(.*)' to '(.*)'$`)
+)
+
+// ParseConfigLogRow - Parse the row of config log. Rows with SQL-like statements (insert, update, delete)
+// and rows with "'name' from 'old' to 'new'" are split to changed values.
+func ParseConfigLogRow(row LogRow, location *time.Location) (*ConfigChangeEvent, error) {
+	timestamp, text, err := splitLogRow(row.Content, location)
+	if err != nil {
+		return nil, err
+	}
+	match := configAdmin.FindStringSubmatch(text)
+	if match == nil {
+		return nil, fmt.Errorf("config log row without administrator: %q", text)
+	}
+	event := &ConfigChangeEvent{Time: timestamp, Admin: match[1], Host: strings.Trim(match[2], "[]"), Operation: "other", Message: text}
+	statement := match[3]
+	if m := configSession.FindStringSubmatch(statement); m != nil {
+		event.Operation, event.Object, event.Host = "session", m[1], m[2]
+	} else if m = configInsert.FindStringSubmatch(statement); m != nil {
+		event.Operation, event.Object = "insert", m[1]
+		event.Changes = parseConfigAssignments(m[2])
+	} else if m = configUpdate.FindStringSubmatch(statement); m != nil {
+		event.Operation, event.Object, event.Condition = "update", m[1], m[3]
+		event.Changes = parseConfigAssignments(m[2])
+	} else if m = configDelete.FindStringSubmatch(statement); m != nil {
+		event.Operation, event.Object, event.Condition = "delete", m[1], m[2]
+	} else if m = configChanged.FindStringSubmatch(statement); m != nil {
+		event.Operation, event.Object = "change", m[1]
+		event.Changes = ConfigValueChangeList{{Name: m[2], Old: m[3], New: m[4]}}
+	}
+	return event, nil
+}
+
+// parseConfigAssignments splits name='value', name2=value2 pairs; quote is escaped by doubling it
+func parseConfigAssignments(text string) ConfigValueChangeList {
+	var changes ConfigValueChangeList
+	for text != "" {
+		equal := strings.IndexByte(text, '=')
+		if equal < 0 {
+			break
+		}
+		change := ConfigValueChange{Name: strings.TrimSpace(text[:equal])}
+		text = strings.TrimLeft(text[equal+1:], " ")
+		if strings.HasPrefix(text, "'") {
+			var value strings.Builder
+			i := 1
+			for ; i < len(text); i++ {
+				if text[i] == '\'' {
+					if i+1 < len(text) && text[i+1] == '\'' {
+						value.WriteByte('\'')
+						i++
+						continue
+					}
+					break
+				}
+				value.WriteByte(text[i])
+			}
+			change.New = value.String()
+			if i < len(text) {
+				i++
+			}
+			text = text[i:]
+		} else {
+			end := strings.IndexByte(text, ',')
+			if end < 0 {
+				end = len(text)
+			}
+			change.New = strings.TrimSpace(text[:end])
+			text = text[end:]
+		}
+		changes = append(changes, change)
+		text = strings.TrimLeft(text, ", ")
+	}
+	return changes
+}
+
+var logFieldName = regexp.MustCompile(`(?:^|, )([A-Z][A-Za-z-]*(?: [a-z]+)?): `)
+
+// parseLogFields splits "Name: value, Other-Name: value" to map, values may contain commas
+func parseLogFields(text string) map[string]string {
+	fields := map[string]string{}
+	matches := logFieldName.FindAllStringSubmatchIndex(text, -1)
+	for i, match := range matches {
+		end := len(text)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		fields[text[match[2]:match[3]]] = strings.TrimSpace(text[match[1]:end])
+	}
+	return fields
+}
+
+// splitLogRow separates timestamp in brackets from the text of the row
+func splitLogRow(content string, location *time.Location) (time.Time, string, error) {
+	if location == nil {
+		location = time.UTC
+	}
+	end := strings.IndexByte(content, ']')
+	if !strings.HasPrefix(content, "[") || end < 0 {
+		return time.Time{}, "", fmt.Errorf("log row without timestamp: %q", content)
+	}
+	timestamp, err := time.ParseInLocation(logTimeLayout, content[1:end], location)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("invalid timestamp of log row: %q", content)
+	}
+	return timestamp, strings.TrimSpace(content[end+1:]), nil
+}
+
+func trimAngleBrackets(address string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(address), "<"), ">")
+}
@@ -0,0 +1,133 @@
+package connect
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+// readFixture returns lines of the file in testdata/logs
+func readFixture(t testing.TB, name string) []string {
+	t.Helper()
+	file, err := os.Open("testdata/logs/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err = scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return lines
+}
+
+func TestParseLogRowFixtures(t *testing.T) {
+	for _, logName := range []LogType{MailLog, SecurityLog, SpamLog, ConfigLog} {
+		rows := readFixture(t, string(logName)+".log")
+		expected := readFixture(t, string(logName)+".json")
+		if len(rows) != len(expected) {
+			t.Fatalf("%s: %d rows, %d expected events", logName, len(rows), len(expected))
+		}
+		for i, row := range rows {
+			event, err := ParseLogRow(logName, LogRow{Content: row}, nil)
+			if err != nil {
+				t.Errorf("%s: %v", logName, err)
+				continue
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != expected[i] {
+				t.Errorf("%s row %d:\n got %s\nwant %s", logName, i+1, data, expected[i])
+			}
+		}
+	}
+}
+
+func TestParseLogMessage(t *testing.T) {
+	message, err := ParseLogMessage(LogRow{Content: "[01/Feb/2021 08:00:00] Low disk space"}, nil)
+	if err != nil || message.Text != "Low disk space" || message.Time.Day() != 1 {
+		t.Errorf("unexpected message %+v, %v", message, err)
+	}
+	if _, err = ParseLogMessage(LogRow{Content: "no timestamp"}, nil); err == nil {
+		t.Error("row without timestamp must fail")
+	}
+}
+
+// addFixtureSeeds adds rows of the fixture as the fuzz corpus
+func addFixtureSeeds(f *testing.F, logName LogType) {
+	for _, row := range readFixture(f, string(logName)+".log") {
+		f.Add(row)
+	}
+}
+
+func FuzzParseMailLogRow(f *testing.F) {
+	addFixtureSeeds(f, MailLog)
+	f.Fuzz(func(t *testing.T, content string) {
+		event, err := ParseMailLogRow(LogRow{Content: content}, nil)
+		if err != nil {
+			return
+		}
+		if event.QueueId == "" || !strings.Contains(content, event.QueueId) {
+			t.Errorf("queue id %q not found in %q", event.QueueId, content)
+		}
+		if _, err = json.Marshal(event); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func FuzzParseSecurityLogRow(f *testing.F) {
+	addFixtureSeeds(f, SecurityLog)
+	f.Fuzz(func(t *testing.T, content string) {
+		event, err := ParseSecurityLogRow(LogRow{Content: content}, nil)
+		if err != nil {
+			return
+		}
+		if !strings.Contains(content, event.Ip) || !strings.Contains(content, event.User) {
+			t.Errorf("ip %q or user %q not found in %q", event.Ip, event.User, content)
+		}
+		if _, err = json.Marshal(event); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func FuzzParseSpamLogRow(f *testing.F) {
+	addFixtureSeeds(f, SpamLog)
+	f.Fuzz(func(t *testing.T, content string) {
+		event, err := ParseSpamLogRow(LogRow{Content: content}, nil)
+		if err != nil {
+			return
+		}
+		if !strings.Contains(content, event.Verdict) {
+			t.Errorf("verdict %q not found in %q", event.Verdict, content)
+		}
+		if _, err = json.Marshal(event); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func FuzzParseConfigLogRow(f *testing.F) {
+	addFixtureSeeds(f, ConfigLog)
+	f.Fuzz(func(t *testing.T, content string) {
+		event, err := ParseConfigLogRow(LogRow{Content: content}, nil)
+		if err != nil {
+			return
+		}
+		if event.Admin == "" || !strings.Contains(content, event.Admin) {
+			t.Errorf("admin %q not found in %q", event.Admin, content)
+		}
+		if _, err = json.Marshal(event); err != nil {
+			t.Error(err)
+		}
+	})
+}
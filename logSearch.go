@@ -0,0 +1,200 @@
+package connect
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// DefaultSearchPollInterval - Pause between LogsGetSearchProgress calls while the server is searching
+const DefaultSearchPollInterval = 200 * time.Millisecond
+
+// LogLineRange - Lines of the log to search, both ends are included
+type LogLineRange struct {
+	From int `json:"from"` // first line to search
+	To   int `json:"to"`   // last line to search; -1 means end of log
+}
+
+// WholeLog - Range covering all lines of the log
+var WholeLog = LogLineRange{From: 0, To: logEnd}
+
+// LogMatch - Row found by the search
+type LogMatch struct {
+	LogName LogType `json:"logName"` // name of the log
+	Line    int     `json:"line"`    // number of the row in the log
+	LogRow
+}
+
+// LogSearchOptions - Options of SearchLog
+type LogSearchOptions struct {
+	Regexp       *regexp.Regexp // rows found by the server are sent only if they match the expression
+	PollInterval time.Duration  // pause between polls; DefaultSearchPollInterval if 0
+}
+
+// SearchLog - Search the log on the server and stream matching rows as they are found.
+// Search on the server is cancelled when ctx is done.
+//	ctx - context stopping the search
+//	logName - unique name of the log
+//	pattern - searched string
+//	lineRange - lines to search, WholeLog for all of them
+//	options - client-side filter and polling; nil means defaults
+// Return
+//	matches - found rows ordered by line, closed when the search ends
+//	errs - error which ended the search, closed together with matches
+func (s *ServerConnection) SearchLog(ctx context.Context, logName LogType, pattern string, lineRange LogLineRange, options *LogSearchOptions) (<-chan LogMatch, <-chan error) {
+	return s.SearchLogs(ctx, []LogType{logName}, pattern, lineRange, options)
+}
+
+// SearchLogs - Search several logs in one call. Logs are searched one after another because the connection
+// does not support concurrent calls, errs receives one error for each log which failed.
+func (s *ServerConnection) SearchLogs(ctx context.Context, logNames []LogType, pattern string, lineRange LogLineRange, options *LogSearchOptions) (<-chan LogMatch, <-chan error) {
+	search := logSearch{s: s, pattern: pattern, lineRange: lineRange}
+	if options != nil {
+		search.options = *options
+	}
+	if search.options.PollInterval <= 0 {
+		search.options.PollInterval = DefaultSearchPollInterval
+	}
+	matches := make(chan LogMatch)
+	errs := make(chan error, len(logNames))
+	go func() {
+		defer close(errs)
+		defer close(matches)
+		for _, logName := range logNames {
+			if err := search.run(ctx, logName, matches); err != nil {
+				errs <- fmt.Errorf("search in %s log: %v", logName, err)
+			}
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}()
+	return matches, errs
+}
+
+// logSearch holds parameters shared by searches of all logs
+type logSearch struct {
+	s         *ServerConnection
+	pattern   string
+	lineRange LogLineRange
+	options   LogSearchOptions
+}
+
+// run finds matches one by one, every server search starts behind the previous match
+func (l logSearch) run(ctx context.Context, logName LogType, matches chan<- LogMatch) error {
+	line := l.lineRange.From
+	for l.lineRange.To == logEnd || line <= l.lineRange.To {
+		match, found, err := l.next(ctx, logName, line)
+		if err != nil || !found {
+			return err
+		}
+		if match.Line < line {
+			return nil
+		}
+		line = match.Line + 1
+		if l.options.Regexp != nil && !l.options.Regexp.MatchString(match.Content) {
+			continue
+		}
+		select {
+		case matches <- match:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// next starts the server search from the line and waits for its result
+func (l logSearch) next(ctx context.Context, logName LogType, line int) (LogMatch, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return LogMatch{}, false, err
+	}
+	searchId, err := l.s.LogsSearch(logName, l.pattern, line, l.lineRange.To, true)
+	if err != nil {
+		return LogMatch{}, false, err
+	}
+	for {
+		viewport, firstLine, _, status, _, err := l.s.LogsGetSearchProgress(1, *searchId)
+		if err != nil {
+			_ = l.s.LogsCancelSearch(*searchId)
+			return LogMatch{}, false, err
+		}
+		switch *status {
+		case ResultFound:
+			if len(viewport) == 0 {
+				return LogMatch{}, false, fmt.Errorf("line %d was found but not returned", firstLine)
+			}
+			return LogMatch{LogName: logName, Line: firstLine, LogRow: viewport[0]}, true, nil
+		case ResultNotFound:
+			return LogMatch{}, false, nil
+		case Cancelled:
+			return LogMatch{}, false, fmt.Errorf("search was cancelled on the server")
+		}
+		select {
+		case <-ctx.Done():
+			_ = l.s.LogsCancelSearch(*searchId)
+			return LogMatch{}, false, ctx.Err()
+		case <-time.After(l.options.PollInterval):
+		}
+	}
+}
+
+// LogLineRangeForTime - Find lines of the log written within the time window by binary search over times of rows.
+// Rows are expected in order of time; rows without time, e.g. continuation lines, never shrink the range.
+//	logName - log to search
+//	from - beginning of the window; zero means the first line
+//	to - end of the window; zero means end of log
+//	location - time zone of the server; nil means UTC
+// Return
+//	lineRange - lines of the window for SearchLog; To is less than From if no row is in the window
+func (s *ServerConnection) LogLineRangeForTime(logName LogType, from, to time.Time, location *time.Location) (LogLineRange, error) {
+	lineRange := WholeLog
+	if from.IsZero() && to.IsZero() {
+		return lineRange, nil
+	}
+	_, total, err := s.LogsGet(logName, logEnd, 0)
+	if err != nil {
+		return lineRange, err
+	}
+	if !from.IsZero() {
+		lineRange.From, err = s.searchLogTime(logName, total, func(t time.Time) bool { return !t.Before(from) }, true, location)
+		if err != nil {
+			return lineRange, err
+		}
+	}
+	if !to.IsZero() {
+		after, err := s.searchLogTime(logName, total, func(t time.Time) bool { return t.After(to) }, false, location)
+		if err != nil {
+			return lineRange, err
+		}
+		if after < total {
+			lineRange.To = after - 1
+		}
+	}
+	return lineRange, nil
+}
+
+// searchLogTime returns the first line whose time is reached, unknown is used for rows without time
+func (s *ServerConnection) searchLogTime(logName LogType, total int, reached func(time.Time) bool, unknown bool, location *time.Location) (int, error) {
+	low, high := 0, total
+	for low < high {
+		middle := int(uint(low+high) >> 1)
+		rows, _, err := s.LogsGet(logName, middle, 1)
+		if err != nil {
+			return 0, err
+		}
+		ok := unknown
+		if len(rows) > 0 {
+			if message, err := ParseLogMessage(rows[0], location); err == nil {
+				ok = reached(message.Time)
+			}
+		}
+		if ok {
+			high = middle
+		} else {
+			low = middle + 1
+		}
+	}
+	return low, nil
+}
@@ -0,0 +1,125 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSearch implements Logs.get, Logs.search and Logs.getSearchProgress over fixed logs
+type fakeSearch struct {
+	mu       sync.Mutex
+	logs     map[LogType]LogRowList
+	searches map[string]fakeSearchState
+	endless  bool
+}
+
+type fakeSearchState struct {
+	logName LogType
+	what    string
+	from    int
+	to      int
+	polls   int
+}
+
+func (f *fakeSearch) handlers() map[string]fakeHandler {
+	return map[string]fakeHandler{
+		"Logs.search": func(params json.RawMessage) interface{} {
+			request := struct {
+				LogName  LogType `json:"logName"`
+				What     string  `json:"what"`
+				FromLine int     `json:"fromLine"`
+				ToLine   int     `json:"toLine"`
+			}{}
+			_ = json.Unmarshal(params, &request)
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			id := fmt.Sprintf("s%d", len(f.searches)+1)
+			f.searches[id] = fakeSearchState{logName: request.LogName, what: request.What, from: request.FromLine, to: request.ToLine}
+			return map[string]interface{}{"searchId": id}
+		},
+		"Logs.getSearchProgress": func(params json.RawMessage) interface{} {
+			request := struct {
+				SearchId string `json:"searchId"`
+			}{}
+			_ = json.Unmarshal(params, &request)
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			search := f.searches[request.SearchId]
+			search.polls++
+			f.searches[request.SearchId] = search
+			rows := f.logs[search.logName]
+			if f.endless || search.polls == 1 {
+				return map[string]interface{}{"viewport": LogRowList{}, "status": Searching, "totalItems": len(rows)}
+			}
+			for i := search.from; i < len(rows) && (search.to == logEnd || i <= search.to); i++ {
+				if strings.Contains(rows[i].Content, search.what) {
+					return map[string]interface{}{"viewport": LogRowList{rows[i]}, "firstLine": i, "status": ResultFound, "totalItems": len(rows)}
+				}
+			}
+			return map[string]interface{}{"viewport": LogRowList{}, "status": ResultNotFound, "totalItems": len(rows)}
+		},
+		"Logs.cancelSearch": result(nil),
+		"Logs.get": func(params json.RawMessage) interface{} {
+			request := struct {
+				LogName    LogType `json:"logName"`
+				FromLine   int     `json:"fromLine"`
+				CountLines int     `json:"countLines"`
+			}{}
+			_ = json.Unmarshal(params, &request)
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			rows := f.logs[request.LogName]
+			if request.FromLine == logEnd {
+				request.FromLine = len(rows) - request.CountLines
+			}
+			viewport := LogRowList{}
+			for i := request.FromLine; i >= 0 && i < len(rows) && i < request.FromLine+request.CountLines; i++ {
+				viewport = append(viewport, rows[i])
+			}
+			return map[string]interface{}{"viewport": viewport, "totalItems": len(rows)}
+		},
+	}
+}
+
+func TestSearchLogs(t *testing.T) {
+	search := &fakeSearch{searches: map[string]fakeSearchState{}, logs: map[LogType]LogRowList{
+		MailLog:     {{Content: "Recv: alice"}, {Content: "Sent: alice"}, {Content: "Recv: bob"}, {Content: "Recv: alice again"}},
+		SecurityLog: {{Content: "login alice from 10.0.0.1"}},
+	}}
+	conn, _ := newFakeConnection(t, search.handlers())
+	options := &LogSearchOptions{Regexp: regexp.MustCompile(`^Recv|login`), PollInterval: time.Millisecond}
+	matches, errs := conn.SearchLogs(context.Background(), []LogType{MailLog, SecurityLog}, "alice", WholeLog, options)
+	found := map[LogType][]int{}
+	for match := range matches {
+		found[match.LogName] = append(found[match.LogName], match.Line)
+	}
+	for err := range errs {
+		t.Error(err)
+	}
+	if fmt.Sprint(found[MailLog]) != "[0 3]" || fmt.Sprint(found[SecurityLog]) != "[0]" {
+		t.Errorf("unexpected matches: %v", found)
+	}
+}
+
+func TestSearchLogCancel(t *testing.T) {
+	search := &fakeSearch{searches: map[string]fakeSearchState{}, endless: true, logs: map[LogType]LogRowList{}}
+	conn, fake := newFakeConnection(t, search.handlers())
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	matches, errs := conn.SearchLog(ctx, MailLog, "x", WholeLog, &LogSearchOptions{PollInterval: time.Millisecond})
+	for range matches {
+		t.Error("no match expected")
+	}
+	if err := <-errs; err == nil {
+		t.Error("search should end with error of context")
+	}
+	if fake.called("Logs.cancelSearch") != 1 {
+		t.Error("search was not cancelled on the server")
+	}
+}
@@ -0,0 +1,214 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultShipRetryInterval - Pause after failed delivery to the sink
+const DefaultShipRetryInterval = 5 * time.Second
+
+// LogRecord - Log row prepared for shipping
+type LogRecord struct {
+	Host      string         `json:"host"`                // server the row comes from
+	LogName   LogType        `json:"logName"`             // name of the log
+	Line      int            `json:"line"`                // number of the row in the log
+	Time      time.Time      `json:"time"`                // timestamp of the row, time of shipping if the row has none
+	Text      string         `json:"text"`                // row without the timestamp
+	Content   string         `json:"content"`             // the whole row
+	Highlight HighlightColor `json:"highlight,omitempty"` // highlight color of the row
+	Event     interface{}    `json:"event,omitempty"`     // parsed row, see ParseLogRow
+}
+
+// LogRecordList - List of records
+type LogRecordList []LogRecord
+
+// LogSink - Destination of shipped records. WriteRecords must return nil only if all records were stored,
+// records of failed call are written again later.
+type LogSink interface {
+	WriteRecords(records LogRecordList) error
+	Close() error
+}
+
+// LogShipperOptions - Options of LogShipper
+type LogShipperOptions struct {
+	CursorFile    string          // file with positions of shipped rows; empty means rows are not tracked between runs
+	StartLines    int             // rows shipped from the end of a log which has no saved position; 0 means new rows only
+	PollInterval  time.Duration   // pause between polls; DefaultTailPollInterval if 0
+	PageSize      int             // rows read at once; DefaultTailPageSize if 0
+	RetryInterval time.Duration   // pause after failed delivery; DefaultShipRetryInterval if 0
+	Location      *time.Location  // time zone of the server used for timestamps of rows; nil means UTC
+	Parse         bool            // attach parsed rows to records
+	OnError       func(err error) // called when reading of log or delivery fails, shipping continues
+}
+
+// LogShipper - Forwards rows of logs to a sink with at-least-once delivery.
+// Position of each log is saved to the cursor file after its rows were accepted by the sink.
+type LogShipper struct {
+	s        *ServerConnection
+	logNames []LogType
+	sink     LogSink
+	options  LogShipperOptions
+	host     string
+}
+
+// NewLogShipper - Create shipper of the logs.
+//	logNames - logs to ship
+//	sink - destination of rows
+//	options - cursor file, polling and formatting; nil means defaults without cursor file
+func (s *ServerConnection) NewLogShipper(logNames []LogType, sink LogSink, options *LogShipperOptions) *LogShipper {
+	l := &LogShipper{s: s, logNames: logNames, sink: sink}
+	if options != nil {
+		l.options = *options
+	}
+	if l.options.RetryInterval <= 0 {
+		l.options.RetryInterval = DefaultShipRetryInterval
+	}
+	if l.options.PollInterval <= 0 {
+		l.options.PollInterval = DefaultTailPollInterval
+	}
+	if u, err := url.Parse(s.Config.url); err == nil {
+		l.host = u.Hostname()
+	}
+	return l
+}
+
+// Run - Ship rows until ctx is done. Logs are polled one after another through the connection.
+// Return
+//	err - nil when ctx is done, error if the cursor file can not be read or written
+func (l *LogShipper) Run(ctx context.Context) error {
+	cursors, err := ReadLogCursors(l.options.CursorFile)
+	if err != nil {
+		return err
+	}
+	tails := make([]*logTail, 0, len(l.logNames))
+	for _, logName := range l.logNames {
+		options := &TailOptions{LastLines: l.options.StartLines, PollInterval: l.options.PollInterval, PageSize: l.options.PageSize}
+		if cursor, ok := cursors[logName]; ok {
+			options.Cursor = &cursor
+		}
+		tails = append(tails, l.s.newLogTail(logName, options))
+	}
+	pending := map[LogType][]LogEntry{}
+	for {
+		busy, failed := false, false
+		for _, tail := range tails {
+			entries := pending[tail.logName]
+			if len(entries) == 0 {
+				var more bool
+				entries, more, err = tail.read()
+				if err != nil {
+					l.error(ctx, err)
+					failed = true
+					continue
+				}
+				busy = busy || more
+				if len(entries) == 0 {
+					continue
+				}
+			}
+			if err = l.sink.WriteRecords(l.records(tail.logName, entries)); err != nil {
+				l.error(ctx, err)
+				pending[tail.logName] = entries
+				failed = true
+				continue
+			}
+			delete(pending, tail.logName)
+			cursors[tail.logName] = entries[len(entries)-1].Cursor
+			if err = WriteLogCursors(l.options.CursorFile, cursors); err != nil {
+				return err
+			}
+		}
+		wait := l.options.PollInterval
+		if failed {
+			wait = l.options.RetryInterval
+		} else if busy {
+			wait = 0
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (l *LogShipper) error(ctx context.Context, err error) {
+	if ctx.Err() == nil && l.options.OnError != nil {
+		l.options.OnError(err)
+	}
+}
+
+// records converts entries of the log, rows without valid timestamp get the current time
+func (l *LogShipper) records(logName LogType, entries []LogEntry) LogRecordList {
+	records := make(LogRecordList, 0, len(entries))
+	for _, entry := range entries {
+		record := LogRecord{Host: l.host, LogName: logName, Line: entry.Cursor.Line - 1, Content: entry.Content, Highlight: entry.Highlight}
+		timestamp, text, err := splitLogRow(entry.Content, l.options.Location)
+		if err != nil {
+			timestamp, text = time.Now(), entry.Content
+		}
+		record.Time, record.Text = timestamp, text
+		if l.options.Parse {
+			if event, err := ParseLogRow(logName, entry.LogRow, l.options.Location); err == nil {
+				record.Event = event
+			}
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// ReadLogCursors - Read positions of logs saved by WriteLogCursors; missing file means no positions
+func ReadLogCursors(fileName string) (map[LogType]LogCursor, error) {
+	cursors := map[LogType]LogCursor{}
+	if fileName == "" {
+		return cursors, nil
+	}
+	data, err := ioutil.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return cursors, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cursors, json.Unmarshal(data, &cursors)
+}
+
+// WriteLogCursors - Save positions of logs, the file is replaced atomically
+func WriteLogCursors(fileName string, cursors map[LogType]LogCursor) error {
+	if fileName == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(cursors, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(fileName, data)
+}
+
+// writeFileAtomic replaces the file by temporary file with the data, so readers never see it partially written
+func writeFileAtomic(fileName string, data []byte) error {
+	temporary, err := ioutil.TempFile(filepath.Dir(fileName), filepath.Base(fileName)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err = temporary.Write(data); err == nil {
+		err = temporary.Sync()
+	}
+	if closeErr := temporary.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(temporary.Name(), fileName)
+	}
+	if err != nil {
+		_ = os.Remove(temporary.Name())
+	}
+	return err
+}
@@ -0,0 +1,187 @@
+package connect
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memorySink stores records and fails the configured count of calls first, calling fail before each failure
+type memorySink struct {
+	mu      sync.Mutex
+	failing int
+	fail    func()
+	records LogRecordList
+}
+
+func (m *memorySink) WriteRecords(records LogRecordList) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.failing > 0 {
+		m.failing--
+		if m.fail != nil {
+			m.fail()
+		}
+		return errors.New("sink is down")
+	}
+	m.records = append(m.records, records...)
+	return nil
+}
+
+func (m *memorySink) Close() error {
+	return nil
+}
+
+func (m *memorySink) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.records)
+}
+
+func runShipper(t *testing.T, conn *ServerConnection, sink *memorySink, options *LogShipperOptions, expected int) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- conn.NewLogShipper([]LogType{MailLog}, sink, options).Run(ctx)
+	}()
+	deadline := time.Now().Add(2 * time.Second)
+	for sink.count() < expected && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if sink.count() != expected {
+		t.Fatalf("shipped %d of %d records", sink.count(), expected)
+	}
+}
+
+func TestLogShipperResume(t *testing.T) {
+	log := &fakeLog{lines: LogRowList{
+		{Content: "[28/Jan/2021 10:15:33] Recv: Queue-ID: q1, From: <a@example.com>, To: <b@example.com>, Size: 10"},
+		{Content: "[28/Jan/2021 10:15:34] Sent: Queue-ID: q1, Recipient: <b@example.com>, Result: delivered"},
+	}}
+	conn, _ := newFakeConnection(t, map[string]fakeHandler{"Logs.get": log.get})
+	cursorFile := filepath.Join(t.TempDir(), "cursor.json")
+	options := &LogShipperOptions{CursorFile: cursorFile, StartLines: 10, PollInterval: time.Millisecond, RetryInterval: time.Millisecond, Parse: true}
+	sink := &memorySink{failing: 2}
+	runShipper(t, conn, sink, options, 2)
+	record := sink.records[0]
+	if record.Line != 0 || record.Text[:5] != "Recv:" || record.Time.Minute() != 15 {
+		t.Errorf("unexpected record: %+v", record)
+	}
+	if event, ok := record.Event.(*MailLogEvent); !ok || event.QueueId != "q1" {
+		t.Errorf("unexpected event: %+v", record.Event)
+	}
+	cursors, err := ReadLogCursors(cursorFile)
+	if err != nil || cursors[MailLog].Line != 2 {
+		t.Fatalf("unexpected cursors %+v, %v", cursors, err)
+	}
+	log.set(append(log.lines, LogRow{Content: "[28/Jan/2021 10:16:00] Recv: Queue-ID: q2"}))
+	sink = &memorySink{}
+	runShipper(t, conn, sink, options, 1)
+	if sink.records[0].Line != 2 {
+		t.Errorf("shipper did not resume: %+v", sink.records)
+	}
+}
+
+func TestLogShipperCancel(t *testing.T) {
+	log := &fakeLog{lines: LogRowList{{Content: "[28/Jan/2021 10:15:33] Recv: Queue-ID: q1"}}}
+	conn, _ := newFakeConnection(t, map[string]fakeHandler{"Logs.get": log.get})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	failures := 0
+	options := &LogShipperOptions{StartLines: 10, PollInterval: time.Millisecond, RetryInterval: time.Millisecond, OnError: func(error) { failures++ }}
+	sink := &memorySink{failing: 1, fail: cancel}
+	if err := conn.NewLogShipper([]LogType{MailLog}, sink, options).Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if failures != 0 {
+		t.Errorf("OnError called %d times after cancel", failures)
+	}
+}
+
+func TestSyslogSink(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	received := make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			length, err := reader.ReadString(' ')
+			if err != nil {
+				return
+			}
+			size, _ := strconv.Atoi(strings.TrimSpace(length))
+			frame := make([]byte, size)
+			if _, err = io.ReadFull(reader, frame); err != nil {
+				return
+			}
+			received <- string(frame)
+		}
+	}()
+	sink, err := NewSyslogSink("tcp", listener.Addr().String(), &SyslogSinkOptions{Facility: FacilityLocal0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	record := LogRecord{Host: "mail.example.com", LogName: SecurityLog, Line: 7, Time: time.Date(2021, 1, 28, 10, 20, 1, 0, time.UTC), Text: "Failed login"}
+	if err = sink.WriteRecords(LogRecordList{record, record}); err != nil {
+		t.Fatal(err)
+	}
+	expected := `<133>1 2021-01-28T10:20:01.000000Z mail.example.com kerio-connect - security [kerio@32473 log="security" line="7"] Failed login`
+	for i := 0; i < 2; i++ {
+		select {
+		case message := <-received:
+			if message != expected {
+				t.Errorf("unexpected message %q", message)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("no message received")
+		}
+	}
+}
+
+func TestFileSinkRotation(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := NewFileSink(dir, 30, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	for i := 0; i < 4; i++ {
+		record := LogRecord{LogName: MailLog, Content: strings.Repeat(string(rune('a'+i)), 19)}
+		if err = sink.WriteRecords(LogRecordList{record}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, expected := range map[string]string{"mail.log": "dddd", "mail.log.1": "cccc", "mail.log.2": "bbbb"} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil || !strings.HasPrefix(string(data), expected) {
+			t.Errorf("%s: unexpected content %q, %v", name, data, err)
+		}
+	}
+	if _, err = os.Stat(filepath.Join(dir, "mail.log.3")); !os.IsNotExist(err) {
+		t.Error("the oldest file was not removed")
+	}
+}
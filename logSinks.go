@@ -0,0 +1,320 @@
+package connect
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// syslogEnterpriseId - Private enterprise number of structured data element, 32473 is reserved for documentation (RFC 5612)
+const syslogEnterpriseId = "32473"
+
+// SyslogSinkOptions - Options of SyslogSink
+type SyslogSinkOptions struct {
+	Facility  FacilityUnit  // FacilityMailSystem if empty
+	AppName   string        // APP-NAME of messages; kerio-connect if empty
+	TlsConfig *tls.Config   // configuration of "tls" network
+	Timeout   time.Duration // timeout of connecting and writing; 10 seconds if 0
+}
+
+// SyslogSink - Sends records as RFC 5424 messages. UDP sends one message per datagram,
+// TCP and TLS use octet counting framing of RFC 6587.
+type SyslogSink struct {
+	network string
+	address string
+	options SyslogSinkOptions
+	mutex   sync.Mutex
+	conn    net.Conn
+}
+
+// NewSyslogSink - Connect to syslog collector.
+//	network - udp, tcp or tls
+//	address - host:port of the collector
+//	options - facility, application name and TLS; nil means defaults
+func NewSyslogSink(network, address string, options *SyslogSinkOptions) (*SyslogSink, error) {
+	sink := &SyslogSink{network: network, address: address}
+	if options != nil {
+		sink.options = *options
+	}
+	if sink.options.Facility == "" {
+		sink.options.Facility = FacilityMailSystem
+	}
+	if sink.options.AppName == "" {
+		sink.options.AppName = "kerio-connect"
+	}
+	if sink.options.Timeout <= 0 {
+		sink.options.Timeout = 10 * time.Second
+	}
+	if syslogFacility(sink.options.Facility) < 0 {
+		return nil, fmt.Errorf("unknown syslog facility %q", sink.options.Facility)
+	}
+	switch network {
+	case "udp", "tcp", "tls":
+	default:
+		return nil, fmt.Errorf("unsupported syslog network %q", network)
+	}
+	if err := sink.dial(); err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+func (s *SyslogSink) dial() error {
+	dialer := &net.Dialer{Timeout: s.options.Timeout}
+	var err error
+	if s.network == "tls" {
+		s.conn, err = tls.DialWithDialer(dialer, "tcp", s.address, s.options.TlsConfig)
+	} else {
+		s.conn, err = dialer.Dial(s.network, s.address)
+	}
+	return err
+}
+
+// WriteRecords - Send records, the connection is established again if sending fails
+func (s *SyslogSink) WriteRecords(records LogRecordList) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, record := range records {
+		message := s.format(record)
+		if s.network != "udp" {
+			message = strconv.Itoa(len(message)) + " " + message
+		}
+		if err := s.write(message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SyslogSink) write(message string) error {
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			if err = s.dial(); err != nil {
+				continue
+			}
+		}
+		_ = s.conn.SetWriteDeadline(time.Now().Add(s.options.Timeout))
+		if _, err = io.WriteString(s.conn, message); err == nil {
+			return nil
+		}
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+	return err
+}
+
+// Close - Close the connection to collector
+func (s *SyslogSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// format builds the message: <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD] MSG
+func (s *SyslogSink) format(record LogRecord) string {
+	priority := syslogFacility(s.options.Facility)*8 + syslogSeverity(record.LogName)
+	host := syslogField(record.Host, 255)
+	structured := fmt.Sprintf(`[kerio@%s log="%s" line="%d"]`, syslogEnterpriseId, syslogParam(string(record.LogName)), record.Line)
+	return fmt.Sprintf("<%d>1 %s %s %s - %s %s %s", priority, record.Time.Format("2006-01-02T15:04:05.000000Z07:00"),
+		host, syslogField(s.options.AppName, 48), syslogField(string(record.LogName), 32), structured, record.Text)
+}
+
+// syslogFacility returns number of facility or -1 if it is unknown
+func syslogFacility(facility FacilityUnit) int {
+	for i, known := range []FacilityUnit{FacilityKernel, FacilityUserLevel, FacilityMailSystem, FacilitySystemDaemons,
+		FacilitySecurity1, FacilityInternal, FacilityLinePrinter, FacilityNetworkNews, FacilityUucpSubsystem,
+		FacilityClockDaemon1, FacilitySecurity2, FacilityFtpDaemon, FacilityNtpSubsystem, FacilityLogAudit,
+		FacilityLogAlert, FacilityClockDaemon2, FacilityLocal0, FacilityLocal1, FacilityLocal2, FacilityLocal3,
+		FacilityLocal4, FacilityLocal5, FacilityLocal6, FacilityLocal7} {
+		if known == facility {
+			return i
+		}
+	}
+	return -1
+}
+
+// syslogSeverity derives severity from the log the record comes from
+func syslogSeverity(logName LogType) int {
+	switch logName {
+	case "error":
+		return 3
+	case "warning":
+		return 4
+	case SecurityLog:
+		return 5
+	case "debug":
+		return 7
+	}
+	return 6
+}
+
+// syslogField returns printable ASCII value limited to the length, "-" for empty value
+func syslogField(value string, length int) string {
+	value = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return -1
+		}
+		return r
+	}, value)
+	if len(value) > length {
+		value = value[:length]
+	}
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+var syslogParamReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+func syslogParam(value string) string {
+	return syslogParamReplacer.Replace(value)
+}
+
+// FileSink - Writes rows of each log to its own file in the directory and rotates the files by size
+type FileSink struct {
+	dir      string
+	maxSize  int64
+	maxFiles int
+	mutex    sync.Mutex
+	files    map[LogType]*os.File
+}
+
+// NewFileSink - Create sink writing to files named by logs, e.g. mail.log.
+//	dir - directory of files, it is created if missing
+//	maxSize - size in bytes when the file is rotated; 0 means no rotation
+//	maxFiles - count of kept rotated files, mail.log.1 is the newest one
+func NewFileSink(dir string, maxSize int64, maxFiles int) (*FileSink, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, err
+	}
+	return &FileSink{dir: dir, maxSize: maxSize, maxFiles: maxFiles, files: map[LogType]*os.File{}}, nil
+}
+
+// WriteRecords - Append rows to files and flush them to the disk
+func (f *FileSink) WriteRecords(records LogRecordList) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	written := map[LogType]*os.File{}
+	for _, record := range records {
+		line := record.Content + "\n"
+		file, err := f.file(record.LogName, int64(len(line)))
+		if err != nil {
+			return err
+		}
+		if _, err = file.WriteString(line); err != nil {
+			return err
+		}
+		written[record.LogName] = file
+	}
+	for _, file := range written {
+		if err := file.Sync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// file returns open file of the log, rotates it first if the line does not fit
+func (f *FileSink) file(logName LogType, length int64) (*os.File, error) {
+	name := filepath.Join(f.dir, filepath.Base(string(logName))+".log")
+	file := f.files[logName]
+	if file != nil && f.maxSize > 0 {
+		info, err := file.Stat()
+		if err != nil {
+			return nil, err
+		}
+		if info.Size() > 0 && info.Size()+length > f.maxSize {
+			if err = file.Sync(); err != nil {
+				return nil, err
+			}
+			_ = file.Close()
+			delete(f.files, logName)
+			file = nil
+			if err = rotateFiles(name, f.maxFiles); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if file == nil {
+		var err error
+		if file, err = os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640); err != nil {
+			return nil, err
+		}
+		f.files[logName] = file
+	}
+	return file, nil
+}
+
+// rotateFiles renames name to name.1, name.1 to name.2 and so on, the oldest file is removed
+func rotateFiles(name string, count int) error {
+	if count <= 0 {
+		return os.Remove(name)
+	}
+	_ = os.Remove(fmt.Sprintf("%s.%d", name, count))
+	for i := count - 1; i > 0; i-- {
+		if err := os.Rename(fmt.Sprintf("%s.%d", name, i), fmt.Sprintf("%s.%d", name, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(name, name+".1")
+}
+
+// Close - Close all files
+func (f *FileSink) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	var result error
+	for logName, file := range f.files {
+		if err := file.Close(); err != nil && result == nil {
+			result = err
+		}
+		delete(f.files, logName)
+	}
+	return result
+}
+
+// JsonLinesSink - Writes each record as one line of JSON, e.g. to stdout
+type JsonLinesSink struct {
+	w     io.Writer
+	mutex sync.Mutex
+}
+
+// NewJsonLinesSink - Create sink writing to w
+func NewJsonLinesSink(w io.Writer) *JsonLinesSink {
+	return &JsonLinesSink{w: w}
+}
+
+// WriteRecords - Write records, the output is flushed after all of them
+func (j *JsonLinesSink) WriteRecords(records LogRecordList) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	buffer := bufio.NewWriter(j.w)
+	encoder := json.NewEncoder(buffer)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return buffer.Flush()
+}
+
+// Close - Nothing to close, the writer is owned by the caller
+func (j *JsonLinesSink) Close() error {
+	return nil
+}
@@ -0,0 +1,186 @@
+package connect
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	DefaultTailPollInterval = 2 * time.Second // pause between polls when there are no new lines
+	DefaultTailPageSize     = 1000            // lines requested by one LogsGet call
+	maxLogLines             = 50000           // maximum of returned lines at once
+	logEnd                  = -1              // Unlimited, symbolic name for end of log
+)
+
+// LogCursor - Position in the log which can be saved and used to resume tailing
+type LogCursor struct {
+	LogName LogType `json:"logName"` // name of the log
+	Line    int     `json:"line"`    // number of the next line to read
+	Total   int     `json:"total"`   // count of log lines known when the cursor was created
+}
+
+// LogEntry - Row of the tailed log together with the cursor pointing behind it
+type LogEntry struct {
+	LogRow
+	Cursor LogCursor `json:"cursor"`
+}
+
+// TailOptions - Options of TailLog
+type TailOptions struct {
+	Cursor       *LogCursor                  // resume behind the saved position, LastLines is ignored
+	LastLines    int                         // start with the last lines of the log; 0 means new lines only
+	PollInterval time.Duration               // pause between polls; DefaultTailPollInterval if 0
+	PageSize     int                         // lines requested at once; DefaultTailPageSize if 0
+	OnRotate     func(previous, current int) // called when the count of lines went backwards, tailing continues from the beginning
+	OnError      func(err error)             // called when polling fails, tailing continues after PollInterval
+}
+
+// TailLog - Follow the log and send its new rows to the channel. The channel is closed when ctx is done.
+// Rotation and LogsClear are detected from the count of lines going backwards, the log is then read from the beginning.
+// A rotation followed by writing more lines than were read before the next poll can not be detected.
+//	ctx - context stopping the tail
+//	logName - unique name of the log
+//	options - where to start and how to poll; nil means new lines only with default polling
+// Return
+//	rows - channel with log rows including their highlight color
+func (s *ServerConnection) TailLog(ctx context.Context, logName LogType, options *TailOptions) <-chan LogRow {
+	entries := s.TailLogEntries(ctx, logName, options)
+	rows := make(chan LogRow)
+	go func() {
+		defer close(rows)
+		for entry := range entries {
+			select {
+			case rows <- entry.LogRow:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return rows
+}
+
+// TailLogEntries - Same as TailLog, but every row carries the cursor behind it. Saving the cursor of the
+// processed entry and passing it in TailOptions.Cursor later resumes without losing lines.
+func (s *ServerConnection) TailLogEntries(ctx context.Context, logName LogType, options *TailOptions) <-chan LogEntry {
+	t := s.newLogTail(logName, options)
+	entries := make(chan LogEntry)
+	go t.run(ctx, entries)
+	return entries
+}
+
+func (s *ServerConnection) newLogTail(logName LogType, options *TailOptions) *logTail {
+	t := &logTail{s: s, logName: logName}
+	if options != nil {
+		t.options = *options
+	}
+	if t.options.PollInterval <= 0 {
+		t.options.PollInterval = DefaultTailPollInterval
+	}
+	if t.options.PageSize <= 0 {
+		t.options.PageSize = DefaultTailPageSize
+	}
+	if t.options.PageSize > maxLogLines {
+		t.options.PageSize = maxLogLines
+	}
+	return t
+}
+
+// logTail holds the state of one tailed log
+type logTail struct {
+	s       *ServerConnection
+	logName LogType
+	options TailOptions
+	cursor  LogCursor
+	started bool
+}
+
+func (t *logTail) run(ctx context.Context, entries chan<- LogEntry) {
+	defer close(entries)
+	for {
+		read, more, err := t.read()
+		if err != nil && t.options.OnError != nil {
+			t.options.OnError(err)
+		}
+		for _, entry := range read {
+			select {
+			case entries <- entry:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if more && err == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(t.options.PollInterval):
+		}
+	}
+}
+
+// read returns next rows of the log; more is true if the page was full and next rows should be read immediately
+func (t *logTail) read() (entries []LogEntry, more bool, err error) {
+	if !t.started {
+		entries, err = t.start()
+		return entries, false, err
+	}
+	rows, total, err := t.s.LogsGet(t.logName, t.cursor.Line, t.options.PageSize)
+	if err != nil {
+		return nil, false, err
+	}
+	if total < t.cursor.Line || total < t.cursor.Total {
+		t.rotated(total)
+		return nil, true, nil
+	}
+	t.cursor.Total = total
+	return t.entries(rows), len(rows) == t.options.PageSize, nil
+}
+
+// start places the cursor according to options and returns the last rows if requested
+func (t *logTail) start() ([]LogEntry, error) {
+	if t.options.Cursor != nil {
+		t.cursor = *t.options.Cursor
+		t.cursor.LogName = t.logName
+		_, total, err := t.s.LogsGet(t.logName, logEnd, 0)
+		if err != nil {
+			return nil, err
+		}
+		t.started = true
+		if total < t.cursor.Line || total < t.cursor.Total {
+			t.rotated(total)
+		}
+		return nil, nil
+	}
+	rows, total, err := t.s.LogsGet(t.logName, logEnd, t.options.LastLines)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > total {
+		rows = rows[len(rows)-total:]
+	}
+	t.started = true
+	t.cursor = LogCursor{LogName: t.logName, Line: total - len(rows), Total: total}
+	return t.entries(rows), nil
+}
+
+func (t *logTail) rotated(total int) {
+	if t.options.OnRotate != nil {
+		t.options.OnRotate(t.cursor.Total, total)
+	}
+	t.cursor.Line = 0
+	t.cursor.Total = 0
+}
+
+// entries moves the cursor behind each of rows
+func (t *logTail) entries(rows LogRowList) []LogEntry {
+	entries := make([]LogEntry, 0, len(rows))
+	for _, row := range rows {
+		t.cursor.Line++
+		if t.cursor.Total < t.cursor.Line {
+			t.cursor.Total = t.cursor.Line
+		}
+		entries = append(entries, LogEntry{LogRow: row, Cursor: t.cursor})
+	}
+	return entries
+}
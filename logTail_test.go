@@ -0,0 +1,121 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeLog serves Logs.get from lines which can be changed by the test
+type fakeLog struct {
+	mu    sync.Mutex
+	lines LogRowList
+}
+
+func (f *fakeLog) set(lines LogRowList) {
+	f.mu.Lock()
+	f.lines = lines
+	f.mu.Unlock()
+}
+
+func (f *fakeLog) get(params json.RawMessage) interface{} {
+	request := struct {
+		FromLine   int `json:"fromLine"`
+		CountLines int `json:"countLines"`
+	}{}
+	_ = json.Unmarshal(params, &request)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	from := request.FromLine
+	if from == logEnd {
+		from = len(f.lines) - request.CountLines
+	}
+	if from < 0 {
+		from = 0
+	}
+	to := from + request.CountLines
+	if to > len(f.lines) {
+		to = len(f.lines)
+	}
+	viewport := LogRowList{}
+	if from < to {
+		viewport = append(viewport, f.lines[from:to]...)
+	}
+	return map[string]interface{}{"viewport": viewport, "totalItems": len(f.lines)}
+}
+
+func logLines(prefix string, count int) LogRowList {
+	lines := make(LogRowList, count)
+	for i := range lines {
+		lines[i] = LogRow{Content: fmt.Sprintf("%s%d", prefix, i+1), Highlight: "FF0000"}
+	}
+	return lines
+}
+
+func receiveEntries(t *testing.T, entries <-chan LogEntry, count int) []LogEntry {
+	t.Helper()
+	var received []LogEntry
+	for len(received) < count {
+		select {
+		case entry := <-entries:
+			received = append(received, entry)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("received %d of %d entries", len(received), count)
+		}
+	}
+	return received
+}
+
+func TestTailLogRotation(t *testing.T) {
+	log := &fakeLog{lines: logLines("a", 5)}
+	conn, _ := newFakeConnection(t, map[string]fakeHandler{"Logs.get": log.get})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var rotations []int
+	entries := conn.TailLogEntries(ctx, "mail", &TailOptions{
+		LastLines:    2,
+		PollInterval: 10 * time.Millisecond,
+		PageSize:     2,
+		OnRotate:     func(previous, current int) { rotations = append(rotations, previous, current) },
+	})
+	received := receiveEntries(t, entries, 2)
+	if received[0].Content != "a4" || received[1].Content != "a5" || received[1].Highlight != "FF0000" {
+		t.Errorf("unexpected last lines: %+v", received)
+	}
+	log.set(logLines("a", 8))
+	received = receiveEntries(t, entries, 3)
+	if received[2].Content != "a8" || received[2].Cursor.Line != 8 {
+		t.Errorf("unexpected new lines: %+v", received)
+	}
+	log.set(logLines("b", 3))
+	received = receiveEntries(t, entries, 3)
+	if received[0].Content != "b1" || received[2].Cursor != (LogCursor{LogName: "mail", Line: 3, Total: 3}) {
+		t.Errorf("unexpected lines after rotation: %+v", received)
+	}
+	if len(rotations) != 2 || rotations[0] != 8 || rotations[1] != 3 {
+		t.Errorf("unexpected rotations: %v", rotations)
+	}
+}
+
+func TestTailLogResume(t *testing.T) {
+	log := &fakeLog{lines: logLines("a", 6)}
+	conn, _ := newFakeConnection(t, map[string]fakeHandler{"Logs.get": log.get})
+	ctx, cancel := context.WithCancel(context.Background())
+	rows := conn.TailLog(ctx, "mail", &TailOptions{Cursor: &LogCursor{Line: 4, Total: 4}, PollInterval: 10 * time.Millisecond})
+	for _, expected := range []string{"a5", "a6"} {
+		select {
+		case row := <-rows:
+			if row.Content != expected {
+				t.Errorf("expected %s, got %s", expected, row.Content)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("no row received")
+		}
+	}
+	cancel()
+	for range rows {
+	}
+}
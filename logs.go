@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 // Global limits:
 // 1. maximum of returned lines at once is 50000
 // 2. maximal line length is 1024 (it does not include date, time, ID in the beginning of log line)
@@ -203,7 +201,7 @@ func (s *ServerConnection) LogsExportLog(logName LogType, fromLine int, countLin
 			FileDownload Download `json:"fileDownload"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &fileDownload)
+	err = s.unmarshal(data, &fileDownload)
 	return &fileDownload.Result.FileDownload, err
 }
 
@@ -230,7 +228,7 @@ func (s *ServerConnection) LogsExportLogRelative(logName LogType, fromLine int,
 			FileDownload Download `json:"fileDownload"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &fileDownload)
+	err = s.unmarshal(data, &fileDownload)
 	return &fileDownload.Result.FileDownload, err
 }
 
@@ -257,7 +255,7 @@ func (s *ServerConnection) LogsGet(logName LogType, fromLine int, countLines int
 			TotalItems int        `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &viewport)
+	err = s.unmarshal(data, &viewport)
 	return viewport.Result.Viewport, viewport.Result.TotalItems, err
 }
 
@@ -274,7 +272,7 @@ func (s *ServerConnection) LogsGetHighlightRules() (*HighlightRules, error) {
 			Rules HighlightRules `json:"rules"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &rules)
+	err = s.unmarshal(data, &rules)
 	return &rules.Result.Rules, err
 }
 
@@ -291,7 +289,7 @@ func (s *ServerConnection) LogsGetLogSet() (*LogSet, error) {
 			LogSet LogSet `json:"logSet"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &logSet)
+	err = s.unmarshal(data, &logSet)
 	return &logSet.Result.LogSet, err
 }
 
@@ -308,7 +306,7 @@ func (s *ServerConnection) LogsGetMessages() (TreeLeafList, error) {
 			Messages TreeLeafList `json:"messages"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &messages)
+	err = s.unmarshal(data, &messages)
 	return messages.Result.Messages, err
 }
 
@@ -339,7 +337,7 @@ func (s *ServerConnection) LogsGetSearchProgress(countLines int, searchId string
 			Percentage int          `json:"percentage"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &viewport)
+	err = s.unmarshal(data, &viewport)
 	return viewport.Result.Viewport,
 		viewport.Result.FirstLine,
 		viewport.Result.TotalItems,
@@ -365,7 +363,7 @@ func (s *ServerConnection) LogsGetSettings(logName LogType) (*LogSettings, error
 			CurrentSettings LogSettings `json:"currentSettings"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &currentSettings)
+	err = s.unmarshal(data, &currentSettings)
 	return &currentSettings.Result.CurrentSettings, err
 }
 
@@ -394,7 +392,7 @@ func (s *ServerConnection) LogsSearch(logName LogType, what string, fromLine int
 			SearchId string `json:"searchId"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &searchId)
+	err = s.unmarshal(data, &searchId)
 	return &searchId.Result.SearchId, err
 }
 
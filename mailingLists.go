@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 // MlPermission - Mailing List (=ML) Action Access Right Type
 type MlPermission string
 
@@ -160,7 +158,7 @@ func (s *ServerConnection) MailingListsAddMlUserList(members UserOrEmailList, ml
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -183,7 +181,7 @@ func (s *ServerConnection) MailingListsCreate(mailingLists MlList) (ErrorList, C
 			Result CreateResultList `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, errors.Result.Result, err
 }
 
@@ -206,7 +204,7 @@ func (s *ServerConnection) MailingListsExportMlUsersToCsv(kind MlMembership, mlI
 			FileDownload Download `json:"fileDownload"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &fileDownload)
+	err = s.unmarshal(data, &fileDownload)
 	return &fileDownload.Result.FileDownload, err
 }
 
@@ -231,7 +229,7 @@ func (s *ServerConnection) MailingListsGet(query SearchQuery, domainId KId) (MlL
 			TotalItems int    `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -257,7 +255,7 @@ func (s *ServerConnection) MailingListsGetMlUserList(query SearchQuery, mlId KId
 			TotalItems int             `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -280,7 +278,7 @@ func (s *ServerConnection) MailingListsGetMlUserListFromCsv(fileId string, mlToI
 			Members MLMemberImporteeList `json:"members"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &members)
+	err = s.unmarshal(data, &members)
 	return members.Result.Members, err
 }
 
@@ -297,7 +295,7 @@ func (s *ServerConnection) MailingListsGetSuffixes() (StringList, error) {
 			Suffixes StringList `json:"suffixes"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &suffixes)
+	err = s.unmarshal(data, &suffixes)
 	return suffixes.Result.Suffixes, err
 }
 
@@ -322,7 +320,7 @@ func (s *ServerConnection) MailingListsGetTrusteeTargetList(query SearchQuery, d
 			TotalItems int               `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -343,7 +341,7 @@ func (s *ServerConnection) MailingListsRemove(mlIds KIdList) (ErrorList, error)
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -366,7 +364,7 @@ func (s *ServerConnection) MailingListsRemoveMlUserList(members UserOrEmailList,
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -389,6 +387,6 @@ func (s *ServerConnection) MailingListsSet(mlIds KIdList, pattern Ml) (ErrorList
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
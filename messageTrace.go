@@ -0,0 +1,358 @@
+package connect
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Log names searched by TraceMessage in addition to mail, spam and security logs
+const FilterLog LogType = "filter"
+
+// TraceCriteria - Which message to trace; at least queue id, sender or recipient has to be given
+type TraceCriteria struct {
+	QueueId   string         `json:"queueId,omitempty"`   // queue ID of the message
+	Sender    string         `json:"sender,omitempty"`    // sender address
+	Recipient string         `json:"recipient,omitempty"` // recipient address
+	From      time.Time      `json:"from,omitempty"`      // beginning of the time window; zero means unbounded
+	To        time.Time      `json:"to,omitempty"`        // end of the time window; zero means unbounded
+	Location  *time.Location `json:"-"`                   // time zone of the server; nil means UTC
+}
+
+// TraceEventType - Step in the life of the message
+type TraceEventType string
+
+const (
+	TraceReceived   TraceEventType = "Received"   // message was accepted by the server
+	TraceFiltered   TraceEventType = "Filtered"   // message was processed by a content filter rule
+	TraceSpamScore  TraceEventType = "SpamScore"  // message was rated by spam filter
+	TraceRejected   TraceEventType = "Rejected"   // message was rejected or discarded as spam
+	TraceDelivered  TraceEventType = "Delivered"  // message was delivered locally or relayed
+	TraceBounced    TraceEventType = "Bounced"    // delivery failed
+	TraceQueued     TraceEventType = "Queued"     // message waits in the queue for next retry
+	TraceProcessing TraceEventType = "Processing" // message is being processed by the server
+	TraceSecurity   TraceEventType = "Security"   // security event related to the sender
+	TraceNotFound   TraceEventType = "NotFound"   // no trace of the message was found
+)
+
+// TraceEvent - One entry of message timeline
+type TraceEvent struct {
+	Time        time.Time      `json:"time,omitempty"`      // zero if the time is not known
+	Type        TraceEventType `json:"type"`                // step of the message
+	Source      string         `json:"source"`              // queue, processing or name of the log
+	QueueId     string         `json:"queueId,omitempty"`   // queue ID of the message
+	From        string         `json:"from,omitempty"`      // sender address
+	To          string         `json:"to,omitempty"`        // recipient address
+	Description string         `json:"description"`         // human readable description of the step
+	SpamScore   *float64       `json:"spamScore,omitempty"` // score of spam filter
+	NextTry     string         `json:"nextTry,omitempty"`   // when the queued message is sent again
+	Line        int            `json:"line,omitempty"`      // number of the log row
+	Row         string         `json:"row,omitempty"`       // the log row
+}
+
+// TraceEventList - Timeline ordered by time
+type TraceEventList []TraceEvent
+
+// MessageTrace - Result of TraceMessage
+type MessageTrace struct {
+	Criteria TraceCriteria  `json:"criteria"`
+	QueueIds StringList     `json:"queueIds"`           // queue IDs of found messages
+	Status   TraceEventType `json:"status"`             // the most significant final state of the message
+	Events   TraceEventList `json:"events"`             // timeline of the message
+	Warnings StringList     `json:"warnings,omitempty"` // logs which could not be searched
+}
+
+// TraceMessage - Find the message in the queue and in mail, spam, filter and security logs and merge found
+// entries into one timeline. Messages matching sender and recipient are looked up in the mail log first,
+// other logs are then searched by queue IDs of found messages. Only lines of the time window are searched.
+//	ctx - context stopping log searches
+//	criteria - queue id, sender, recipient and time window
+// Return
+//	trace - timeline of the message and its final status
+func (s *ServerConnection) TraceMessage(ctx context.Context, criteria TraceCriteria) (*MessageTrace, error) {
+	if criteria.QueueId == "" && criteria.Sender == "" && criteria.Recipient == "" {
+		return nil, fmt.Errorf("queue id, sender or recipient has to be given")
+	}
+	t := &messageTracer{s: s, criteria: criteria, queueIds: map[string]bool{}, seen: map[string]bool{}, ranges: map[LogType]LogLineRange{}}
+	if criteria.QueueId != "" {
+		t.queueIds[criteria.QueueId] = true
+	} else {
+		pattern := criteria.Recipient
+		if pattern == "" {
+			pattern = criteria.Sender
+		}
+		err := t.search(ctx, []LogType{MailLog}, pattern, func(event TraceEvent) bool {
+			if !t.matchesAddresses(event) {
+				return false
+			}
+			t.queueIds[event.QueueId] = true
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, queueId := range sortedSet(t.queueIds) {
+		queueId := queueId
+		err := t.search(ctx, []LogType{MailLog, SpamLog, FilterLog}, queueId, func(event TraceEvent) bool {
+			return hasQueueId(event, queueId)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if criteria.Sender != "" {
+		if err := t.search(ctx, []LogType{SecurityLog}, criteria.Sender, nil); err != nil {
+			return nil, err
+		}
+	}
+	if err := t.queue(); err != nil {
+		return nil, err
+	}
+	return t.trace(), nil
+}
+
+// messageTracer collects events of one TraceMessage call
+type messageTracer struct {
+	s        *ServerConnection
+	criteria TraceCriteria
+	queueIds map[string]bool
+	seen     map[string]bool
+	ranges   map[LogType]LogLineRange // lines of the time window by log
+	events   TraceEventList
+	warnings StringList
+}
+
+// search adds events of matching rows accepted by the function; nil accepts all rows.
+// Failed search of a log is recorded as warning, e.g. filter log does not exist on older servers.
+func (t *messageTracer) search(ctx context.Context, logNames []LogType, pattern string, accept func(event TraceEvent) bool) error {
+	for _, logName := range logNames {
+		lineRange, err := t.lineRange(logName)
+		if err != nil {
+			t.warnings = append(t.warnings, fmt.Sprintf("search in %s log: %v", logName, err))
+			continue
+		}
+		matches, errs := t.s.SearchLog(ctx, logName, pattern, lineRange, nil)
+		for match := range matches {
+			key := fmt.Sprintf("%s:%d", match.LogName, match.Line)
+			if t.seen[key] {
+				continue
+			}
+			event, ok := t.logEvent(match)
+			if !ok || !t.inWindow(event.Time) || accept != nil && !accept(event) {
+				continue
+			}
+			t.seen[key] = true
+			t.events = append(t.events, event)
+		}
+		for err := range errs {
+			t.warnings = append(t.warnings, err.Error())
+		}
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lineRange converts the time window of criteria to lines of the log, once for each log
+func (t *messageTracer) lineRange(logName LogType) (LogLineRange, error) {
+	if lineRange, ok := t.ranges[logName]; ok {
+		return lineRange, nil
+	}
+	lineRange, err := t.s.LogLineRangeForTime(logName, t.criteria.From, t.criteria.To, t.criteria.Location)
+	if err != nil {
+		return lineRange, err
+	}
+	t.ranges[logName] = lineRange
+	return lineRange, nil
+}
+
+// hasQueueId checks the queue ID of the event; row without parsed queue ID has to contain it as a whole word
+func hasQueueId(event TraceEvent, queueId string) bool {
+	if event.QueueId != "" {
+		return event.QueueId == queueId
+	}
+	words := strings.FieldsFunc(event.Row, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' && r != '.'
+	})
+	for _, word := range words {
+		if strings.TrimRight(word, ".") == queueId {
+			return true
+		}
+	}
+	return false
+}
+
+// logEvent converts the matching row, false means the row does not belong to traced messages
+func (t *messageTracer) logEvent(match LogMatch) (TraceEvent, bool) {
+	event := TraceEvent{Source: string(match.LogName) + " log", Line: match.Line, Row: match.Content}
+	switch match.LogName {
+	case MailLog:
+		mail, err := ParseMailLogRow(match.LogRow, t.criteria.Location)
+		if err != nil {
+			return event, false
+		}
+		event.Time, event.QueueId, event.From, event.To = mail.Time, mail.QueueId, mail.From, mail.To
+		switch strings.ToLower(mail.Result) {
+		case "":
+			event.Type = TraceReceived
+			event.Description = fmt.Sprintf("%s received by %s from %s", mail.Action, mail.Service, mail.SenderHost)
+		case "failed", "bounced", "rejected":
+			event.Type = TraceBounced
+			event.Description = fmt.Sprintf("delivery to %s failed: %s", mail.To, mail.Status)
+		default:
+			event.Type = TraceDelivered
+			event.Description = fmt.Sprintf("%s to %s via %s", mail.Result, mail.To, mail.RemoteHost)
+		}
+	case SpamLog:
+		spam, err := ParseSpamLogRow(match.LogRow, t.criteria.Location)
+		if err != nil {
+			return event, false
+		}
+		event.Time, event.QueueId, event.From, event.To = spam.Time, spam.QueueId, spam.From, spam.To
+		event.Type, event.SpamScore = TraceSpamScore, &spam.Score
+		if strings.Contains(spam.Verdict, "rejected") || strings.Contains(spam.Verdict, "discarded") {
+			event.Type = TraceRejected
+		}
+		event.Description = fmt.Sprintf("message %s with score %.2f", spam.Verdict, spam.Score)
+	case SecurityLog:
+		security, err := ParseSecurityLogRow(match.LogRow, t.criteria.Location)
+		if err != nil {
+			return event, false
+		}
+		event.Time, event.Type, event.Description = security.Time, TraceSecurity, security.Message
+	default:
+		message, err := ParseLogMessage(match.LogRow, t.criteria.Location)
+		if err != nil {
+			return event, false
+		}
+		event.Time, event.Type, event.Description = message.Time, TraceFiltered, message.Text
+	}
+	return event, true
+}
+
+func (t *messageTracer) matchesAddresses(event TraceEvent) bool {
+	if event.QueueId == "" {
+		return false
+	}
+	if t.criteria.Sender != "" && !strings.EqualFold(event.From, t.criteria.Sender) {
+		return false
+	}
+	return t.criteria.Recipient == "" || strings.EqualFold(event.To, t.criteria.Recipient)
+}
+
+func (t *messageTracer) inWindow(timestamp time.Time) bool {
+	if timestamp.IsZero() {
+		return true
+	}
+	if !t.criteria.From.IsZero() && timestamp.Before(t.criteria.From) {
+		return false
+	}
+	return t.criteria.To.IsZero() || !timestamp.After(t.criteria.To)
+}
+
+// queue adds messages waiting in the queue or being processed
+func (t *messageTracer) queue() error {
+	queued, _, _, err := t.s.QueueGet(SearchQuery{})
+	if err != nil {
+		return err
+	}
+	for _, message := range queued {
+		if !t.matchesQueue(string(message.Id), message.From, message.To) {
+			continue
+		}
+		t.queueIds[string(message.Id)] = true
+		created, _ := parseServerTime(message.CreationTime, t.criteria.Location)
+		t.events = append(t.events, TraceEvent{Time: created, Type: TraceQueued, Source: "queue", QueueId: string(message.Id),
+			From: message.From, To: message.To, NextTry: message.NextTry,
+			Description: fmt.Sprintf("waiting in queue (%s), next try %s", message.Status, message.NextTry)})
+	}
+	processed, _, err := t.s.QueueGetProcessed(SearchQuery{})
+	if err != nil {
+		return err
+	}
+	for _, message := range processed {
+		if !t.matchesQueue(string(message.Id), message.From, message.To) {
+			continue
+		}
+		t.queueIds[string(message.Id)] = true
+		t.events = append(t.events, TraceEvent{Type: TraceProcessing, Source: "processing", QueueId: string(message.Id),
+			From: message.From, To: message.To,
+			Description: fmt.Sprintf("%s %d%% on %s for %s", message.Status, message.Percentage, message.Server, message.Time)})
+	}
+	return nil
+}
+
+func (t *messageTracer) matchesQueue(queueId, from, to string) bool {
+	if t.queueIds[queueId] {
+		return true
+	}
+	if t.criteria.QueueId != "" {
+		return false
+	}
+	if t.criteria.Sender != "" && !strings.EqualFold(trimAngleBrackets(from), t.criteria.Sender) {
+		return false
+	}
+	return t.criteria.Recipient == "" || strings.EqualFold(trimAngleBrackets(to), t.criteria.Recipient)
+}
+
+// trace orders events by time, events without time go last
+func (t *messageTracer) trace() *MessageTrace {
+	sort.SliceStable(t.events, func(i, j int) bool {
+		a, b := t.events[i].Time, t.events[j].Time
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero() && b.IsZero()
+		}
+		return a.Before(b)
+	})
+	trace := &MessageTrace{Criteria: t.criteria, QueueIds: sortedSet(t.queueIds), Status: TraceNotFound, Events: t.events, Warnings: t.warnings}
+	if trace.QueueIds == nil {
+		trace.QueueIds = StringList{}
+	}
+	// the latest delivery result wins, a message still in the queue or being processed is not finished
+	for _, event := range t.events {
+		switch event.Type {
+		case TraceQueued, TraceProcessing:
+			if trace.Status != TraceProcessing {
+				trace.Status = event.Type
+			}
+		case TraceDelivered, TraceBounced, TraceRejected:
+			if trace.Status != TraceQueued && trace.Status != TraceProcessing {
+				trace.Status = event.Type
+			}
+		case TraceReceived, TraceFiltered, TraceSpamScore:
+			if trace.Status == TraceNotFound {
+				trace.Status = TraceReceived
+			}
+		}
+	}
+	return trace
+}
+
+// parseServerTime parses time sent as a string, e.g. creation time of queued message
+func parseServerTime(value string, location *time.Location) (time.Time, error) {
+	if location == nil {
+		location = time.UTC
+	}
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{logTimeLayout, "2006-01-02 15:04:05", "2006-01-02 15:04", time.RFC3339,
+		"2006-01-02T15:04:05", "02.01.2006 15:04:05", "02.01.2006 15:04", "1/2/2006 3:04:05 PM", "1/2/2006 3:04 PM"} {
+		if parsed, err := time.ParseInLocation(layout, value, location); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", value)
+}
+
+func sortedSet(set map[string]bool) StringList {
+	var list StringList
+	for value := range set {
+		list = append(list, value)
+	}
+	sort.Strings(list)
+	return list
+}
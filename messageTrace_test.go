@@ -0,0 +1,70 @@
+package connect
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTraceMessage(t *testing.T) {
+	search := &fakeSearch{searches: map[string]fakeSearchState{}, logs: map[LogType]LogRowList{
+		MailLog: {
+			{Content: "[28/Jan/2021 10:15:33] Recv: Queue-ID: q1, Service: SMTP, From: <alice@example.com>, To: <bob@example.com>, Size: 10, Sender-Host: 10.0.0.5"},
+			{Content: "[28/Jan/2021 10:15:33] Recv: Queue-ID: q2, Service: SMTP, From: <carol@example.com>, To: <bob@example.com>, Size: 10"},
+			{Content: "[28/Jan/2021 10:15:36] Sent: Queue-ID: q1, Recipient: <bob@example.com>, Result: delivered, Status: 2.0.0, Remote-Host: 127.0.0.1"},
+			{Content: "[28/Jan/2021 10:20:00] Sent: Queue-ID: q10, Recipient: <bob@example.com>, Result: delivered, Status: 2.0.0, Remote-Host: 127.0.0.1"},
+			{Content: "[29/Jan/2021 08:00:00] Recv: Queue-ID: q3, Service: SMTP, From: <alice@example.com>, To: <bob@example.com>, Size: 10"},
+		},
+		SpamLog: {
+			{Content: "[28/Jan/2021 10:15:34] Message is not spam with score: 1.50, To: bob@example.com, From: alice@example.com, Queue-ID: q1"},
+			{Content: "[28/Jan/2021 10:20:00] Message is spam with score: 9.50, To: bob@example.com, From: eve@example.org, Queue-ID: q10"},
+		},
+		SecurityLog: {
+			{Content: "[28/Jan/2021 10:14:00] SMTP: Invalid password for user alice@example.com. Attempt from IP address 10.0.0.5."},
+		},
+	}}
+	handlers := search.handlers()
+	handlers["Queue.get"] = result(map[string]interface{}{"list": MessageInQueueList{
+		{Id: "q9", CreationTime: "2021-01-28 11:00", NextTry: "2021-01-28 11:30", From: "alice@example.com", To: "bob@example.com", Status: "Remote host not responding"},
+		{Id: "q8", From: "dave@example.com", To: "bob@example.com"},
+	}})
+	handlers["Queue.getProcessed"] = result(map[string]interface{}{"list": MessageInProcessList{}})
+	conn, _ := newFakeConnection(t, handlers)
+	trace, err := conn.TraceMessage(context.Background(), TraceCriteria{
+		Sender:    "alice@example.com",
+		Recipient: "bob@example.com",
+		From:      time.Date(2021, 1, 28, 0, 0, 0, 0, time.UTC),
+		To:        time.Date(2021, 1, 28, 23, 59, 59, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// searches of the mail log end with the last row of the window
+	for _, state := range search.searches {
+		if state.logName == MailLog && state.to != 3 {
+			t.Errorf("mail log searched up to line %d", state.to)
+		}
+	}
+	var types []TraceEventType
+	for _, event := range trace.Events {
+		types = append(types, event.Type)
+	}
+	expected := []TraceEventType{TraceSecurity, TraceReceived, TraceSpamScore, TraceDelivered, TraceQueued}
+	if len(types) != len(expected) {
+		t.Fatalf("unexpected timeline %v", types)
+	}
+	for i := range expected {
+		if types[i] != expected[i] {
+			t.Fatalf("unexpected timeline %v", types)
+		}
+	}
+	if trace.Status != TraceQueued || len(trace.QueueIds) != 2 || trace.QueueIds[0] != "q1" || trace.QueueIds[1] != "q9" {
+		t.Errorf("unexpected trace status %s, queue ids %v", trace.Status, trace.QueueIds)
+	}
+	if score := trace.Events[2].SpamScore; score == nil || *score != 1.5 {
+		t.Errorf("unexpected spam score %v", score)
+	}
+	if trace.Events[4].NextTry != "2021-01-28 11:30" || trace.Events[4].Time.Hour() != 11 {
+		t.Errorf("unexpected queue event %+v", trace.Events[4])
+	}
+}
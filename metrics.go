@@ -0,0 +1,478 @@
+package connect
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetricType - Type of metric family in the exposition format
+type MetricType string
+
+const (
+	MetricGauge   MetricType = "gauge"
+	MetricCounter MetricType = "counter"
+)
+
+// MetricsNamespace - Prefix of all exported metric names
+const MetricsNamespace = "kerio_connect"
+
+// Metric - One sample of metric family
+type Metric struct {
+	Labels map[string]string `json:"labels,omitempty"`
+	Value  float64           `json:"value"`
+}
+
+// MetricList - List of samples
+type MetricList []Metric
+
+// MetricFamily - Named group of samples of the same type. Name of counter family does not contain the _total suffix.
+type MetricFamily struct {
+	Name    string     `json:"name"`
+	Type    MetricType `json:"type"`
+	Help    string     `json:"help"`
+	Metrics MetricList `json:"metrics"`
+}
+
+// MetricFamilyList - List of metric families
+type MetricFamilyList []MetricFamily
+
+// MetricsExporter - http.Handler exposing server statistics in Prometheus text or OpenMetrics format.
+// Every scrape calls the server, so scrapes are serialized. Exported families (without namespace prefix):
+//	up - 1 if all sources were read successfully
+//	scrape_success{source} - 1 if the source (statistics, health, queue, connections, license, backup, alerts) was read
+//	scrape_duration_seconds - time spent by reading all sources
+//	start_time_seconds, uptime_seconds - server start and uptime
+//	storage_total_bytes, storage_occupied_bytes - space on the store partition
+//	messages{direction}, messages_bytes{direction}, message_recipients{direction} - counters of MessageThroughput
+//		directions are received, stored_in_queue, transmitted, delivered_to_locals, mx and relay
+//	delivery_failures{kind} - transient and permanent delivery failures
+//	delivery_notifications{kind} - sent success, delay and failure notifications
+//	antivirus_checked_attachments, antivirus_found_viruses, antivirus_prohibited_types - antivirus counters
+//	spam_messages{result} - checked, tagged, rejected, marked_as_spam and marked_as_not_spam messages
+//	largest_message_bytes, message_loops - other statistics
+//	incoming_connections{service}, authentication_failures{service} - smtp, pop3, imap, ldap, web and xmpp servers
+//	ldap_search_requests - search requests received by the LDAP server
+//	smtp_server_events{event}, smtp_client_events{event}, pop3_client_events{event} - remaining protocol counters
+//	dns_queries{type,cached}, antibombing_rejections{kind}, greylisting_messages{result} - remaining counters
+//	cpu_usage_percent, memory_usage_percent - the latest sample of SystemHealthGet
+//	memory_total_bytes, disk_total_bytes, disk_free_bytes - system resources
+//	queue_messages, queue_bytes - messages waiting in the queue
+//	connections{protocol} - active connections by protocol from ServerGetConnections
+//	license_expiry_days{type}, license_unlimited{type} - license and subscription expiration
+//	backup_in_progress, backup_progress_percent, backup_last_status{status} - backup status
+//	backup_last_timestamp_seconds{type}, backup_last_size_bytes{type} - the latest full, differential and mirror backup
+//	alert{name,type}, alerts - active alerts
+type MetricsExporter struct {
+	s     *ServerConnection
+	mutex sync.Mutex
+}
+
+// NewMetricsExporter - Create exporter reading statistics through the connection.
+// The connection has to be logged in and kept alive by the caller.
+func (s *ServerConnection) NewMetricsExporter() *MetricsExporter {
+	return &MetricsExporter{s: s}
+}
+
+// ServeHTTP - Scrape the server and write metrics. OpenMetrics format is used if the client accepts it.
+func (e *MetricsExporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	families := e.Collect()
+	openMetrics := strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text")
+	if openMetrics {
+		w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	}
+	_ = WriteMetrics(w, families, openMetrics)
+}
+
+// Collect - Read all sources and return metric families. A failed source is reported by scrape_success
+// and does not prevent other sources from being exported.
+func (e *MetricsExporter) Collect() MetricFamilyList {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	started := time.Now()
+	m := &metricSet{}
+	sources := []struct {
+		name    string
+		collect func(*metricSet) error
+	}{
+		{"statistics", e.collectStatistics},
+		{"health", e.collectHealth},
+		{"queue", e.collectQueue},
+		{"connections", e.collectConnections},
+		{"license", e.collectLicense},
+		{"backup", e.collectBackup},
+		{"alerts", e.collectAlerts},
+	}
+	up := 1.0
+	for _, source := range sources {
+		success := 1.0
+		if err := source.collect(m); err != nil {
+			success = 0
+			up = 0
+		}
+		m.add("scrape_success", MetricGauge, "Whether the source was read successfully.", success, "source", source.name)
+	}
+	m.add("up", MetricGauge, "Whether all sources were read successfully.", up)
+	m.add("scrape_duration_seconds", MetricGauge, "Time spent by reading the server.", time.Since(started).Seconds())
+	return m.families
+}
+
+func (e *MetricsExporter) collectStatistics(m *metricSet) error {
+	statistics, err := e.s.StatisticsGet()
+	if err != nil {
+		return err
+	}
+	m.add("start_time_seconds", MetricGauge, "Start time of the server since unix epoch.", float64(statistics.Start))
+	m.add("uptime_seconds", MetricGauge, "Server uptime.", statistics.Uptime.Seconds())
+	m.add("storage_total_bytes", MetricGauge, "Total space on the store partition.", float64(statistics.Storage.Total.ByteCount()))
+	m.add("storage_occupied_bytes", MetricGauge, "Occupied space on the store partition.", float64(statistics.Storage.Occupied.ByteCount()))
+	for _, throughput := range []struct {
+		direction string
+		value     MessageThroughput
+	}{
+		{"received", statistics.Received},
+		{"stored_in_queue", statistics.StoredInQueue},
+		{"transmitted", statistics.Transmitted},
+		{"delivered_to_locals", statistics.DeliveredToLocals},
+		{"mx", statistics.Mx},
+		{"relay", statistics.Relay},
+	} {
+		m.counter("messages", "Messages processed by the server.", throughput.value.Count, "direction", throughput.direction)
+		m.add("messages_bytes", MetricCounter, "Volume of messages processed by the server.", float64(throughput.value.Volume.ByteCount()), "direction", throughput.direction)
+		m.counter("message_recipients", "Recipients of messages processed by the server.", throughput.value.Recipients, "direction", throughput.direction)
+	}
+	m.counter("delivery_failures", "Delivery failures.", statistics.Failures.TransientFailures, "kind", "transient")
+	m.counter("delivery_failures", "Delivery failures.", statistics.Failures.PermanentFailures, "kind", "permanent")
+	m.counter("delivery_notifications", "Sent delivery status notifications.", statistics.DeliveryStatus.Success, "kind", "success")
+	m.counter("delivery_notifications", "Sent delivery status notifications.", statistics.DeliveryStatus.Delay, "kind", "delay")
+	m.counter("delivery_notifications", "Sent delivery status notifications.", statistics.DeliveryStatus.Failure, "kind", "failure")
+	m.counter("antivirus_checked_attachments", "Attachments checked by antivirus.", statistics.Antivirus.CheckedAttachments)
+	m.counter("antivirus_found_viruses", "Viruses found by antivirus.", statistics.Antivirus.FoundViruses)
+	m.counter("antivirus_prohibited_types", "Prohibited filenames or MIME types found.", statistics.Antivirus.ProhibitedTypes)
+	for _, spam := range []metricValue{
+		{"checked", statistics.Spam.Checked},
+		{"tagged", statistics.Spam.Tagged},
+		{"rejected", statistics.Spam.Rejected},
+		{"marked_as_spam", statistics.Spam.MarkedAsSpam},
+		{"marked_as_not_spam", statistics.Spam.MarkedAsNotSpam},
+	} {
+		m.counter("spam_messages", "Messages processed by spam filter.", spam.value, "result", spam.label)
+	}
+	m.add("largest_message_bytes", MetricGauge, "The largest message received by the server.", float64(statistics.Other.Largest.ByteCount()))
+	m.counter("message_loops", "Detected message loops.", statistics.Other.Loops)
+	for _, service := range []struct {
+		name        string
+		connections string
+		failures    string
+	}{
+		{"smtp", statistics.SmtpServer.TotalIncomingConnections, statistics.SmtpServer.AuthenticationFailures},
+		{"pop3", statistics.Pop3Server.TotalIncomingConnections, statistics.Pop3Server.AuthenticationFailures},
+		{"imap", statistics.ImapServer.TotalIncomingConnections, statistics.ImapServer.AuthenticationFailures},
+		{"ldap", statistics.LdapServer.TotalIncomingConnections, statistics.LdapServer.AuthenticationFailures},
+		{"web", statistics.WebServer.TotalIncomingConnections, ""},
+		{"xmpp", statistics.XmppServer.TotalIncomingConnections, statistics.XmppServer.AuthenticationFailures},
+	} {
+		m.counter("incoming_connections", "Incoming connections accepted by the service.", service.connections, "service", service.name)
+		if service.name != "web" {
+			m.counter("authentication_failures", "Failed authentications to the service.", service.failures, "service", service.name)
+		}
+	}
+	smtpServer := statistics.SmtpServer
+	for _, event := range []metricValue{
+		{"lost_connection", smtpServer.LostConnections},
+		{"rejected_by_blacklist", smtpServer.RejectedByBlacklist},
+		{"authentication_attempt", smtpServer.AuthenticationAttempts},
+		{"rejected_relay", smtpServer.RejectedRelays},
+		{"accepted_message", smtpServer.AcceptedMessages},
+	} {
+		m.counter("smtp_server_events", "Events of the SMTP server.", event.value, "event", event.label)
+	}
+	smtpClient := statistics.SmtpClient
+	for _, event := range []metricValue{
+		{"connection_attempt", smtpClient.ConnectionAttempts},
+		{"dns_failure", smtpClient.DnsFailures},
+		{"connection_failure", smtpClient.ConnectionFailures},
+		{"connection_loss", smtpClient.ConnectionLosses},
+	} {
+		m.counter("smtp_client_events", "Events of the SMTP client.", event.value, "event", event.label)
+	}
+	pop3Client := statistics.Pop3Client
+	for _, event := range []metricValue{
+		{"connection_attempt", pop3Client.ConnectionAttempts},
+		{"connection_failure", pop3Client.ConnectionFailures},
+		{"authentication_failure", pop3Client.AuthenticationFailures},
+		{"download", pop3Client.TotalDownloads},
+	} {
+		m.counter("pop3_client_events", "Events of the POP3 client.", event.value, "event", event.label)
+	}
+	m.counter("ldap_search_requests", "Search requests received by the LDAP server.", statistics.LdapServer.TotalSearchRequests)
+	dns := statistics.DnsResolver
+	m.counter("dns_queries", "Queries of the DNS resolver.", dns.HostnameQueries, "type", "hostname", "cached", "false")
+	m.counter("dns_queries", "Queries of the DNS resolver.", dns.CachedHostnameQueries, "type", "hostname", "cached", "true")
+	m.counter("dns_queries", "Queries of the DNS resolver.", dns.MxQueries, "type", "mx", "cached", "false")
+	m.counter("dns_queries", "Queries of the DNS resolver.", dns.CachedMxQueries, "type", "mx", "cached", "true")
+	m.counter("antibombing_rejections", "Rejections by antibombing.", statistics.Antibombing.RejectedConnections, "kind", "connection")
+	m.counter("antibombing_rejections", "Rejections by antibombing.", statistics.Antibombing.RejectedMessages, "kind", "message")
+	m.counter("antibombing_rejections", "Rejections by antibombing.", statistics.Antibombing.RejectedHarvestAttacks, "kind", "harvest_attack")
+	m.counter("greylisting_messages", "Messages processed by greylisting.", statistics.Greylisting.MessagesAccepted, "result", "accepted")
+	m.counter("greylisting_messages", "Messages processed by greylisting.", statistics.Greylisting.MessagesDelayed, "result", "delayed")
+	m.counter("greylisting_messages", "Messages processed by greylisting.", statistics.Greylisting.MessagesSkipped, "result", "skipped")
+	return nil
+}
+
+func (e *MetricsExporter) collectHealth(m *metricSet) error {
+	health, err := e.s.SystemHealthGet(HistogramTwoHours)
+	if err != nil {
+		return err
+	}
+	if len(health.Cpu) > 0 {
+		m.add("cpu_usage_percent", MetricGauge, "The latest CPU usage sample.", health.Cpu[len(health.Cpu)-1])
+	}
+	if len(health.Memory) > 0 {
+		m.add("memory_usage_percent", MetricGauge, "The latest memory usage sample.", health.Memory[len(health.Memory)-1])
+	}
+	m.add("memory_total_bytes", MetricGauge, "Total memory.", health.MemoryTotal)
+	m.add("disk_total_bytes", MetricGauge, "Total space on the data partition.", health.DiskTotal)
+	m.add("disk_free_bytes", MetricGauge, "Free space on the data partition.", health.DiskFree)
+	return nil
+}
+
+func (e *MetricsExporter) collectQueue(m *metricSet) error {
+	_, total, volume, err := e.s.QueueGet(SearchQuery{Limit: 1})
+	if err != nil {
+		return err
+	}
+	m.add("queue_messages", MetricGauge, "Messages waiting in the queue.", float64(total))
+	m.add("queue_bytes", MetricGauge, "Volume of messages waiting in the queue.", float64(volume.ByteCount()))
+	return nil
+}
+
+func (e *MetricsExporter) collectConnections(m *metricSet) error {
+	connections, _, err := e.s.ServerGetConnections(SearchQuery{})
+	if err != nil {
+		return err
+	}
+	counts := map[string]int{}
+	var protocols []string
+	for _, protocol := range []Protocol{ProtocolAdmin, ProtocolSmtp, ProtocolSmtps, ProtocolSubmission, ProtocolPop3, ProtocolPop3s,
+		ProtocolImap, ProtocolImaps, ProtocolNntp, ProtocolNntps, ProtocolLdap, ProtocolLdaps, ProtocolHttp, ProtocolHttps,
+		ProtocolXmpp, ProtocolXmpps} {
+		counts[protocol.metricLabel()] = 0
+		protocols = append(protocols, protocol.metricLabel())
+	}
+	for _, connection := range connections {
+		label := connection.Proto.metricLabel()
+		if _, ok := counts[label]; !ok {
+			protocols = append(protocols, label)
+		}
+		counts[label]++
+	}
+	for _, protocol := range protocols {
+		m.add("connections", MetricGauge, "Active connections by protocol.", float64(counts[protocol]), "protocol", protocol)
+	}
+	return nil
+}
+
+func (e *MetricsExporter) collectLicense(m *metricSet) error {
+	status, err := e.s.ProductRegistrationGetFullStatus()
+	if err != nil {
+		return err
+	}
+	for _, expiration := range status.Expirations {
+		unlimited := 0.0
+		if expiration.IsUnlimited {
+			unlimited = 1
+		} else {
+			m.add("license_expiry_days", MetricGauge, "Days remaining to expiration.", float64(expiration.RemainingDays), "type", strings.ToLower(string(expiration.Type)))
+		}
+		m.add("license_unlimited", MetricGauge, "Whether the expiration is never.", unlimited, "type", strings.ToLower(string(expiration.Type)))
+	}
+	return nil
+}
+
+func (e *MetricsExporter) collectBackup(m *metricSet) error {
+	status, err := e.s.BackupGetStatus()
+	if err != nil {
+		return err
+	}
+	m.add("backup_in_progress", MetricGauge, "Whether a backup is running.", boolValue(status.BackupInProgress))
+	m.add("backup_progress_percent", MetricGauge, "Progress of the running backup.", float64(status.Percents))
+	for _, lastStatus := range []struct {
+		name  string
+		value LastBackupStatus
+	}{
+		{"none", BackupStatusNone},
+		{"successful", BackupStatusSuccessful},
+		{"failed", BackupStatusFailed},
+	} {
+		m.add("backup_last_status", MetricGauge, "Status of the last backup run.", boolValue(status.LastBackupStatus == lastStatus.value), "status", lastStatus.name)
+	}
+	for _, backup := range []struct {
+		name string
+		info BackupInfo
+	}{
+		{"full", status.LastFull},
+		{"differential", status.LastDifferential},
+		{"mirror", status.LastMirror},
+	} {
+		if !backup.info.IsCreated {
+			continue
+		}
+		if created, err := backup.info.Created.Time(); err == nil {
+			m.add("backup_last_timestamp_seconds", MetricGauge, "Start time of the latest backup since unix epoch.", float64(created.Unix()), "type", backup.name)
+		}
+		m.add("backup_last_size_bytes", MetricGauge, "Compressed size of the latest backup.", float64(backup.info.Size.ByteCount()), "type", backup.name)
+	}
+	return nil
+}
+
+func (e *MetricsExporter) collectAlerts(m *metricSet) error {
+	alerts, err := e.s.ServerGetAlertList()
+	if err != nil {
+		return err
+	}
+	m.add("alerts", MetricGauge, "Number of active alerts.", float64(len(alerts)))
+	for _, alert := range alerts {
+		m.add("alert", MetricGauge, "Active alert.", 1, "name", string(alert.AlertName), "type", strings.ToLower(string(alert.AlertType)))
+	}
+	return nil
+}
+
+// WriteMetrics - Write metric families in Prometheus text format 0.0.4 or in OpenMetrics text format.
+//	w - output
+//	families - metrics to write
+//	openMetrics - use OpenMetrics format
+func WriteMetrics(w io.Writer, families MetricFamilyList, openMetrics bool) error {
+	b := bufio.NewWriter(w)
+	for _, family := range families {
+		name := MetricsNamespace + "_" + family.Name
+		sample := name
+		if family.Type == MetricCounter {
+			sample += "_total"
+			if !openMetrics {
+				name = sample
+			}
+		}
+		fmt.Fprintf(b, "# HELP %s %s\n", name, escapeMetricHelp(family.Help))
+		fmt.Fprintf(b, "# TYPE %s %s\n", name, family.Type)
+		for _, metric := range family.Metrics {
+			b.WriteString(sample)
+			if len(metric.Labels) > 0 {
+				labels := make([]string, 0, len(metric.Labels))
+				for _, label := range sortedKeys(metric.Labels) {
+					labels = append(labels, label+"=\""+escapeMetricLabel(metric.Labels[label])+"\"")
+				}
+				b.WriteString("{" + strings.Join(labels, ",") + "}")
+			}
+			b.WriteString(" " + formatMetricValue(metric.Value) + "\n")
+		}
+	}
+	if openMetrics {
+		b.WriteString("# EOF\n")
+	}
+	return b.Flush()
+}
+
+// parseCounter converts a counter sent as a string, e.g. "1 234", "1,234" or "12 %", to a number
+func parseCounter(value string) (float64, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', ',', '\u00a0', '\u202f', '%':
+			return -1
+		}
+		return r
+	}, value)
+	if cleaned == "" {
+		return 0, fmt.Errorf("invalid counter %q", value)
+	}
+	return strconv.ParseFloat(cleaned, 64)
+}
+
+// metricLabel returns protocol name without the prefix, e.g. smtp for protocolSmtp
+func (p Protocol) metricLabel() string {
+	return strings.ToLower(strings.TrimPrefix(string(p), "protocol"))
+}
+
+// metricValue is a counter sent as a string with the value of its label
+type metricValue struct {
+	label string
+	value string
+}
+
+// metricSet collects samples into families keeping the order of first occurrence
+type metricSet struct {
+	families MetricFamilyList
+}
+
+func (m *metricSet) add(name string, metricType MetricType, help string, value float64, labels ...string) {
+	metric := Metric{Value: value}
+	if len(labels) > 0 {
+		metric.Labels = map[string]string{}
+		for i := 0; i+1 < len(labels); i += 2 {
+			metric.Labels[labels[i]] = labels[i+1]
+		}
+	}
+	for i := range m.families {
+		if m.families[i].Name == name {
+			m.families[i].Metrics = append(m.families[i].Metrics, metric)
+			return
+		}
+	}
+	m.families = append(m.families, MetricFamily{Name: name, Type: metricType, Help: help, Metrics: MetricList{metric}})
+}
+
+// counter adds a counter sent as a string, values which are not numbers are skipped
+func (m *metricSet) counter(name string, help string, value string, labels ...string) {
+	if number, err := parseCounter(value); err == nil {
+		m.add(name, MetricCounter, help, number, labels...)
+	}
+}
+
+func boolValue(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatMetricValue(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var metricHelpReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+var metricLabelReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeMetricHelp(help string) string {
+	return metricHelpReplacer.Replace(help)
+}
+
+func escapeMetricLabel(value string) string {
+	return metricLabelReplacer.Replace(value)
+}
@@ -0,0 +1,90 @@
+package connect
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func metricsHandlers() map[string]fakeHandler {
+	return map[string]fakeHandler{
+		"Statistics.get": result(map[string]interface{}{"statistics": ServerStatistics{
+			Start:    1600000000,
+			Uptime:   Distance{Days: 1, Hours: 2, Minutes: 3},
+			Storage:  OccupiedStorage{Total: ByteValueWithUnits{Value: 2, Units: GigaBytes}, Occupied: ByteValueWithUnits{Value: 512, Units: MegaBytes}},
+			Received: MessageThroughput{Count: "1 234", Volume: ByteValueWithUnits{Value: 3, Units: KiloBytes}, Recipients: "1,500"},
+			Spam:     SpamStats{Checked: "10", Tagged: "n/a"},
+		}}),
+		"SystemHealth.get": result(map[string]interface{}{"data": SystemHealthData{Cpu: PercentHistogram{10, 42.5}, MemoryTotal: 1024}}),
+		"Queue.get": result(map[string]interface{}{"list": MessageInQueueList{}, "totalItems": 7,
+			"volume": ByteValueWithUnits{Value: 5, Units: MegaBytes}}),
+		"Server.getConnections": result(map[string]interface{}{"list": ConnectionList{{Proto: ProtocolSmtp}, {Proto: ProtocolSmtp}, {Proto: ProtocolImaps}}}),
+		"ProductRegistration.getFullStatus": result(map[string]interface{}{"status": RegistrationFullStatus{Expirations: LicenseExpireInfo{
+			{Type: License, IsUnlimited: true}, {Type: Subscription, RemainingDays: 30},
+		}}}),
+		"Backup.getStatus": result(map[string]interface{}{"status": BackupStatus{LastBackupStatus: BackupStatusFailed,
+			LastFull: BackupInfo{IsCreated: true, Created: "2020-09-13T12:26:40Z", Size: ByteValueWithUnits{Value: 1, Units: GigaBytes}}}}),
+	}
+}
+
+func TestMetricsExporter(t *testing.T) {
+	conn, _ := newFakeConnection(t, metricsHandlers())
+	server := httptest.NewServer(conn.NewMetricsExporter())
+	defer server.Close()
+	response, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := ioutil.ReadAll(response.Body)
+	_ = response.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(body)
+	for _, line := range []string{
+		"# TYPE kerio_connect_messages_total counter",
+		`kerio_connect_messages_total{direction="received"} 1234`,
+		`kerio_connect_message_recipients_total{direction="received"} 1500`,
+		`kerio_connect_messages_bytes_total{direction="received"} 3072`,
+		`kerio_connect_spam_messages_total{result="checked"} 10`,
+		"kerio_connect_uptime_seconds 93780",
+		"kerio_connect_storage_total_bytes 2.147483648e+09",
+		"kerio_connect_cpu_usage_percent 42.5",
+		"kerio_connect_queue_messages 7",
+		"kerio_connect_queue_bytes 5.24288e+06",
+		`kerio_connect_connections{protocol="smtp"} 2`,
+		`kerio_connect_connections{protocol="imaps"} 1`,
+		`kerio_connect_connections{protocol="pop3"} 0`,
+		`kerio_connect_license_unlimited{type="license"} 1`,
+		`kerio_connect_license_expiry_days{type="subscription"} 30`,
+		`kerio_connect_backup_last_status{status="failed"} 1`,
+		`kerio_connect_backup_last_timestamp_seconds{type="full"} 1.6e+09`,
+		`kerio_connect_scrape_success{source="alerts"} 0`,
+		"kerio_connect_up 0",
+	} {
+		if !strings.Contains(text, line+"\n") {
+			t.Errorf("missing %q in:\n%s", line, text)
+		}
+	}
+	if strings.Contains(text, `result="tagged"`) {
+		t.Error("counter which is not a number must be skipped")
+	}
+}
+
+func TestWriteMetricsOpenMetrics(t *testing.T) {
+	m := &metricSet{}
+	m.counter("messages", "Messages.", "5", "direction", "a\"b")
+	text := &strings.Builder{}
+	if err := WriteMetrics(text, m.families, true); err != nil {
+		t.Fatal(err)
+	}
+	expected := "# HELP kerio_connect_messages Messages.\n" +
+		"# TYPE kerio_connect_messages counter\n" +
+		"kerio_connect_messages_total{direction=\"a\\\"b\"} 5\n" +
+		"# EOF\n"
+	if text.String() != expected {
+		t.Errorf("unexpected output:\n%s", text)
+	}
+}
@@ -1,20 +1,18 @@
 package connect
 
-import "encoding/json"
-
 type MigrationStatusEnum string
 
 const (
-	migNotStarted            MigrationStatusEnum = "migNotStarted"            // Migration planed but not started
-	migCompressionStarted    MigrationStatusEnum = "migCompressionStarted"    // Migration started - packing of the mailbox and sending through network
-	migCompressionFinished   MigrationStatusEnum = "migCompressionFinished"   // Source server actions finished
-	migTransferStarted       MigrationStatusEnum = "migTransferStarted"       // Downloading packed mailbox
-	migTransferFinished      MigrationStatusEnum = "migTransferFinished"      // Download completed
-	migDecompressionStarted  MigrationStatusEnum = "migDecompressionStarted"  // Target server actions - unpacking of the mailbox
-	migDecompressionFinished MigrationStatusEnum = "migDecompressionFinished" // Mailbox successfully decompressed
-	migFinished              MigrationStatusEnum = "migFinished"              // Migration finished
-	migCanceled              MigrationStatusEnum = "migCanceled"              // Migration canceled by user
-	migError                 MigrationStatusEnum = "migError"                 // Migration failed - detailed error description can be found in logs
+	MigNotStarted            MigrationStatusEnum = "migNotStarted"            // Migration planed but not started
+	MigCompressionStarted    MigrationStatusEnum = "migCompressionStarted"    // Migration started - packing of the mailbox and sending through network
+	MigCompressionFinished   MigrationStatusEnum = "migCompressionFinished"   // Source server actions finished
+	MigTransferStarted       MigrationStatusEnum = "migTransferStarted"       // Downloading packed mailbox
+	MigTransferFinished      MigrationStatusEnum = "migTransferFinished"      // Download completed
+	MigDecompressionStarted  MigrationStatusEnum = "migDecompressionStarted"  // Target server actions - unpacking of the mailbox
+	MigDecompressionFinished MigrationStatusEnum = "migDecompressionFinished" // Mailbox successfully decompressed
+	MigFinished              MigrationStatusEnum = "migFinished"              // Migration finished
+	MigCanceled              MigrationStatusEnum = "migCanceled"              // Migration canceled by user
+	MigError                 MigrationStatusEnum = "migError"                 // Migration failed - detailed error description can be found in logs
 )
 
 // MigrationStatus - Status of the migration task and progress of migration in percents.
@@ -59,7 +57,7 @@ func (s *ServerConnection) MigrationCancel(taskIdList KIdList) (ErrorList, error
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -83,7 +81,7 @@ func (s *ServerConnection) MigrationGet(query SearchQuery) (MigrationTaskList, i
 			TotalItems int               `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -100,7 +98,7 @@ func (s *ServerConnection) MigrationGetCurrentHomeServer() (*HomeServer, error)
 			HomeServer HomeServer `json:"homeServer"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &homeServer)
+	err = s.unmarshal(data, &homeServer)
 	return &homeServer.Result.HomeServer, err
 }
 
@@ -119,7 +117,7 @@ func (s *ServerConnection) MigrationGetCurrentStatus() (*KId, *MigrationStatus,
 			Status MigrationStatus `json:"status"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &taskId)
+	err = s.unmarshal(data, &taskId)
 	return &taskId.Result.TaskId, &taskId.Result.Status, err
 }
 
@@ -140,7 +138,7 @@ func (s *ServerConnection) MigrationGetStatus(taskId KId) (*MigrationStatus, err
 			Status MigrationStatus `json:"status"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &status)
+	err = s.unmarshal(data, &status)
 	return &status.Result.Status, err
 }
 
@@ -157,7 +155,7 @@ func (s *ServerConnection) MigrationIsInProgress() (bool, error) {
 			IsInProgress bool `json:"isInProgress"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &isInProgress)
+	err = s.unmarshal(data, &isInProgress)
 	return isInProgress.Result.IsInProgress, err
 }
 
@@ -180,6 +178,6 @@ func (s *ServerConnection) MigrationStart(userIds KIdList) (ErrorList, CreateRes
 			Result CreateResultList `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, errors.Result.Result, err
 }
@@ -0,0 +1,430 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMigrationPollInterval = 10 * time.Second // pause between reads of task status
+	DefaultMigrationWaveSize     = 5                // users started together
+	DefaultMigrationRetries      = 2                // new attempts after failed migration
+)
+
+// MigrationItemState - State of one user in the orchestrated migration
+type MigrationItemState string
+
+const (
+	MigrationPending  MigrationItemState = "pending"  // waiting for its wave
+	MigrationRunning  MigrationItemState = "running"  // task was started on the server
+	MigrationDone     MigrationItemState = "done"     // mailbox was migrated
+	MigrationFailed   MigrationItemState = "failed"   // all attempts failed
+	MigrationCanceled MigrationItemState = "canceled" // canceled by MigrationOrchestrator.Cancel
+)
+
+// MigrationItem - Migration of one user
+type MigrationItem struct {
+	UserId   KId                 `json:"userId"`
+	State    MigrationItemState  `json:"state"`
+	Wave     int                 `json:"wave"`     // number of the last wave the user was started in, from 1
+	TaskId   KId                 `json:"taskId"`   // task of the last attempt
+	Attempts int                 `json:"attempts"` // count of started attempts
+	Status   MigrationStatusEnum `json:"status"`   // last status of the task
+	Progress int                 `json:"progress"` // progress of the task in percents
+	Error    string              `json:"error,omitempty"`
+}
+
+// MigrationItemList - Users of the orchestrated migration in order of waves
+type MigrationItemList []MigrationItem
+
+// MigrationOrchestratorOptions - Options of NewMigrationOrchestrator
+type MigrationOrchestratorOptions struct {
+	StateFile    string                   // file with progress, an interrupted run resumes from it; empty means no file
+	WaveSize     int                      // users started together; DefaultMigrationWaveSize if 0
+	MaxRetries   int                      // new attempts after failure; DefaultMigrationRetries if 0, negative means none
+	PollInterval time.Duration            // pause between reads of task status; DefaultMigrationPollInterval if 0
+	OnProgress   func(item MigrationItem) // called when state or progress of a user changes
+}
+
+// MigrationOrchestrator - Migrates users to the connected server in waves.
+// Pause, Resume and Cancel may be called from other goroutines while Run is running.
+type MigrationOrchestrator struct {
+	s        *ServerConnection
+	options  MigrationOrchestratorOptions
+	mu       sync.Mutex
+	items    MigrationItemList
+	wave     int
+	paused   bool
+	canceled bool
+	wake     chan struct{}
+}
+
+// NewMigrationOrchestrator - Create orchestrator of migration of users to the connected server.
+// If the state file exists, its progress is loaded and users not found in it are appended.
+//	userIds - users to migrate
+//	options - nil means defaults
+func (s *ServerConnection) NewMigrationOrchestrator(userIds KIdList, options *MigrationOrchestratorOptions) (*MigrationOrchestrator, error) {
+	o := &MigrationOrchestrator{s: s, wake: make(chan struct{}, 1)}
+	if options != nil {
+		o.options = *options
+	}
+	if o.options.WaveSize <= 0 {
+		o.options.WaveSize = DefaultMigrationWaveSize
+	}
+	if o.options.MaxRetries == 0 {
+		o.options.MaxRetries = DefaultMigrationRetries
+	}
+	if o.options.MaxRetries < 0 {
+		o.options.MaxRetries = 0
+	}
+	if o.options.PollInterval <= 0 {
+		o.options.PollInterval = DefaultMigrationPollInterval
+	}
+	if o.options.StateFile != "" {
+		data, err := ioutil.ReadFile(o.options.StateFile)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if err == nil {
+			if err = json.Unmarshal(data, &o.items); err != nil {
+				return nil, fmt.Errorf("%s: %v", o.options.StateFile, err)
+			}
+		}
+	}
+	known := map[KId]bool{}
+	for _, item := range o.items {
+		known[item.UserId] = true
+		if item.Wave > o.wave {
+			o.wave = item.Wave
+		}
+	}
+	for _, id := range userIds {
+		if !known[id] {
+			known[id] = true
+			o.items = append(o.items, MigrationItem{UserId: id, State: MigrationPending})
+		}
+	}
+	return o, nil
+}
+
+// Items - Return copy of the current progress
+func (o *MigrationOrchestrator) Items() MigrationItemList {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append(MigrationItemList{}, o.items...)
+}
+
+// Pause - Stop starting new waves, running tasks continue
+func (o *MigrationOrchestrator) Pause() {
+	o.mu.Lock()
+	o.paused = true
+	o.mu.Unlock()
+}
+
+// Resume - Continue starting waves after Pause
+func (o *MigrationOrchestrator) Resume() {
+	o.mu.Lock()
+	o.paused = false
+	o.mu.Unlock()
+	o.notify()
+}
+
+// Cancel - Cancel running tasks by MigrationCancel and stop Run, pending users are marked canceled
+func (o *MigrationOrchestrator) Cancel() {
+	o.mu.Lock()
+	o.canceled = true
+	o.mu.Unlock()
+	o.notify()
+}
+
+func (o *MigrationOrchestrator) notify() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run - Migrate users until all are done or failed, Cancel is called or ctx is done.
+// When ctx is done, tasks started on the server continue and the next run with the same state file follows them.
+// Return
+//	err - error of the server, ctx error or count of failed users
+func (o *MigrationOrchestrator) Run(ctx context.Context) error {
+	for {
+		o.mu.Lock()
+		paused, canceled := o.paused, o.canceled
+		o.mu.Unlock()
+		if canceled {
+			return o.cancel()
+		}
+		running, err := o.poll()
+		if err != nil {
+			return err
+		}
+		if running == 0 {
+			started, err := 0, error(nil)
+			if !paused {
+				if started, err = o.startWave(); err != nil {
+					return err
+				}
+			}
+			if started == 0 && !paused {
+				return o.finish()
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-o.wake:
+		case <-time.After(o.options.PollInterval):
+		}
+	}
+}
+
+// poll updates running items by status of their tasks
+func (o *MigrationOrchestrator) poll() (int, error) {
+	running := 0
+	for _, i := range o.indexes(MigrationRunning) {
+		item := o.item(i)
+		status, err := o.s.MigrationGetStatus(item.TaskId)
+		if err != nil {
+			return 0, err
+		}
+		changed := item.Status != status.MigrationStatus || item.Progress != status.ProgressInPercents
+		item.Status = status.MigrationStatus
+		item.Progress = status.ProgressInPercents
+		switch status.MigrationStatus {
+		case MigFinished:
+			item.State = MigrationDone
+			item.Error = ""
+		case MigError, MigCanceled:
+			item.Error = status.ErrorMessage
+			if item.Error == "" && status.MigrationStatus == MigCanceled {
+				item.Error = "migration canceled on the server"
+			} else if item.Error == "" {
+				item.Error = "migration failed"
+			}
+			o.retryOrFail(&item)
+		default:
+			running++
+		}
+		if changed || item.State != MigrationRunning {
+			if err = o.update(i, item); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return running, nil
+}
+
+// startWave starts next pending users
+func (o *MigrationOrchestrator) startWave() (int, error) {
+	pending := o.indexes(MigrationPending)
+	if len(pending) == 0 {
+		return 0, nil
+	}
+	if len(pending) > o.options.WaveSize {
+		pending = pending[:o.options.WaveSize]
+	}
+	ids := make(KIdList, len(pending))
+	for j, i := range pending {
+		ids[j] = o.item(i).UserId
+	}
+	errors, results, err := o.s.MigrationStart(ids)
+	if err != nil {
+		return 0, err
+	}
+	o.mu.Lock()
+	o.wave++
+	wave := o.wave
+	o.mu.Unlock()
+	started := 0
+	for j, i := range pending {
+		item := o.item(i)
+		item.Wave = wave
+		item.Attempts++
+		item.Progress = 0
+		item.Status = MigNotStarted
+		item.State = MigrationRunning
+		for _, result := range results {
+			if result.InputIndex == j {
+				item.TaskId = result.Id
+			}
+		}
+		for _, e := range errors {
+			if e.InputIndex == j {
+				item.Error = formatMessage(e.Message, e.MessageParameters.PositionalParameters)
+				o.retryOrFail(&item)
+			}
+		}
+		if item.State == MigrationRunning && item.TaskId == "" {
+			item.Error = "server returned no migration task"
+			o.retryOrFail(&item)
+		}
+		if item.State == MigrationRunning {
+			started++
+		}
+		if err = o.update(i, item); err != nil {
+			return 0, err
+		}
+	}
+	// users which could not be started are retried by the next wave
+	if started == 0 {
+		return len(o.indexes(MigrationPending)), nil
+	}
+	return started, nil
+}
+
+func (o *MigrationOrchestrator) retryOrFail(item *MigrationItem) {
+	if item.Attempts <= o.options.MaxRetries {
+		item.State = MigrationPending
+	} else {
+		item.State = MigrationFailed
+	}
+}
+
+// cancel cancels running tasks and marks unfinished users as canceled
+func (o *MigrationOrchestrator) cancel() error {
+	running := o.indexes(MigrationRunning)
+	var tasks KIdList
+	for _, i := range running {
+		tasks = append(tasks, o.item(i).TaskId)
+	}
+	if len(tasks) > 0 {
+		errors, err := o.s.MigrationCancel(tasks)
+		if err != nil {
+			return err
+		}
+		if len(errors) > 0 {
+			return fmt.Errorf("cannot cancel migration: %s", formatMessage(errors[0].Message, errors[0].MessageParameters.PositionalParameters))
+		}
+	}
+	for _, i := range append(running, o.indexes(MigrationPending)...) {
+		item := o.item(i)
+		item.State = MigrationCanceled
+		if err := o.update(i, item); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("migration canceled")
+}
+
+// finish reports failed users
+func (o *MigrationOrchestrator) finish() error {
+	if failed := len(o.indexes(MigrationFailed)); failed > 0 {
+		return fmt.Errorf("migration of %d of %d users failed", failed, len(o.Items()))
+	}
+	return nil
+}
+
+func (o *MigrationOrchestrator) indexes(state MigrationItemState) []int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var indexes []int
+	for i, item := range o.items {
+		if item.State == state {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+func (o *MigrationOrchestrator) item(i int) MigrationItem {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.items[i]
+}
+
+// update stores the item, saves the state file and reports the change
+func (o *MigrationOrchestrator) update(i int, item MigrationItem) error {
+	o.mu.Lock()
+	o.items[i] = item
+	data, err := json.MarshalIndent(o.items, "", "  ")
+	o.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if o.options.StateFile != "" {
+		if err = writeFileAtomic(o.options.StateFile, data); err != nil {
+			return err
+		}
+	}
+	if o.options.OnProgress != nil {
+		o.options.OnProgress(item)
+	}
+	return nil
+}
+
+// formatMessage replaces placeholders %1, %2, ... of the server message by its parameters
+func formatMessage(message string, parameters StringList) string {
+	for i := len(parameters); i > 0; i-- {
+		message = strings.Replace(message, "%"+strconv.Itoa(i), parameters[i-1], -1)
+	}
+	return message
+}
+
+// PlanHomeServerRebalance - Select users to migrate to the target server, so that it has its share of users.
+// Users are taken from the servers with the most users above the average.
+//	users - users of the distributed domain with their home servers
+//	servers - home servers of the cluster
+//	target - server the users will be migrated to, i.e. the connected server
+// Return
+//	userIds - users to migrate
+func PlanHomeServerRebalance(users UserList, servers HomeServerList, target KId) KIdList {
+	if len(servers) == 0 {
+		return KIdList{}
+	}
+	byServer := map[KId]UserList{}
+	for _, user := range users {
+		byServer[user.HomeServer.Id] = append(byServer[user.HomeServer.Id], user)
+	}
+	average := (len(users) + len(servers) - 1) / len(servers)
+	missing := average - len(byServer[target])
+	ids := KIdList{}
+	for missing > 0 {
+		// take one user from the most loaded server above the average
+		var source KId
+		for _, server := range servers {
+			if server.Id != target && len(byServer[server.Id]) > average &&
+				(source == "" || len(byServer[server.Id]) > len(byServer[source])) {
+				source = server.Id
+			}
+		}
+		if source == "" {
+			break
+		}
+		list := byServer[source]
+		sort.SliceStable(list, func(i, j int) bool { return list[i].LoginName < list[j].LoginName })
+		ids = append(ids, list[len(list)-1].Id)
+		byServer[source] = list[:len(list)-1]
+		missing--
+	}
+	return ids
+}
+
+// PlanRebalance - Read users of the domain and home servers and plan migration to the connected server.
+//	domainId - distributed domain
+// Return
+//	userIds - users to migrate, see PlanHomeServerRebalance
+func (s *ServerConnection) PlanRebalance(domainId KId) (KIdList, error) {
+	users, _, err := s.UsersGet(SearchQuery{}, domainId)
+	if err != nil {
+		return nil, err
+	}
+	servers, err := s.DistributedDomainGetHomeServerList()
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.MigrationGetCurrentHomeServer()
+	if err != nil {
+		return nil, err
+	}
+	return PlanHomeServerRebalance(users, servers, current.Id), nil
+}
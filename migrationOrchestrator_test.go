@@ -0,0 +1,204 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeMigration starts a task per user, every task reports progress once and then finishes.
+// Users in fail have their first attempt failed.
+type fakeMigration struct {
+	mu       sync.Mutex
+	tasks    map[KId]int // task id to count of status reads
+	users    map[KId]KId // task id to user id
+	fail     map[KId]bool
+	started  []KIdList
+	canceled KIdList
+}
+
+func newFakeMigration(fail ...KId) *fakeMigration {
+	f := &fakeMigration{tasks: map[KId]int{}, users: map[KId]KId{}, fail: map[KId]bool{}}
+	for _, id := range fail {
+		f.fail[id] = true
+	}
+	return f
+}
+
+func (f *fakeMigration) handlers() map[string]fakeHandler {
+	return map[string]fakeHandler{
+		"Migration.start": func(params json.RawMessage) interface{} {
+			request := struct {
+				UserIds KIdList `json:"userIds"`
+			}{}
+			json.Unmarshal(params, &request)
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.started = append(f.started, request.UserIds)
+			results := CreateResultList{}
+			for i, id := range request.UserIds {
+				task := KId(fmt.Sprintf("task%d", len(f.users)+1))
+				f.users[task] = id
+				results = append(results, CreateResult{InputIndex: i, Id: task})
+			}
+			return map[string]interface{}{"errors": ErrorList{}, "result": results}
+		},
+		"Migration.getStatus": func(params json.RawMessage) interface{} {
+			request := struct {
+				TaskId KId `json:"taskId"`
+			}{}
+			json.Unmarshal(params, &request)
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.tasks[request.TaskId]++
+			status := MigrationStatus{MigrationStatus: MigTransferStarted, ProgressInPercents: 50}
+			if user := f.users[request.TaskId]; f.tasks[request.TaskId] > 1 && f.fail[user] {
+				delete(f.fail, user)
+				status = MigrationStatus{MigrationStatus: MigError, ErrorMessage: "disk full"}
+			} else if f.tasks[request.TaskId] > 1 {
+				status = MigrationStatus{MigrationStatus: MigFinished, ProgressInPercents: 100}
+			}
+			return map[string]interface{}{"status": status}
+		},
+		"Migration.cancel": func(params json.RawMessage) interface{} {
+			request := struct {
+				TaskIdList KIdList `json:"taskIdList"`
+			}{}
+			json.Unmarshal(params, &request)
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.canceled = append(f.canceled, request.TaskIdList...)
+			return map[string]interface{}{"errors": ErrorList{}}
+		},
+	}
+}
+
+func TestMigrationOrchestrator(t *testing.T) {
+	fake := newFakeMigration("u3")
+	conn, _ := newFakeConnection(t, fake.handlers())
+	stateFile := filepath.Join(t.TempDir(), "migration.json")
+	var progress []string
+	o, err := conn.NewMigrationOrchestrator(KIdList{"u1", "u2", "u3", "u4", "u2"}, &MigrationOrchestratorOptions{
+		StateFile:    stateFile,
+		WaveSize:     2,
+		MaxRetries:   1,
+		PollInterval: time.Millisecond,
+		OnProgress:   func(item MigrationItem) { progress = append(progress, string(item.UserId)+":"+string(item.State)) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = o.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(fake.started) != "[[u1 u2] [u3 u4] [u3]]" {
+		t.Errorf("unexpected waves %v", fake.started)
+	}
+	items := o.Items()
+	if len(items) != 4 || items[2].Attempts != 2 || items[2].Wave != 3 || items[2].State != MigrationDone || items[2].Error != "" {
+		t.Errorf("unexpected items %+v", items)
+	}
+	if !strings.Contains(strings.Join(progress, ","), "u3:pending") {
+		t.Errorf("unexpected progress %v", progress)
+	}
+	data, err := ioutil.ReadFile(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved MigrationItemList
+	if err = json.Unmarshal(data, &saved); err != nil || len(saved) != 4 || saved[3].State != MigrationDone {
+		t.Errorf("unexpected state file %s", data)
+	}
+}
+
+func TestMigrationOrchestratorResume(t *testing.T) {
+	fake := newFakeMigration("u2")
+	fake.users["task1"] = "u1"
+	conn, _ := newFakeConnection(t, fake.handlers())
+	stateFile := filepath.Join(t.TempDir(), "migration.json")
+	saved := MigrationItemList{
+		{UserId: "u1", State: MigrationRunning, Wave: 1, TaskId: "task1", Attempts: 1},
+		{UserId: "u2", State: MigrationPending, Wave: 1, Attempts: 1, Error: "disk full"},
+	}
+	data, _ := json.Marshal(saved)
+	if err := ioutil.WriteFile(stateFile, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	o, err := conn.NewMigrationOrchestrator(KIdList{"u1", "u2"}, &MigrationOrchestratorOptions{
+		StateFile:    stateFile,
+		MaxRetries:   -1,
+		PollInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = o.Run(context.Background())
+	if err == nil || err.Error() != "migration of 1 of 2 users failed" {
+		t.Errorf("unexpected error %v", err)
+	}
+	items := o.Items()
+	if items[0].State != MigrationDone || items[1].State != MigrationFailed || items[1].Wave != 2 || items[1].Error != "disk full" {
+		t.Errorf("unexpected items %+v", items)
+	}
+}
+
+func TestMigrationOrchestratorCancel(t *testing.T) {
+	fake := newFakeMigration()
+	conn, _ := newFakeConnection(t, fake.handlers())
+	o, err := conn.NewMigrationOrchestrator(KIdList{"u1", "u2"}, &MigrationOrchestratorOptions{WaveSize: 1, PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.options.OnProgress = func(item MigrationItem) {
+		if item.State == MigrationRunning {
+			o.Cancel()
+		}
+	}
+	if err = o.Run(context.Background()); err == nil || err.Error() != "migration canceled" {
+		t.Errorf("unexpected error %v", err)
+	}
+	items := o.Items()
+	if fmt.Sprint(fake.canceled) != "[task1]" || items[0].State != MigrationCanceled || items[1].State != MigrationCanceled {
+		t.Errorf("unexpected cancel %v %+v", fake.canceled, items)
+	}
+}
+
+func TestMigrationOrchestratorPause(t *testing.T) {
+	fake := newFakeMigration()
+	conn, _ := newFakeConnection(t, fake.handlers())
+	o, err := conn.NewMigrationOrchestrator(KIdList{"u1"}, &MigrationOrchestratorOptions{PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.Pause()
+	done := make(chan error)
+	go func() { done <- o.Run(context.Background()) }()
+	time.Sleep(20 * time.Millisecond)
+	if items := o.Items(); items[0].State != MigrationPending {
+		t.Errorf("paused migration started %+v", items)
+	}
+	o.Resume()
+	if err = <-done; err != nil || o.Items()[0].State != MigrationDone {
+		t.Errorf("unexpected result %v %+v", err, o.Items())
+	}
+}
+
+func TestPlanHomeServerRebalance(t *testing.T) {
+	servers := HomeServerList{{Id: "a"}, {Id: "b"}, {Id: "c"}}
+	var users UserList
+	for i, server := range []KId{"a", "a", "a", "a", "a", "b", "b", "b", "c"} {
+		users = append(users, User{Id: KId(fmt.Sprintf("u%d", i)), LoginName: fmt.Sprintf("user%d", i), HomeServer: HomeServer{Id: server}})
+	}
+	if ids := PlanHomeServerRebalance(users, servers, "c"); fmt.Sprint(ids) != "[u4 u3]" {
+		t.Errorf("unexpected plan %v", ids)
+	}
+	if ids := PlanHomeServerRebalance(users, servers, "a"); len(ids) != 0 {
+		t.Errorf("unexpected plan %v", ids)
+	}
+}
@@ -0,0 +1,483 @@
+package connect
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/mail"
+	"sort"
+	"strings"
+)
+
+// MlTrustee - Archive reader of ML. Used only in local member files and sync reports,
+// on the server trustees are stored in ArchiveSettings.ArchiveReaderList.
+const MlTrustee MlMembership = "Trustee"
+
+// MlMemberRecord - ML member, moderator or trustee as stored in a local CSV or JSON file
+type MlMemberRecord struct {
+	EmailAddress string       `json:"emailAddress"` // email address, login name of user or group name in square brackets (trustees only)
+	FullName     string       `json:"fullName"`     // full name of user or associated email
+	Kind         MlMembership `json:"kind"`         // Member, Moderator or Trustee; empty means Member
+}
+
+// MlMemberRecordList - List of ML member records
+type MlMemberRecordList []MlMemberRecord
+
+// MlSyncOptions - Scope and safety limits of mailing list synchronization
+type MlSyncOptions struct {
+	Kinds            []MlMembership // membership kinds to converge; empty means Member and Moderator, trustees are touched only if MlTrustee is listed
+	MaxRemovePercent int            // refuse to remove more than given percent of current entries; 0 means DefaultMaxRemovePercent, 100 disables the limit
+	DryRun           bool           // compute and report the delta without changing the ML
+}
+
+// MlSyncReport - Result of mailing list synchronization
+type MlSyncReport struct {
+	MlId       KId                `json:"mlId"`       // global identification of ML
+	MlName     string             `json:"mlName"`     // ML address
+	Kept       int                `json:"kept"`       // number of entries which stay unchanged
+	Added      MlMemberRecordList `json:"added"`      // added members, moderators and trustees
+	Removed    MlMemberRecordList `json:"removed"`    // removed members, moderators and trustees
+	Duplicates StringList         `json:"duplicates"` // desired entries listed more than once
+	Conflicts  StringList         `json:"conflicts"`  // desired entries listed both as member and moderator
+	Invalid    StringList         `json:"invalid"`    // desired entries which are not valid addresses or unknown users/groups
+	Applied    bool               `json:"applied"`    // delta was sent to the server
+	Errors     ErrorList          `json:"errors"`     // errors returned by the server for particular entries
+}
+
+// IsChanged returns true if the synchronization adds or removes any entry
+func (r *MlSyncReport) IsChanged() bool {
+	return len(r.Added) > 0 || len(r.Removed) > 0
+}
+
+var mlCsvHeader = []string{"Email", "FullName", "Kind"}
+
+// ReadMlMembersCsv - Parse ML members from CSV in format 'Email, FullName[, Kind]'.
+// The header line is optional, the format is compatible with files exported by Kerio Connect.
+func ReadMlMembersCsv(r io.Reader) (MlMemberRecordList, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string]int{"email": 0, "fullname": 1, "kind": 2}
+	if len(rows) > 0 && isMlCsvHeader(rows[0]) {
+		columns = map[string]int{}
+		for i, name := range rows[0] {
+			columns[strings.ToLower(strings.TrimSpace(name))] = i
+		}
+		if _, ok := columns["emailaddress"]; ok {
+			columns["email"] = columns["emailaddress"]
+		}
+		rows = rows[1:]
+	}
+	cell := func(row []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+	records := make(MlMemberRecordList, 0, len(rows))
+	for _, row := range rows {
+		record := MlMemberRecord{
+			EmailAddress: cell(row, "email"),
+			FullName:     cell(row, "fullname"),
+			Kind:         MlMembership(cell(row, "kind")),
+		}
+		if record.EmailAddress == "" {
+			continue
+		}
+		if record.Kind, err = parseMlKind(string(record.Kind)); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// WriteMlMembersCsv - Write ML members as CSV with header 'Email,FullName,Kind'
+func WriteMlMembersCsv(w io.Writer, records MlMemberRecordList) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(mlCsvHeader); err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := writer.Write([]string{record.EmailAddress, record.FullName, string(record.Kind)}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadMlMembersJson - Parse ML members from JSON array of MlMemberRecord
+func ReadMlMembersJson(r io.Reader) (MlMemberRecordList, error) {
+	var records MlMemberRecordList
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, err
+	}
+	var err error
+	for i := range records {
+		if records[i].Kind, err = parseMlKind(string(records[i].Kind)); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+// WriteMlMembersJson - Write ML members as indented JSON array
+func WriteMlMembersJson(w io.Writer, records MlMemberRecordList) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+// ExportMlMembers - Obtain members, moderators and trustees of the mailing list as local records.
+// Internal users are exported with their primary email address.
+//	mlId - unique ML identifier
+// Return
+//	records - ML members, moderators and trustees
+func (s *ServerConnection) ExportMlMembers(mlId KId) (MlMemberRecordList, error) {
+	ml, err := s.findMailingList(mlId)
+	if err != nil {
+		return nil, err
+	}
+	indexes, err := s.newUserIndexList()
+	if err != nil {
+		return nil, err
+	}
+	members, _, err := s.MailingListsGetMlUserList(SearchQuery{}, ml.Id)
+	if err != nil {
+		return nil, err
+	}
+	records := make(MlMemberRecordList, 0, len(members)+len(ml.Archive.ArchiveReaderList))
+	for _, member := range members {
+		records = append(records, mlMemberRecord(indexes, member))
+	}
+	for _, trustee := range ml.Archive.ArchiveReaderList {
+		records = append(records, mlTrusteeRecord(indexes, trustee))
+	}
+	return records, nil
+}
+
+// SyncMailingList - Converge members, moderators and optionally trustees of the mailing list to the desired records.
+// Addresses of internal users are stored as users, other addresses as external emails.
+// A local address which belongs to no user, group, alias or ML is reported as invalid.
+//	mlId - unique ML identifier
+//	desired - desired members, moderators and trustees
+//	options - scope and safety limits, nil means defaults
+// Return
+//	report - computed delta and result of applying it
+func (s *ServerConnection) SyncMailingList(mlId KId, desired MlMemberRecordList, options *MlSyncOptions) (*MlSyncReport, error) {
+	if options == nil {
+		options = &MlSyncOptions{}
+	}
+	kinds := map[MlMembership]bool{Member: true, Moderator: true}
+	if len(options.Kinds) > 0 {
+		kinds = map[MlMembership]bool{}
+		for _, kind := range options.Kinds {
+			kinds[kind] = true
+		}
+	}
+	limit := options.MaxRemovePercent
+	if limit == 0 {
+		limit = DefaultMaxRemovePercent
+	}
+	ml, err := s.findMailingList(mlId)
+	if err != nil {
+		return nil, err
+	}
+	indexes, err := s.newUserIndexList()
+	if err != nil {
+		return nil, err
+	}
+	report := &MlSyncReport{MlId: ml.Id, MlName: ml.Name}
+	if index := indexes.forDomain(ml.DomainId); index != nil {
+		report.MlName = ml.Name + "@" + index.domain.Name
+	}
+	var targets TrusteeTargetList
+	if kinds[MlTrustee] {
+		if targets, _, err = s.MailingListsGetTrusteeTargetList(SearchQuery{}, ml.DomainId); err != nil {
+			return nil, err
+		}
+	}
+	wantedMembers := map[string]UserOrEmail{}
+	wantedKinds := map[string]MlMembership{} // kind of each member, moderator or external address
+	wantedTrustees := map[string]Trustee{}
+	for _, record := range desired {
+		kind := record.Kind
+		if kind == "" {
+			kind = Member
+		}
+		if !kinds[kind] {
+			continue
+		}
+		if kind == MlTrustee {
+			trustee, ok := resolveTrustee(indexes, targets, ml.DomainId, record.EmailAddress)
+			if !ok {
+				report.Invalid = append(report.Invalid, record.EmailAddress)
+				continue
+			}
+			if _, ok := wantedTrustees[string(trustee.ReaderId)]; ok {
+				report.Duplicates = append(report.Duplicates, record.EmailAddress)
+			}
+			wantedTrustees[string(trustee.ReaderId)] = trustee
+			continue
+		}
+		member, ok, err := s.resolveMlMember(indexes, ml.DomainId, record)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			report.Invalid = append(report.Invalid, record.EmailAddress)
+			continue
+		}
+		identity := mlMemberKey(member)
+		if other, ok := wantedKinds[identity]; ok && other != kind {
+			report.Conflicts = append(report.Conflicts, record.EmailAddress)
+		}
+		wantedKinds[identity] = kind
+		member.Kind = kind
+		key := mlMemberKey(member)
+		if _, ok := wantedMembers[key]; ok {
+			report.Duplicates = append(report.Duplicates, record.EmailAddress)
+		}
+		wantedMembers[key] = member
+	}
+	current, _, err := s.MailingListsGetMlUserList(SearchQuery{}, ml.Id)
+	if err != nil {
+		return nil, err
+	}
+	managed := 0
+	var toRemove UserOrEmailList
+	existing := map[string]bool{}
+	for _, member := range current {
+		if !kinds[member.Kind] {
+			continue
+		}
+		managed++
+		key := mlMemberKey(member)
+		existing[key] = true
+		if _, ok := wantedMembers[key]; ok {
+			report.Kept++
+			continue
+		}
+		toRemove = append(toRemove, member)
+		report.Removed = append(report.Removed, mlMemberRecord(indexes, member))
+	}
+	var toAdd UserOrEmailList
+	for key, member := range wantedMembers {
+		if !existing[key] {
+			toAdd = append(toAdd, member)
+			report.Added = append(report.Added, mlMemberRecord(indexes, member))
+		}
+	}
+	trustees := ml.Archive.ArchiveReaderList
+	if kinds[MlTrustee] {
+		managed += len(ml.Archive.ArchiveReaderList)
+		trustees = TrusteeList{}
+		for _, trustee := range ml.Archive.ArchiveReaderList {
+			if _, ok := wantedTrustees[string(trustee.ReaderId)]; ok {
+				report.Kept++
+				trustees = append(trustees, trustee)
+				delete(wantedTrustees, string(trustee.ReaderId))
+				continue
+			}
+			report.Removed = append(report.Removed, mlTrusteeRecord(indexes, trustee))
+		}
+		for _, trustee := range wantedTrustees {
+			trustees = append(trustees, trustee)
+			report.Added = append(report.Added, mlTrusteeRecord(indexes, trustee))
+		}
+	}
+	report.Added.sort()
+	report.Removed.sort()
+	sort.Strings(report.Duplicates)
+	sort.Strings(report.Conflicts)
+	sort.Strings(report.Invalid)
+	if len(report.Removed)*100 > managed*limit {
+		return report, fmt.Errorf("mailing list %s: refusing to remove %d of %d entries (limit is %d%%)", report.MlName, len(report.Removed), managed, limit)
+	}
+	if options.DryRun || !report.IsChanged() {
+		return report, nil
+	}
+	if len(toAdd) > 0 {
+		errors, err := s.MailingListsAddMlUserList(toAdd, ml.Id)
+		if err != nil {
+			return report, err
+		}
+		report.Errors = append(report.Errors, errors...)
+	}
+	if len(toRemove) > 0 {
+		errors, err := s.MailingListsRemoveMlUserList(toRemove, ml.Id)
+		if err != nil {
+			return report, err
+		}
+		report.Errors = append(report.Errors, errors...)
+	}
+	if len(trustees) != len(ml.Archive.ArchiveReaderList) || len(wantedTrustees) > 0 {
+		pattern := *ml
+		pattern.Archive.ArchiveReaderList = trustees
+		errors, err := s.MailingListsSet(KIdList{ml.Id}, pattern)
+		if err != nil {
+			return report, err
+		}
+		report.Errors = append(report.Errors, errors...)
+	}
+	report.Applied = true
+	return report, nil
+}
+
+// resolveMlMember converts the record to a user (for addresses of internal users) or an external email.
+// Unknown user or invalid address is not an error, ok is false; error means the server could not be asked.
+func (s *ServerConnection) resolveMlMember(indexes userIndexList, domainId KId, record MlMemberRecord) (UserOrEmail, bool, error) {
+	identifier := strings.TrimSpace(record.EmailAddress)
+	if !strings.Contains(identifier, "@") {
+		if index := indexes.forDomain(domainId); index != nil {
+			if user, ok := index.lookup(identifier); ok {
+				return UserOrEmail{HasId: true, UserId: user.Id, FullName: user.FullName}, true, nil
+			}
+		}
+		return UserOrEmail{}, false, nil
+	}
+	address, err := mail.ParseAddress(identifier)
+	if err != nil {
+		return UserOrEmail{}, false, nil
+	}
+	if index := indexes.forAddress(address.Address); index != nil {
+		user, ok, err := s.resolveUser(index, address.Address)
+		if err != nil {
+			return UserOrEmail{}, false, err
+		}
+		if ok {
+			return UserOrEmail{HasId: true, UserId: user.Id, FullName: user.FullName}, true, nil
+		}
+		// local address which is not a user has to belong to a group, alias or another ML, otherwise it is a typo
+		local, _ := splitAddress(address.Address)
+		entities, err := s.ServerFindEntityByEmail(StringList{local}, EntityDetail{Kind: EntityUser}, index.domain.Id)
+		if err != nil {
+			return UserOrEmail{}, false, err
+		}
+		exists := false
+		for _, entity := range entities {
+			exists = exists || !entity.IsPattern
+		}
+		if !exists {
+			return UserOrEmail{}, false, nil
+		}
+	}
+	fullName := record.FullName
+	if fullName == "" {
+		fullName = address.Name
+	}
+	return UserOrEmail{EmailAddress: strings.ToLower(address.Address), FullName: fullName}, true, nil
+}
+
+// resolveTrustee finds the user or group which may read the ML archive
+func resolveTrustee(indexes userIndexList, targets TrusteeTargetList, domainId KId, identifier string) (Trustee, bool) {
+	identifier = strings.TrimSpace(identifier)
+	var userId KId
+	if index := indexes.forAddress(identifier); index != nil {
+		if user, ok := index.lookup(identifier); ok {
+			userId = user.Id
+		}
+	} else if index := indexes.forDomain(domainId); index != nil {
+		if user, ok := index.lookup(identifier); ok {
+			userId = user.Id
+		}
+	}
+	for _, target := range targets {
+		if target.Id == userId || strings.EqualFold(target.Name, identifier) {
+			return Trustee{
+				Kind:          target.Type,
+				ReaderId:      target.Id,
+				DisplayString: target.Name,
+				IsEnabled:     target.IsEnabled,
+				ItemSource:    target.ItemSource,
+			}, true
+		}
+	}
+	return Trustee{}, false
+}
+
+// mlMemberRecord converts the ML member to a local record
+func mlMemberRecord(indexes userIndexList, member UserOrEmail) MlMemberRecord {
+	record := MlMemberRecord{EmailAddress: member.EmailAddress, FullName: member.FullName, Kind: member.Kind}
+	if member.HasId {
+		record.EmailAddress = string(member.UserId)
+		if index, user, ok := indexes.user(member.UserId); ok {
+			record.EmailAddress = index.primaryAddress(user)
+			record.FullName = user.FullName
+		}
+	}
+	return record
+}
+
+// mlTrusteeRecord converts the ML archive reader to a local record
+func mlTrusteeRecord(indexes userIndexList, trustee Trustee) MlMemberRecord {
+	record := MlMemberRecord{EmailAddress: trustee.DisplayString, Kind: MlTrustee}
+	if trustee.Kind == TrusteeUser {
+		if index, user, ok := indexes.user(trustee.ReaderId); ok {
+			record.EmailAddress = index.primaryAddress(user)
+			record.FullName = user.FullName
+		}
+	}
+	return record
+}
+
+// mlMemberKey identifies the ML member regardless of its full name
+func mlMemberKey(member UserOrEmail) string {
+	if member.HasId {
+		return string(member.Kind) + "|" + string(member.UserId)
+	}
+	return string(member.Kind) + "|" + strings.ToLower(member.EmailAddress)
+}
+
+// findMailingList looks the mailing list up by ID in all domains
+func (s *ServerConnection) findMailingList(mlId KId) (*Ml, error) {
+	domains, _, err := s.DomainsGet(SearchQuery{})
+	if err != nil {
+		return nil, err
+	}
+	for _, domain := range domains {
+		list, _, err := s.MailingListsGet(SearchQuery{}, domain.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, ml := range list {
+			if ml.Id == mlId {
+				return &ml, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("mailing list %q not found", mlId)
+}
+
+func (l MlMemberRecordList) sort() {
+	sort.Slice(l, func(i, j int) bool {
+		if l[i].Kind != l[j].Kind {
+			return l[i].Kind < l[j].Kind
+		}
+		return l[i].EmailAddress < l[j].EmailAddress
+	})
+}
+
+func isMlCsvHeader(row []string) bool {
+	name := strings.ToLower(strings.TrimSpace(row[0]))
+	return name == "email" || name == "emailaddress"
+}
+
+func parseMlKind(kind string) (MlMembership, error) {
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "", "member":
+		return Member, nil
+	case "moderator":
+		return Moderator, nil
+	case "trustee":
+		return MlTrustee, nil
+	}
+	return "", fmt.Errorf("unknown mailing list membership %q", kind)
+}
@@ -0,0 +1,144 @@
+package connect
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMlMembersCsvRoundTrip(t *testing.T) {
+	records, err := ReadMlMembersCsv(strings.NewReader("jdoe@example.com, John Doe\n\"x@y.org\",\"Doe, Jane\",moderator\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := MlMemberRecordList{
+		{EmailAddress: "jdoe@example.com", FullName: "John Doe", Kind: Member},
+		{EmailAddress: "x@y.org", FullName: "Doe, Jane", Kind: Moderator},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Fatalf("unexpected records: %+v", records)
+	}
+	buffer := &bytes.Buffer{}
+	if err = WriteMlMembersCsv(buffer, records); err != nil {
+		t.Fatal(err)
+	}
+	again, err := ReadMlMembersCsv(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, expected) {
+		t.Errorf("round trip changed records: %+v", again)
+	}
+	if _, err = ReadMlMembersCsv(strings.NewReader("a@b.c,A,owner\n")); err == nil {
+		t.Error("unknown kind accepted")
+	}
+}
+
+func TestMlMembersJson(t *testing.T) {
+	records, err := ReadMlMembersJson(strings.NewReader(`[{"emailAddress": "a@b.c", "kind": "member"}, {"emailAddress": "[Archive]", "kind": "TRUSTEE"}, {"emailAddress": "d@e.f"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[0].Kind != Member || records[1].Kind != MlTrustee || records[2].Kind != Member {
+		t.Errorf("unexpected records: %+v", records)
+	}
+	if _, err = ReadMlMembersJson(strings.NewReader(`[{"emailAddress": "a@b.c", "kind": "owner"}]`)); err == nil {
+		t.Error("unknown kind accepted")
+	}
+}
+
+func TestSyncMailingList(t *testing.T) {
+	var added, removed UserOrEmailList
+	members := func(params json.RawMessage, list *UserOrEmailList) interface{} {
+		p := struct {
+			Members UserOrEmailList `json:"members"`
+		}{}
+		_ = json.Unmarshal(params, &p)
+		*list = append(*list, p.Members...)
+		return map[string]interface{}{"errors": ErrorList{}}
+	}
+	conn, _ := newFakeConnection(t, map[string]fakeHandler{
+		"Domains.get":      result(map[string]interface{}{"list": DomainList{{Id: "d1", Name: "example.com"}}}),
+		"MailingLists.get": result(map[string]interface{}{"list": MlList{{Id: "ml1", DomainId: "d1", Name: "staff"}}}),
+		"Users.get": result(map[string]interface{}{"list": UserList{
+			{Id: "u1", LoginName: "alice", FullName: "Alice"},
+			{Id: "u2", LoginName: "bob", FullName: "Bob"},
+		}}),
+		"Server.findEntityByEmail": func(params json.RawMessage) interface{} {
+			p := struct {
+				Addresses StringList `json:"addresses"`
+			}{}
+			_ = json.Unmarshal(params, &p)
+			if p.Addresses[0] == "team" {
+				return map[string]interface{}{"entities": EntityDuplicateList{{Kind: EntityGroup, Name: "team"}}}
+			}
+			return map[string]interface{}{"entities": EntityDuplicateList{}}
+		},
+		"MailingLists.getMlUserList": result(map[string]interface{}{"list": UserOrEmailList{
+			{HasId: true, UserId: "u1", Kind: Member},
+			{EmailAddress: "team@example.com", Kind: Member},
+			{EmailAddress: "old@partner.org", Kind: Member},
+			{EmailAddress: "ext@partner.org", Kind: Member},
+			{EmailAddress: "keep@partner.org", Kind: Member},
+			{EmailAddress: "more@partner.org", Kind: Member},
+			{HasId: true, UserId: "u2", Kind: Moderator},
+		}}),
+		"MailingLists.addMlUserList":    func(params json.RawMessage) interface{} { return members(params, &added) },
+		"MailingLists.removeMlUserList": func(params json.RawMessage) interface{} { return members(params, &removed) },
+	})
+	desired := MlMemberRecordList{
+		{EmailAddress: "alice@example.com"},
+		{EmailAddress: "alcie@example.com"},
+		{EmailAddress: "team@example.com"},
+		{EmailAddress: "EXT@partner.org"},
+		{EmailAddress: "keep@partner.org"},
+		{EmailAddress: "more@partner.org"},
+		{EmailAddress: "bob", Kind: Member},
+		{EmailAddress: "bob", Kind: Moderator},
+		{EmailAddress: "ext@partner.org"},
+		{EmailAddress: "not an address"},
+	}
+	report, err := conn.SyncMailingList("ml1", desired, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Kept != 6 || len(report.Added) != 1 || report.Added[0].EmailAddress != "bob@example.com" {
+		t.Errorf("unexpected added: %+v", report)
+	}
+	if len(report.Removed) != 1 || report.Removed[0].EmailAddress != "old@partner.org" {
+		t.Errorf("unexpected removed: %+v", report.Removed)
+	}
+	if !reflect.DeepEqual(report.Duplicates, StringList{"ext@partner.org"}) || !reflect.DeepEqual(report.Invalid, StringList{"alcie@example.com", "not an address"}) {
+		t.Errorf("unexpected duplicates %v or invalid %v", report.Duplicates, report.Invalid)
+	}
+	if !reflect.DeepEqual(report.Conflicts, StringList{"bob"}) {
+		t.Errorf("unexpected conflicts %v", report.Conflicts)
+	}
+	if len(added) != 1 || !added[0].HasId || added[0].UserId != "u2" || len(removed) != 1 {
+		t.Errorf("delta not applied: added %+v, removed %+v", added, removed)
+	}
+}
+
+func TestSyncMailingListLookupError(t *testing.T) {
+	conn, fake := newFakeConnection(t, map[string]fakeHandler{
+		"Domains.get":      result(map[string]interface{}{"list": DomainList{{Id: "d1", Name: "example.com"}}}),
+		"MailingLists.get": result(map[string]interface{}{"list": MlList{{Id: "ml1", DomainId: "d1", Name: "staff"}}}),
+		"Users.get":        result(map[string]interface{}{"list": UserList{{Id: "u1", LoginName: "alice"}}}),
+		"MailingLists.getMlUserList": result(map[string]interface{}{"list": UserOrEmailList{
+			{HasId: true, UserId: "u1", Kind: Member},
+			{EmailAddress: "sales@example.com", Kind: Member},
+		}}),
+		"MailingLists.removeMlUserList": result(map[string]interface{}{"errors": ErrorList{}}),
+	})
+	// Server.findEntityByEmail is not handled, the lookup of the alias fails
+	desired := MlMemberRecordList{{EmailAddress: "alice@example.com"}, {EmailAddress: "sales@example.com"}}
+	report, err := conn.SyncMailingList("ml1", desired, &MlSyncOptions{MaxRemovePercent: 100})
+	if err == nil || report != nil {
+		t.Errorf("lookup error not returned: %v, %+v", err, report)
+	}
+	if fake.called("MailingLists.getMlUserList") != 0 || fake.called("MailingLists.removeMlUserList") != 0 {
+		t.Errorf("members changed after lookup error")
+	}
+}
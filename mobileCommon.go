@@ -48,8 +48,8 @@ const (
 type ProtocolType string
 
 const (
-	protocolASync ProtocolType = "protocolASync"
-	protocolKBC   ProtocolType = "protocolKBC"
+	ProtocolASync ProtocolType = "protocolASync"
+	ProtocolKBC   ProtocolType = "protocolKBC"
 )
 
 // MobileDevice - Mobile device properties.
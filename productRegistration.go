@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 const unlimitedUsers = -2
 
 // @brief ineger value used in KISS as "UNLIMITED" in license, see Registration::subscribers and RegistrationFullStatus::users below
@@ -79,20 +77,20 @@ type Registration struct {
 type RegistrationFinishType string
 
 const (
-	rfCreate   RegistrationFinishType = "rfCreate"   // Create a new registration
-	rfModify   RegistrationFinishType = "rfModify"   // Modify existing Registration
-	rfDownload RegistrationFinishType = "rfDownload" // Download license key without any modification
-	rfStore    RegistrationFinishType = "rfStore"    // Just store in product without downloading key and modifying reg. (trial)
+	RfCreate   RegistrationFinishType = "rfCreate"   // Create a new registration
+	RfModify   RegistrationFinishType = "rfModify"   // Modify existing Registration
+	RfDownload RegistrationFinishType = "rfDownload" // Download license key without any modification
+	RfStore    RegistrationFinishType = "rfStore"    // Just store in product without downloading key and modifying reg. (trial)
 )
 
 // RegistrationType - A type of the current registration of the product
 type RegistrationType string
 
 const (
-	rsNoRegistration    RegistrationType = "rsNoRegistration"    // The product has not been registered yet
-	rsTrialRegistered   RegistrationType = "rsTrialRegistered"   // The product has a valid trial registration
-	rsTrialExpired      RegistrationType = "rsTrialExpired"      // The product has a trial registration but it has expired!
-	rsProductRegistered RegistrationType = "rsProductRegistered" // The product has been registered.
+	RsNoRegistration    RegistrationType = "rsNoRegistration"    // The product has not been registered yet
+	RsTrialRegistered   RegistrationType = "rsTrialRegistered"   // The product has a valid trial registration
+	RsTrialExpired      RegistrationType = "rsTrialExpired"      // The product has a trial registration but it has expired!
+	RsProductRegistered RegistrationType = "rsProductRegistered" // The product has been registered.
 )
 
 // RegistrationStatus - A registration Status of the current product.
@@ -183,7 +181,7 @@ func (s *ServerConnection) ProductRegistrationGet(token string, securityCode str
 			Trial            bool         `json:"trial"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &registrationInfo)
+	err = s.unmarshal(data, &registrationInfo)
 	return &registrationInfo.Result.RegistrationInfo, registrationInfo.Result.NewRegistration, registrationInfo.Result.Trial, err
 }
 
@@ -200,7 +198,7 @@ func (s *ServerConnection) ProductRegistrationGetFullStatus() (*RegistrationFull
 			Status RegistrationFullStatus `json:"status"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &status)
+	err = s.unmarshal(data, &status)
 	return &status.Result.Status, err
 }
 
@@ -217,7 +215,7 @@ func (s *ServerConnection) ProductRegistrationGetStatus() (*RegistrationStatus,
 			Status RegistrationStatus `json:"status"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &status)
+	err = s.unmarshal(data, &status)
 	return &status.Result.Status, err
 }
 
@@ -242,7 +240,7 @@ func (s *ServerConnection) ProductRegistrationStart(langId string) (string, stri
 			ShowImage bool   `json:"showImage"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &token)
+	err = s.unmarshal(data, &token)
 	return token.Result.Token, token.Result.Image, token.Result.ShowImage, err
 }
 
@@ -275,6 +273,6 @@ func (s *ServerConnection) ProductRegistrationVerifyNumber(token string, baseId
 			ExpirationDate RegDate                `json:"expirationDate"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, errors.Result.RegNumberInfo, errors.Result.AllowFinish, errors.Result.Users, &errors.Result.ExpirationDate, err
 }
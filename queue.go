@@ -1,17 +1,15 @@
 package connect
 
-import "encoding/json"
-
 type MessageStatus string
 
 const (
-	msExecuting        MessageStatus = "msExecuting"
-	msBackup           MessageStatus = "msBackup"
-	msContentFiltering MessageStatus = "msContentFiltering"
-	msAntivirusControl MessageStatus = "msAntivirusControl" // shouldn't it be Antivirus Check
-	msLocalDelivering  MessageStatus = "msLocalDelivering"
-	msSmtpDelivering   MessageStatus = "msSmtpDelivering"
-	msFinishing        MessageStatus = "msFinishing" // Terminating in AC manual vs. Finishing in AC
+	MsExecuting        MessageStatus = "msExecuting"
+	MsBackup           MessageStatus = "msBackup"
+	MsContentFiltering MessageStatus = "msContentFiltering"
+	MsAntivirusControl MessageStatus = "msAntivirusControl" // shouldn't it be Antivirus Check
+	MsLocalDelivering  MessageStatus = "msLocalDelivering"
+	MsSmtpDelivering   MessageStatus = "msSmtpDelivering"
+	MsFinishing        MessageStatus = "msFinishing" // Terminating in AC manual vs. Finishing in AC
 )
 
 // MessageInQueue - Message waiting in the queue
@@ -65,7 +63,7 @@ func (s *ServerConnection) QueueGet(query SearchQuery) (MessageInQueueList, int,
 			TotalItems int                `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, &list.Result.Volume, err
 }
 
@@ -89,7 +87,7 @@ func (s *ServerConnection) QueueGetProcessed(query SearchQuery) (MessageInProces
 			TotalItems int                  `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -108,7 +106,7 @@ func (s *ServerConnection) QueueRemove(messageIds KIdList) (int, error) {
 			DeleteItems int `json:"deleteItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &deleteItems)
+	err = s.unmarshal(data, &deleteItems)
 	return deleteItems.Result.DeleteItems, err
 }
 
@@ -123,7 +121,7 @@ func (s *ServerConnection) QueueRemoveAll() (int, error) {
 			DeleteItems int `json:"deleteItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &deleteItems)
+	err = s.unmarshal(data, &deleteItems)
 	return deleteItems.Result.DeleteItems, err
 }
 
@@ -144,7 +142,7 @@ func (s *ServerConnection) QueueRemoveMatching(senderPattern string, recipientPa
 			DeleteItems int `json:"deleteItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &deleteItems)
+	err = s.unmarshal(data, &deleteItems)
 	return deleteItems.Result.DeleteItems, err
 }
 
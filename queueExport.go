@@ -0,0 +1,189 @@
+package connect
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// DefaultQueueReportTopDomains - Count of domains listed in the queue report
+const DefaultQueueReportTopDomains = 10
+
+// DefaultQueueRetryAge - Age after which a message without status is considered stuck in retry
+const DefaultQueueRetryAge = 30 * time.Minute
+
+var queueCsvHeader = []string{"Id", "Created", "NextTry", "AgeSeconds", "Size", "From", "To", "RecipientDomain", "AuthSender", "SenderIp", "Status"}
+
+// WriteQueueCsv - Write queued messages as CSV with header, times are in RFC 3339 and sizes in bytes
+func WriteQueueCsv(w io.Writer, messages QueueMessageList) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(queueCsvHeader); err != nil {
+		return err
+	}
+	for _, message := range messages {
+		record := []string{string(message.Id), formatQueueTime(message.Created), formatQueueTime(message.NextTry),
+			strconv.FormatInt(durationSeconds(message.Age), 10), strconv.FormatInt(message.Size, 10), message.From, message.To,
+			message.RecipientDomain, message.AuthSender, message.SenderIp, message.Status}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteQueueJson - Write queued messages as indented JSON array
+func WriteQueueJson(w io.Writer, messages QueueMessageList) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(messages)
+}
+
+func formatQueueTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// QueueReportOptions - Options of NewQueueReport
+type QueueReportOptions struct {
+	TopDomains int             // count of listed domains; DefaultQueueReportTopDomains if 0
+	RetryAge   time.Duration   // age of message considered stuck in retry even without status; DefaultQueueRetryAge if 0
+	AgeBuckets []time.Duration // age groups of the snapshot taken by QueueReport, see QueueSnapshotOptions
+}
+
+// QueueReport - Daily overview of the queue
+type QueueReport struct {
+	Time               time.Time      `json:"time"`               // when the snapshot was taken
+	Messages           int            `json:"messages"`           // count of queued messages
+	Processing         int            `json:"processing"`         // count of messages being processed
+	Volume             int64          `json:"volume"`             // size of queued messages in bytes
+	Retrying           int            `json:"retrying"`           // count of messages stuck in retry
+	Authenticated      int            `json:"authenticated"`      // messages from authenticated senders
+	Unauthenticated    int            `json:"unauthenticated"`    // messages from unauthenticated senders
+	AuthenticatedShare float64        `json:"authenticatedShare"` // share of authenticated messages from 0 to 1
+	AgeHistogram       QueueGroupList `json:"ageHistogram"`       // messages by age in order of age groups of the snapshot, empty groups included
+	StuckDomains       QueueGroupList `json:"stuckDomains"`       // top recipient domains of messages stuck in retry
+}
+
+// NewQueueReport - Summarize the snapshot.
+// A message is stuck in retry if the server reports its status, e.g. the last delivery error, or if it is older than RetryAge.
+//	snapshot - snapshot from TakeQueueSnapshot or NewQueueSnapshot
+//	options - nil means defaults
+// Return
+//	report - summary of the queue
+func NewQueueReport(snapshot *QueueSnapshot, options *QueueReportOptions) *QueueReport {
+	var o QueueReportOptions
+	if options != nil {
+		o = *options
+	}
+	if o.TopDomains <= 0 {
+		o.TopDomains = DefaultQueueReportTopDomains
+	}
+	if o.RetryAge <= 0 {
+		o.RetryAge = DefaultQueueRetryAge
+	}
+	report := &QueueReport{Time: snapshot.Time, Messages: len(snapshot.Messages), Processing: len(snapshot.Processing), Volume: snapshot.Volume}
+	var retrying QueueMessageList
+	for _, message := range snapshot.Messages {
+		if message.AuthSender != "" {
+			report.Authenticated++
+		} else {
+			report.Unauthenticated++
+		}
+		if message.Status != "" || message.Age >= o.RetryAge {
+			retrying = append(retrying, message)
+		}
+	}
+	if report.Messages > 0 {
+		report.AuthenticatedShare = float64(report.Authenticated) / float64(report.Messages)
+	}
+	report.Retrying = len(retrying)
+	report.StuckDomains = retrying.GroupBy(func(m QueueMessage) string { return m.RecipientDomain })
+	if len(report.StuckDomains) > o.TopDomains {
+		report.StuckDomains = report.StuckDomains[:o.TopDomains]
+	}
+	report.AgeHistogram = queueAgeHistogram(snapshot)
+	return report
+}
+
+// queueAgeHistogram orders age groups of the snapshot and adds the empty ones
+func queueAgeHistogram(snapshot *QueueSnapshot) QueueGroupList {
+	buckets := snapshot.ageBuckets
+	if len(buckets) == 0 {
+		// snapshot was not built by NewQueueSnapshot, e.g. decoded from JSON
+		buckets = (*QueueSnapshotOptions)(nil).ageBuckets()
+	}
+	histogram := make(QueueGroupList, 0, len(buckets)+1)
+	for _, limit := range buckets {
+		histogram = append(histogram, QueueGroup{Key: queueAgeBucket(limit-1, buckets)})
+	}
+	histogram = append(histogram, QueueGroup{Key: queueAgeBucket(buckets[len(buckets)-1], buckets)})
+	for i := range histogram {
+		if group, ok := snapshot.ByAge.Find(histogram[i].Key); ok {
+			histogram[i] = group
+		}
+	}
+	return histogram
+}
+
+// QueueReport - Take snapshot of the queue and summarize it.
+//	location - time zone of the server; nil means UTC
+//	options - nil means defaults
+// Return
+//	report - summary of the queue
+//	snapshot - messages the report was built from, e.g. for WriteQueueCsv
+func (s *ServerConnection) QueueReport(location *time.Location, options *QueueReportOptions) (*QueueReport, *QueueSnapshot, error) {
+	var buckets []time.Duration
+	if options != nil {
+		buckets = options.AgeBuckets
+	}
+	snapshot, err := s.TakeQueueSnapshot(&QueueSnapshotOptions{Location: location, AgeBuckets: buckets})
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewQueueReport(snapshot, options), snapshot, nil
+}
+
+// WriteQueueReport - Write the report as text for people
+func WriteQueueReport(w io.Writer, report *QueueReport) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Queue report\t%s\n", report.Time.Format(time.RFC3339))
+	fmt.Fprintf(tw, "Messages\t%d\n", report.Messages)
+	fmt.Fprintf(tw, "Processing\t%d\n", report.Processing)
+	fmt.Fprintf(tw, "Volume\t%s\n", formatByteCount(report.Volume))
+	fmt.Fprintf(tw, "Stuck in retry\t%d\n", report.Retrying)
+	fmt.Fprintf(tw, "Authenticated\t%d (%.1f %%)\n", report.Authenticated, report.AuthenticatedShare*100)
+	fmt.Fprintf(tw, "Unauthenticated\t%d\n", report.Unauthenticated)
+	fmt.Fprintf(tw, "\nAge\tMessages\tVolume\n")
+	for _, group := range report.AgeHistogram {
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", group.Key, group.Count, formatByteCount(group.Size))
+	}
+	if len(report.StuckDomains) > 0 {
+		fmt.Fprintf(tw, "\nStuck domain\tMessages\tOldest\n")
+		for _, group := range report.StuckDomains {
+			fmt.Fprintf(tw, "%s\t%d\t%s\n", group.Key, group.Count, group.Oldest.Truncate(time.Minute))
+		}
+	}
+	return tw.Flush()
+}
+
+// formatByteCount writes size with binary units, e.g. 1.5 MiB
+func formatByteCount(size int64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	value := float64(size)
+	i := 0
+	for value >= 1024 && i < len(units)-1 {
+		value /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%d B", size)
+	}
+	return fmt.Sprintf("%.1f %s", value, units[i])
+}
@@ -0,0 +1,95 @@
+package connect
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testQueueSnapshot() *QueueSnapshot {
+	now := time.Date(2021, 1, 28, 12, 0, 0, 0, time.UTC)
+	queued := append(queueMessages(3, "alice@example.com", "bob@example.org", "2021-01-28 11:50"),
+		queueMessages(2, "", "carol@example.net", "2021-01-26 12:00")...)
+	queued[0].Status = "Remote host not responding"
+	queued[0].NextTry = "2021-01-28 12:10"
+	return NewQueueSnapshot(now, queued, MessageInProcessList{{Id: "p1"}}, &QueueSnapshotOptions{Location: time.UTC})
+}
+
+func TestWriteQueueCsv(t *testing.T) {
+	snapshot := testQueueSnapshot()
+	buffer := &bytes.Buffer{}
+	if err := WriteQueueCsv(buffer, snapshot.Messages); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(buffer).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 6 || strings.Join(records[0], ",") != strings.Join(queueCsvHeader, ",") {
+		t.Fatalf("unexpected records %v", records)
+	}
+	expected := []string{string(snapshot.Messages[0].Id), "2021-01-28T11:50:00Z", "2021-01-28T12:10:00Z", "600", "2048",
+		"alice@example.com", "bob@example.org", "example.org", "alice@example.com", "10.0.0.1", "Remote host not responding"}
+	if strings.Join(records[1], ",") != strings.Join(expected, ",") {
+		t.Errorf("unexpected record %v", records[1])
+	}
+	if records[2][2] != "" {
+		t.Errorf("unparsed time exported as %q", records[2][2])
+	}
+}
+
+func TestWriteQueueJson(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	if err := WriteQueueJson(buffer, testQueueSnapshot().Messages); err != nil {
+		t.Fatal(err)
+	}
+	var messages QueueMessageList
+	if err := json.Unmarshal(buffer.Bytes(), &messages); err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 5 || messages[0].Size != 2048 || !messages[0].Created.Equal(time.Date(2021, 1, 28, 11, 50, 0, 0, time.UTC)) ||
+		messages[0].Age != 10*time.Minute {
+		t.Errorf("unexpected messages %+v", messages)
+	}
+	if !strings.Contains(buffer.String(), `"ageSeconds": 600`) {
+		t.Errorf("age is not exported in seconds:\n%s", buffer.String())
+	}
+	data, err := json.Marshal(QueueReport{AgeHistogram: QueueGroupList{{Key: "<1h", Count: 1, Oldest: 90 * time.Second}}})
+	if err != nil || !strings.Contains(string(data), `"oldestSeconds":90`) {
+		t.Errorf("oldest age is not exported in seconds: %s %v", data, err)
+	}
+	var thresholds QueueThresholds
+	if err = json.Unmarshal([]byte(`{"maxMessages":10,"maxDomainAgeSeconds":3600}`), &thresholds); err != nil ||
+		thresholds.MaxMessages != 10 || thresholds.MaxDomainAge != time.Hour {
+		t.Errorf("unexpected thresholds %+v %v", thresholds, err)
+	}
+}
+
+func TestNewQueueReport(t *testing.T) {
+	report := NewQueueReport(testQueueSnapshot(), &QueueReportOptions{TopDomains: 1})
+	if report.Messages != 5 || report.Processing != 1 || report.Volume != 5*2048 {
+		t.Errorf("unexpected totals %+v", report)
+	}
+	if report.Authenticated != 3 || report.Unauthenticated != 2 || report.AuthenticatedShare != 0.6 {
+		t.Errorf("unexpected authentication share %+v", report)
+	}
+	if report.Retrying != 3 || len(report.StuckDomains) != 1 || report.StuckDomains[0].Key != "example.net" || report.StuckDomains[0].Count != 2 {
+		t.Errorf("unexpected stuck domains %d %+v", report.Retrying, report.StuckDomains)
+	}
+	if len(report.AgeHistogram) != 6 || report.AgeHistogram[0].Count != 3 || report.AgeHistogram[3].Key != "<3d" ||
+		report.AgeHistogram[3].Count != 2 || report.AgeHistogram[5].Count != 0 {
+		t.Errorf("unexpected age histogram %+v", report.AgeHistogram)
+	}
+	buffer := &bytes.Buffer{}
+	if err := WriteQueueReport(buffer, report); err != nil {
+		t.Fatal(err)
+	}
+	for _, text := range []string{"Volume           10.0 KiB", "Authenticated    3 (60.0 %)", "example.net   2         48h0m0s"} {
+		if !strings.Contains(buffer.String(), text) {
+			t.Errorf("report does not contain %q:\n%s", text, buffer.String())
+		}
+	}
+}
@@ -0,0 +1,393 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultQueueMonitorInterval - Pause between queue snapshots
+const DefaultQueueMonitorInterval = time.Minute
+
+// QueueMessage - Message waiting in the queue with parsed times and size
+type QueueMessage struct {
+	Id              KId            `json:"id"`              // queue ID
+	Created         time.Time      `json:"created"`         // zero if creation time could not be parsed
+	NextTry         time.Time      `json:"nextTry"`         // zero if time of next try could not be parsed
+	Age             time.Duration  `json:"-"`               // time since creation when the snapshot was taken, ageSeconds in JSON
+	Size            int64          `json:"size"`            // message size in bytes
+	From            string         `json:"from"`            // sender address
+	To              string         `json:"to"`              // recipient address
+	RecipientDomain string         `json:"recipientDomain"` // domain of recipient in lower case
+	AuthSender      string         `json:"authSender"`      // authenticated sender, empty for unauthenticated messages
+	SenderIp        string         `json:"senderIp"`        // IP address of sender
+	Status          string         `json:"status"`          // message status
+	Raw             MessageInQueue `json:"-"`               // message as returned by QueueGet
+}
+
+// MarshalJSON - Encode message with Age in seconds like WriteQueueCsv
+func (m QueueMessage) MarshalJSON() ([]byte, error) {
+	type message QueueMessage
+	return json.Marshal(struct {
+		message
+		AgeSeconds int64 `json:"ageSeconds"`
+	}{message(m), durationSeconds(m.Age)})
+}
+
+// UnmarshalJSON - Decode message with Age in seconds
+func (m *QueueMessage) UnmarshalJSON(data []byte) error {
+	type message QueueMessage
+	value := struct {
+		*message
+		AgeSeconds int64 `json:"ageSeconds"`
+	}{message: (*message)(m)}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	m.Age = time.Duration(value.AgeSeconds) * time.Second
+	return nil
+}
+
+// QueueMessageList - List of queued messages
+type QueueMessageList []QueueMessage
+
+// QueueGroup - Messages of the queue sharing the same value of a property
+type QueueGroup struct {
+	Key    string        `json:"key"`   // value of the property; empty string groups messages without the value
+	Count  int           `json:"count"` // count of messages
+	Size   int64         `json:"size"`  // size of messages in bytes
+	Oldest time.Duration `json:"-"`     // age of the oldest message, oldestSeconds in JSON
+}
+
+// MarshalJSON - Encode group with Oldest in seconds
+func (g QueueGroup) MarshalJSON() ([]byte, error) {
+	type group QueueGroup
+	return json.Marshal(struct {
+		group
+		OldestSeconds int64 `json:"oldestSeconds"`
+	}{group(g), durationSeconds(g.Oldest)})
+}
+
+// UnmarshalJSON - Decode group with Oldest in seconds
+func (g *QueueGroup) UnmarshalJSON(data []byte) error {
+	type group QueueGroup
+	value := struct {
+		*group
+		OldestSeconds int64 `json:"oldestSeconds"`
+	}{group: (*group)(g)}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	g.Oldest = time.Duration(value.OldestSeconds) * time.Second
+	return nil
+}
+
+// durationSeconds returns whole seconds of duration
+func durationSeconds(d time.Duration) int64 {
+	return int64(d / time.Second)
+}
+
+// QueueGroupList - Groups ordered by count of messages, the largest first
+type QueueGroupList []QueueGroup
+
+// QueueSnapshot - State of the queue in the time of snapshot
+type QueueSnapshot struct {
+	Time              time.Time            `json:"time"`
+	Messages          QueueMessageList     `json:"messages"`          // messages waiting in the queue
+	Processing        MessageInProcessList `json:"processing"`        // messages being processed
+	Volume            int64                `json:"volume"`            // size of all queued messages in bytes
+	BySender          QueueGroupList       `json:"bySender"`          // messages grouped by sender address
+	ByRecipientDomain QueueGroupList       `json:"byRecipientDomain"` // messages grouped by domain of recipient
+	ByAuthSender      QueueGroupList       `json:"byAuthSender"`      // messages grouped by authenticated sender
+	BySenderIp        QueueGroupList       `json:"bySenderIp"`        // messages grouped by sender IP address
+	ByAge             QueueGroupList       `json:"byAge"`             // messages grouped by age, see QueueSnapshotOptions.AgeBuckets
+	ageBuckets        []time.Duration
+}
+
+// QueueSnapshotOptions - Options of TakeQueueSnapshot and NewQueueSnapshot
+type QueueSnapshotOptions struct {
+	Location   *time.Location  // time zone of the server used to parse times of messages; nil means UTC
+	AgeBuckets []time.Duration // upper limits of age groups of QueueSnapshot.ByAge, older messages fall into the last group; 1h, 6h, 1d, 3d and 7d if empty
+}
+
+// ageBuckets returns positive limits in ascending order or the default ones
+func (o *QueueSnapshotOptions) ageBuckets() []time.Duration {
+	var buckets []time.Duration
+	if o != nil {
+		for _, limit := range o.AgeBuckets {
+			if limit > 0 {
+				buckets = append(buckets, limit)
+			}
+		}
+	}
+	if len(buckets) == 0 {
+		return []time.Duration{time.Hour, 6 * time.Hour, 24 * time.Hour, 3 * 24 * time.Hour, 7 * 24 * time.Hour}
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i] < buckets[j] })
+	return buckets
+}
+
+// TakeQueueSnapshot - Read queued and processed messages and aggregate them.
+//	options - time zone of the server and age groups; nil means defaults
+// Return
+//	snapshot - messages of the queue and their aggregations
+func (s *ServerConnection) TakeQueueSnapshot(options *QueueSnapshotOptions) (*QueueSnapshot, error) {
+	queued, _, _, err := s.QueueGet(SearchQuery{})
+	if err != nil {
+		return nil, err
+	}
+	processing, _, err := s.QueueGetProcessed(SearchQuery{})
+	if err != nil {
+		return nil, err
+	}
+	return NewQueueSnapshot(time.Now(), queued, processing, options), nil
+}
+
+// NewQueueSnapshot - Build snapshot from messages returned by QueueGet and QueueGetProcessed.
+// Options are the same as of TakeQueueSnapshot, nil means defaults.
+func NewQueueSnapshot(now time.Time, queued MessageInQueueList, processing MessageInProcessList, options *QueueSnapshotOptions) *QueueSnapshot {
+	var location *time.Location
+	if options != nil {
+		location = options.Location
+	}
+	snapshot := &QueueSnapshot{Time: now, Processing: processing, Messages: make(QueueMessageList, 0, len(queued)), ageBuckets: options.ageBuckets()}
+	for _, raw := range queued {
+		message := QueueMessage{Id: raw.Id, Size: raw.MessageSize.ByteCount(), From: trimAngleBrackets(raw.From), To: trimAngleBrackets(raw.To),
+			AuthSender: strings.ToLower(raw.AuthSender), SenderIp: string(raw.SenderIp), Status: raw.Status, Raw: raw}
+		_, message.RecipientDomain = splitAddress(message.To)
+		message.Created, _ = parseServerTime(raw.CreationTime, location)
+		message.NextTry, _ = parseServerTime(raw.NextTry, location)
+		if !message.Created.IsZero() && now.After(message.Created) {
+			message.Age = now.Sub(message.Created)
+		}
+		snapshot.Volume += message.Size
+		snapshot.Messages = append(snapshot.Messages, message)
+	}
+	snapshot.BySender = snapshot.Messages.GroupBy(func(m QueueMessage) string { return strings.ToLower(m.From) })
+	snapshot.ByRecipientDomain = snapshot.Messages.GroupBy(func(m QueueMessage) string { return m.RecipientDomain })
+	snapshot.ByAuthSender = snapshot.Messages.GroupBy(func(m QueueMessage) string { return m.AuthSender })
+	snapshot.BySenderIp = snapshot.Messages.GroupBy(func(m QueueMessage) string { return m.SenderIp })
+	snapshot.ByAge = snapshot.Messages.GroupBy(func(m QueueMessage) string { return queueAgeBucket(m.Age, snapshot.ageBuckets) })
+	return snapshot
+}
+
+// GroupBy - Aggregate messages by the key
+func (l QueueMessageList) GroupBy(key func(QueueMessage) string) QueueGroupList {
+	index := map[string]int{}
+	var groups QueueGroupList
+	for _, message := range l {
+		k := key(message)
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, QueueGroup{Key: k})
+		}
+		groups[i].Count++
+		groups[i].Size += message.Size
+		if message.Age > groups[i].Oldest {
+			groups[i].Oldest = message.Age
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Key < groups[j].Key
+	})
+	return groups
+}
+
+// Find - Return the group with the key
+func (l QueueGroupList) Find(key string) (QueueGroup, bool) {
+	for _, group := range l {
+		if group.Key == key {
+			return group, true
+		}
+	}
+	return QueueGroup{}, false
+}
+
+// queueAgeBucket returns name of age group, e.g. "<6h" or ">=7d"; buckets are not empty
+func queueAgeBucket(age time.Duration, buckets []time.Duration) string {
+	for _, limit := range buckets {
+		if age < limit {
+			return "<" + formatAge(limit)
+		}
+	}
+	return ">=" + formatAge(buckets[len(buckets)-1])
+}
+
+// formatAge writes whole days and hours shortly, e.g. 3d or 6h
+func formatAge(age time.Duration) string {
+	switch {
+	case age%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", age/(24*time.Hour))
+	case age%time.Hour == 0:
+		return fmt.Sprintf("%dh", age/time.Hour)
+	}
+	return age.String()
+}
+
+// QueueAnomalyType - Kind of unusual state of the queue
+type QueueAnomalyType string
+
+const (
+	QueueTooLarge         QueueAnomalyType = "QueueTooLarge"         // too many messages in the queue
+	QueueAuthSenderFlood  QueueAnomalyType = "QueueAuthSenderFlood"  // authenticated sender queued too many messages, possibly compromised account
+	QueueAuthSenderGrowth QueueAnomalyType = "QueueAuthSenderGrowth" // messages of authenticated sender grew too fast since previous snapshot
+	QueueSenderIpFlood    QueueAnomalyType = "QueueSenderIpFlood"    // too many messages from one IP address
+	QueueDomainStuck      QueueAnomalyType = "QueueDomainStuck"      // messages to the domain wait too long
+)
+
+// QueueThresholds - Limits of anomaly detection; zero disables the check
+type QueueThresholds struct {
+	MaxMessages         int           `json:"maxMessages" yaml:"maxMessages"`                 // messages in the whole queue
+	MaxPerAuthSender    int           `json:"maxPerAuthSender" yaml:"maxPerAuthSender"`       // messages of one authenticated sender
+	MaxAuthSenderGrowth int           `json:"maxAuthSenderGrowth" yaml:"maxAuthSenderGrowth"` // new messages of one authenticated sender since previous snapshot
+	MaxPerSenderIp      int           `json:"maxPerSenderIp" yaml:"maxPerSenderIp"`           // messages from one IP address
+	MaxDomainAge        time.Duration `json:"-" yaml:"maxDomainAge"`                          // age of the oldest message to a domain, maxDomainAgeSeconds in JSON
+}
+
+// MarshalJSON - Encode thresholds with MaxDomainAge in seconds
+func (t QueueThresholds) MarshalJSON() ([]byte, error) {
+	type thresholds QueueThresholds
+	return json.Marshal(struct {
+		thresholds
+		MaxDomainAgeSeconds int64 `json:"maxDomainAgeSeconds"`
+	}{thresholds(t), durationSeconds(t.MaxDomainAge)})
+}
+
+// UnmarshalJSON - Decode thresholds with MaxDomainAge in seconds
+func (t *QueueThresholds) UnmarshalJSON(data []byte) error {
+	type thresholds QueueThresholds
+	value := struct {
+		*thresholds
+		MaxDomainAgeSeconds int64 `json:"maxDomainAgeSeconds"`
+	}{thresholds: (*thresholds)(t)}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	t.MaxDomainAge = time.Duration(value.MaxDomainAgeSeconds) * time.Second
+	return nil
+}
+
+// QueueAnomaly - Unusual state found in the snapshot
+type QueueAnomaly struct {
+	Type        QueueAnomalyType `json:"type"`
+	Key         string           `json:"key,omitempty"` // sender, IP address or domain
+	Value       int64            `json:"value"`         // measured count or age in seconds
+	Limit       int64            `json:"limit"`         // exceeded threshold
+	Description string           `json:"description"`
+}
+
+// QueueAnomalyList - List of anomalies
+type QueueAnomalyList []QueueAnomaly
+
+// DetectQueueAnomalies - Compare the snapshot with thresholds and with the previous snapshot.
+//	previous - previous snapshot, may be nil
+//	current - current snapshot
+//	thresholds - limits of checks
+// Return
+//	anomalies - exceeded thresholds
+func DetectQueueAnomalies(previous, current *QueueSnapshot, thresholds QueueThresholds) QueueAnomalyList {
+	var anomalies QueueAnomalyList
+	add := func(anomalyType QueueAnomalyType, key string, value, limit int64, format string) {
+		anomalies = append(anomalies, QueueAnomaly{Type: anomalyType, Key: key, Value: value, Limit: limit,
+			Description: fmt.Sprintf(format, key, value, limit)})
+	}
+	if thresholds.MaxMessages > 0 && len(current.Messages) > thresholds.MaxMessages {
+		anomalies = append(anomalies, QueueAnomaly{Type: QueueTooLarge, Value: int64(len(current.Messages)), Limit: int64(thresholds.MaxMessages),
+			Description: fmt.Sprintf("queue has %d messages, limit is %d", len(current.Messages), thresholds.MaxMessages)})
+	}
+	for _, group := range current.ByAuthSender {
+		if group.Key == "" {
+			continue
+		}
+		if thresholds.MaxPerAuthSender > 0 && group.Count > thresholds.MaxPerAuthSender {
+			add(QueueAuthSenderFlood, group.Key, int64(group.Count), int64(thresholds.MaxPerAuthSender),
+				"authenticated sender %s has %d queued messages, limit is %d")
+		}
+		if thresholds.MaxAuthSenderGrowth > 0 && previous != nil {
+			before, _ := previous.ByAuthSender.Find(group.Key)
+			if growth := group.Count - before.Count; growth > thresholds.MaxAuthSenderGrowth {
+				add(QueueAuthSenderGrowth, group.Key, int64(growth), int64(thresholds.MaxAuthSenderGrowth),
+					"authenticated sender %s queued %d new messages since previous snapshot, limit is %d")
+			}
+		}
+	}
+	for _, group := range current.BySenderIp {
+		if group.Key != "" && thresholds.MaxPerSenderIp > 0 && group.Count > thresholds.MaxPerSenderIp {
+			add(QueueSenderIpFlood, group.Key, int64(group.Count), int64(thresholds.MaxPerSenderIp),
+				"IP address %s has %d queued messages, limit is %d")
+		}
+	}
+	for _, group := range current.ByRecipientDomain {
+		if thresholds.MaxDomainAge > 0 && group.Oldest > thresholds.MaxDomainAge {
+			add(QueueDomainStuck, group.Key, int64(group.Oldest/time.Second), int64(thresholds.MaxDomainAge/time.Second),
+				"the oldest message to %s waits %d seconds, limit is %d")
+		}
+	}
+	return anomalies
+}
+
+// QueueMonitorOptions - Options of MonitorQueue
+type QueueMonitorOptions struct {
+	Interval   time.Duration                    // pause between snapshots; DefaultQueueMonitorInterval if 0
+	Location   *time.Location                   // time zone of the server; nil means UTC
+	AgeBuckets []time.Duration                  // age groups of snapshots, see QueueSnapshotOptions
+	Thresholds QueueThresholds                  // limits of anomaly detection
+	Policies   QueuePolicyList                  // policies applied to each snapshot
+	DryRun     bool                             // only report what the policies would do
+	Audit      io.Writer                        // audit log of applied policies in JSON lines; nil disables it
+	OnReport   func(report *QueueMonitorReport) // called after each snapshot
+	OnError    func(err error)                  // called when snapshot or policy fails, monitoring continues
+}
+
+// QueueMonitorReport - Result of one round of monitoring
+type QueueMonitorReport struct {
+	Snapshot  *QueueSnapshot        `json:"snapshot"`
+	Anomalies QueueAnomalyList      `json:"anomalies"`
+	Policies  QueuePolicyResultList `json:"policies"`
+}
+
+// MonitorQueue - Periodically snapshot the queue, detect anomalies and apply policies until ctx is done
+func (s *ServerConnection) MonitorQueue(ctx context.Context, options *QueueMonitorOptions) {
+	var o QueueMonitorOptions
+	if options != nil {
+		o = *options
+	}
+	if o.Interval <= 0 {
+		o.Interval = DefaultQueueMonitorInterval
+	}
+	var previous *QueueSnapshot
+	for {
+		snapshot, err := s.TakeQueueSnapshot(&QueueSnapshotOptions{Location: o.Location, AgeBuckets: o.AgeBuckets})
+		if err != nil {
+			if o.OnError != nil {
+				o.OnError(err)
+			}
+		} else {
+			report := &QueueMonitorReport{Snapshot: snapshot, Anomalies: DetectQueueAnomalies(previous, snapshot, o.Thresholds)}
+			if len(o.Policies) > 0 {
+				report.Policies, err = s.ApplyQueuePolicies(snapshot, o.Policies, &QueuePolicyOptions{DryRun: o.DryRun, Audit: o.Audit})
+				if err != nil && o.OnError != nil {
+					o.OnError(err)
+				}
+			}
+			if o.OnReport != nil {
+				o.OnReport(report)
+			}
+			previous = snapshot
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(o.Interval):
+		}
+	}
+}
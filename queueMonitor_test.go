@@ -0,0 +1,156 @@
+package connect
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func queueMessages(count int, authSender, to, created string) MessageInQueueList {
+	list := make(MessageInQueueList, count)
+	for i := range list {
+		list[i] = MessageInQueue{Id: KId(authSender + to + string(rune('a'+i))), CreationTime: created, From: "<" + authSender + ">", To: to,
+			MessageSize: ByteValueWithUnits{Value: 2, Units: KiloBytes}, AuthSender: authSender, SenderIp: "10.0.0.1"}
+	}
+	return list
+}
+
+func TestNewQueueSnapshot(t *testing.T) {
+	now := time.Date(2021, 1, 28, 12, 0, 0, 0, time.UTC)
+	queued := append(queueMessages(3, "alice@example.com", "bob@example.org", "2021-01-28 11:30"),
+		queueMessages(1, "", "carol@Example.NET", "2021-01-20 12:00")...)
+	snapshot := NewQueueSnapshot(now, queued, nil, &QueueSnapshotOptions{Location: time.UTC})
+	if len(snapshot.Messages) != 4 || snapshot.Volume != 4*2048 {
+		t.Fatalf("unexpected snapshot %d messages, volume %d", len(snapshot.Messages), snapshot.Volume)
+	}
+	if snapshot.Messages[0].From != "alice@example.com" || snapshot.Messages[0].Age != 30*time.Minute {
+		t.Errorf("unexpected message %+v", snapshot.Messages[0])
+	}
+	if group, ok := snapshot.ByAuthSender.Find("alice@example.com"); !ok || group.Count != 3 || group.Size != 3*2048 {
+		t.Errorf("unexpected auth sender group %+v", group)
+	}
+	if group, ok := snapshot.ByRecipientDomain.Find("example.net"); !ok || group.Oldest != 8*24*time.Hour {
+		t.Errorf("unexpected domain group %+v", group)
+	}
+	if snapshot.ByAge[0].Key != "<1h" || snapshot.ByAge[1].Key != ">=7d" {
+		t.Errorf("unexpected age groups %+v", snapshot.ByAge)
+	}
+}
+
+func TestNewQueueSnapshotAgeBuckets(t *testing.T) {
+	now := time.Date(2021, 1, 28, 12, 0, 0, 0, time.UTC)
+	queued := append(queueMessages(1, "", "bob@example.org", "2021-01-28 11:30"),
+		queueMessages(1, "", "carol@example.net", "2021-01-28 08:00")...)
+	snapshot := NewQueueSnapshot(now, queued, nil, &QueueSnapshotOptions{Location: time.UTC, AgeBuckets: []time.Duration{2 * time.Hour, 0}})
+	if len(snapshot.ByAge) != 2 || snapshot.ByAge[0].Key != "<2h" || snapshot.ByAge[1].Key != ">=2h" {
+		t.Errorf("unexpected age groups %+v", snapshot.ByAge)
+	}
+	if histogram := NewQueueReport(snapshot, nil).AgeHistogram; len(histogram) != 2 {
+		t.Errorf("unexpected age histogram %+v", histogram)
+	}
+	snapshot = NewQueueSnapshot(now, queued, nil, &QueueSnapshotOptions{AgeBuckets: []time.Duration{}})
+	if len(NewQueueReport(snapshot, nil).AgeHistogram) != 6 {
+		t.Errorf("default age groups are not used for empty list")
+	}
+}
+
+func TestDetectQueueAnomalies(t *testing.T) {
+	now := time.Date(2021, 1, 28, 12, 0, 0, 0, time.UTC)
+	previous := NewQueueSnapshot(now.Add(-time.Minute), queueMessages(2, "alice@example.com", "bob@example.org", "2021-01-28 11:59"), nil, &QueueSnapshotOptions{Location: time.UTC})
+	current := NewQueueSnapshot(now, append(queueMessages(20, "alice@example.com", "bob@example.org", "2021-01-28 11:59"),
+		queueMessages(1, "", "carol@example.net", "2021-01-26 12:00")...), nil, &QueueSnapshotOptions{Location: time.UTC})
+	anomalies := DetectQueueAnomalies(previous, current, QueueThresholds{
+		MaxMessages:         100,
+		MaxPerAuthSender:    10,
+		MaxAuthSenderGrowth: 15,
+		MaxDomainAge:        24 * time.Hour,
+	})
+	expected := []QueueAnomalyType{QueueAuthSenderFlood, QueueAuthSenderGrowth, QueueDomainStuck}
+	if len(anomalies) != len(expected) {
+		t.Fatalf("unexpected anomalies %+v", anomalies)
+	}
+	for i := range expected {
+		if anomalies[i].Type != expected[i] {
+			t.Errorf("unexpected anomaly %+v", anomalies[i])
+		}
+	}
+	if anomalies[1].Value != 18 || anomalies[2].Key != "example.net" {
+		t.Errorf("unexpected anomalies %+v", anomalies)
+	}
+	if DetectQueueAnomalies(nil, current, QueueThresholds{MaxAuthSenderGrowth: 1}) != nil {
+		t.Error("growth detected without previous snapshot")
+	}
+}
+
+func TestReadQueuePolicies(t *testing.T) {
+	policies, err := ReadQueuePolicies(strings.NewReader(`
+- name: stale
+  action: remove
+  recipientDomain: "*.example.org"
+  olderThan: 5d
+- name: retry
+  action: retry
+  status: not responding
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies) != 2 || policies[0].OlderThan != "5d" || policies[1].Action != QueuePolicyRetry {
+		t.Errorf("unexpected policies %+v", policies)
+	}
+	if _, err = ReadQueuePolicies(strings.NewReader(`[{"name": "all", "action": "remove"}]`)); err == nil {
+		t.Error("policy without condition accepted")
+	}
+	if _, err = ReadQueuePolicies(strings.NewReader(`[{"name": "x", "action": "bounce", "olderThan": "1d"}]`)); err == nil {
+		t.Error("unknown action accepted")
+	}
+}
+
+func TestApplyQueuePolicies(t *testing.T) {
+	now := time.Date(2021, 1, 28, 12, 0, 0, 0, time.UTC)
+	snapshot := NewQueueSnapshot(now, append(queueMessages(3, "alice@example.com", "bob@mx.example.org", "2021-01-20 12:00"),
+		queueMessages(2, "", "carol@example.net", "2021-01-28 11:00")...), nil, &QueueSnapshotOptions{Location: time.UTC})
+	policies := QueuePolicyList{
+		{Name: "stale", Action: QueuePolicyRemove, RecipientDomain: "*.EXAMPLE.org", OlderThan: "5d"},
+		{Name: "limited", Action: QueuePolicyRemove, RecipientDomain: "example.net", MaxMessages: 1},
+		{Name: "retry", Action: QueuePolicyRetry, SenderPattern: "*"},
+	}
+	conn, fake := newFakeConnection(t, map[string]fakeHandler{
+		"Queue.remove":    result(map[string]interface{}{"deleteItems": 3}),
+		"Queue.tryToSend": result(map[string]interface{}{}),
+	})
+	var audit bytes.Buffer
+	results, err := conn.ApplyQueuePolicies(snapshot, policies, &QueuePolicyOptions{DryRun: true, Audit: &audit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fake.called("Queue.remove") != 0 || fake.called("Queue.tryToSend") != 0 {
+		t.Fatal("dry run changed the queue")
+	}
+	if len(results) != 3 || len(results[0].Matched) != 3 || results[0].Skipped != "dry run" || !strings.Contains(results[1].Skipped, "refuses") {
+		t.Errorf("unexpected dry run results %+v", results)
+	}
+	if len(results[2].Matched) != 2 {
+		t.Errorf("messages matched by earlier policy retried %+v", results[2])
+	}
+	if lines := strings.Split(strings.TrimSpace(audit.String()), "\n"); len(lines) != 3 {
+		t.Errorf("unexpected audit log %q", audit.String())
+	} else {
+		var entry QueuePolicyResult
+		if err = json.Unmarshal([]byte(lines[0]), &entry); err != nil || entry.Policy != "stale" || !entry.DryRun {
+			t.Errorf("unexpected audit entry %s", lines[0])
+		}
+	}
+	results, err = conn.ApplyQueuePolicies(snapshot, policies, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fake.called("Queue.remove") != 1 || fake.called("Queue.tryToSend") != 1 {
+		t.Fatal("policies not applied")
+	}
+	if results[0].Applied != 3 || results[2].Applied != 2 {
+		t.Errorf("unexpected results %+v", results)
+	}
+}
@@ -0,0 +1,216 @@
+package connect
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// QueuePolicyAction - What the policy does with matching messages
+type QueuePolicyAction string
+
+const (
+	QueuePolicyRemove QueuePolicyAction = "remove" // remove messages by QueueRemove
+	QueuePolicyRetry  QueuePolicyAction = "retry"  // try to send messages now by QueueTryToSend
+)
+
+// QueuePolicy - Declarative rule for queued messages; all given conditions have to match.
+// Patterns may contain wildcards * and ? and are compared case-insensitively.
+type QueuePolicy struct {
+	Name             string            `json:"name" yaml:"name"`
+	Action           QueuePolicyAction `json:"action" yaml:"action"`
+	SenderPattern    string            `json:"senderPattern,omitempty" yaml:"senderPattern,omitempty"`       // sender address
+	RecipientPattern string            `json:"recipientPattern,omitempty" yaml:"recipientPattern,omitempty"` // recipient address
+	RecipientDomain  string            `json:"recipientDomain,omitempty" yaml:"recipientDomain,omitempty"`   // domain of recipient
+	AuthSender       string            `json:"authSender,omitempty" yaml:"authSender,omitempty"`             // authenticated sender
+	SenderIp         string            `json:"senderIp,omitempty" yaml:"senderIp,omitempty"`                 // IP address of sender
+	Status           string            `json:"status,omitempty" yaml:"status,omitempty"`                     // substring of message status
+	OlderThan        string            `json:"olderThan,omitempty" yaml:"olderThan,omitempty"`               // minimal age, e.g. 5d, 12h or 90m
+	MinMessages      int               `json:"minMessages,omitempty" yaml:"minMessages,omitempty"`           // apply only if at least this count of messages match
+	MaxMessages      int               `json:"maxMessages,omitempty" yaml:"maxMessages,omitempty"`           // refuse to apply if more messages match
+}
+
+// QueuePolicyList - Policies applied in order, each message is handled by the first matching policy
+type QueuePolicyList []QueuePolicy
+
+// ReadQueuePolicies - Read policies from YAML or JSON and validate them
+func ReadQueuePolicies(r io.Reader) (QueuePolicyList, error) {
+	var policies QueuePolicyList
+	if err := yaml.NewDecoder(r).Decode(&policies); err != nil && err != io.EOF {
+		return nil, err
+	}
+	for i, policy := range policies {
+		if err := policy.validate(); err != nil {
+			return nil, fmt.Errorf("policy %d: %v", i+1, err)
+		}
+	}
+	return policies, nil
+}
+
+func (p QueuePolicy) validate() error {
+	if p.Action != QueuePolicyRemove && p.Action != QueuePolicyRetry {
+		return fmt.Errorf("unknown action %q", p.Action)
+	}
+	for _, pattern := range []string{p.SenderPattern, p.RecipientPattern, p.RecipientDomain, p.AuthSender, p.SenderIp} {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q", pattern)
+		}
+	}
+	if _, err := parseAge(p.OlderThan); err != nil {
+		return err
+	}
+	if p.SenderPattern == "" && p.RecipientPattern == "" && p.RecipientDomain == "" && p.AuthSender == "" &&
+		p.SenderIp == "" && p.Status == "" && p.OlderThan == "" {
+		return fmt.Errorf("policy %q has no condition", p.Name)
+	}
+	return nil
+}
+
+// Matches - Return true if the message satisfies all conditions of the policy
+func (p QueuePolicy) Matches(message QueueMessage) bool {
+	for _, condition := range []struct {
+		pattern string
+		value   string
+	}{
+		{p.SenderPattern, message.From},
+		{p.RecipientPattern, message.To},
+		{p.RecipientDomain, message.RecipientDomain},
+		{p.AuthSender, message.AuthSender},
+		{p.SenderIp, message.SenderIp},
+	} {
+		if condition.pattern == "" {
+			continue
+		}
+		if ok, _ := filepath.Match(strings.ToLower(condition.pattern), strings.ToLower(condition.value)); !ok {
+			return false
+		}
+	}
+	if p.Status != "" && !strings.Contains(strings.ToLower(message.Status), strings.ToLower(p.Status)) {
+		return false
+	}
+	if age, _ := parseAge(p.OlderThan); age > 0 && message.Age < age {
+		return false
+	}
+	return true
+}
+
+// QueuePolicyOptions - Options of ApplyQueuePolicies
+type QueuePolicyOptions struct {
+	DryRun bool      // only report matching messages
+	Audit  io.Writer // audit log in JSON lines; nil disables it
+}
+
+// QueuePolicyResult - What the policy did
+type QueuePolicyResult struct {
+	Time    time.Time         `json:"time"`
+	Policy  string            `json:"policy"`
+	Action  QueuePolicyAction `json:"action"`
+	DryRun  bool              `json:"dryRun"`
+	Matched KIdList           `json:"matched"`           // messages matching the policy
+	Applied int               `json:"applied"`           // messages removed or retried
+	Skipped string            `json:"skipped,omitempty"` // why the policy was not applied
+	Error   string            `json:"error,omitempty"`   // error of the server
+}
+
+// QueuePolicyResultList - Results of policies
+type QueuePolicyResultList []QueuePolicyResult
+
+// ApplyQueuePolicies - Apply policies to messages of the snapshot and write the audit log.
+//	snapshot - queue snapshot from TakeQueueSnapshot
+//	policies - policies applied in order
+//	options - dry run and audit log; nil means apply without audit
+// Return
+//	results - result of each policy
+func (s *ServerConnection) ApplyQueuePolicies(snapshot *QueueSnapshot, policies QueuePolicyList, options *QueuePolicyOptions) (QueuePolicyResultList, error) {
+	var o QueuePolicyOptions
+	if options != nil {
+		o = *options
+	}
+	handled := map[KId]bool{}
+	results := make(QueuePolicyResultList, 0, len(policies))
+	failed := 0
+	for _, policy := range policies {
+		result := QueuePolicyResult{Time: time.Now(), Policy: policy.Name, Action: policy.Action, DryRun: o.DryRun, Matched: KIdList{}}
+		if err := policy.validate(); err != nil {
+			result.Skipped = err.Error()
+		} else {
+			for _, message := range snapshot.Messages {
+				if !handled[message.Id] && policy.Matches(message) {
+					result.Matched = append(result.Matched, message.Id)
+				}
+			}
+			applies := false
+			switch {
+			case len(result.Matched) == 0:
+				result.Skipped = "no message matches"
+			case policy.MinMessages > 0 && len(result.Matched) < policy.MinMessages:
+				result.Skipped = fmt.Sprintf("%d messages match, policy applies from %d", len(result.Matched), policy.MinMessages)
+			case policy.MaxMessages > 0 && len(result.Matched) > policy.MaxMessages:
+				result.Skipped = fmt.Sprintf("%d messages match, policy refuses more than %d", len(result.Matched), policy.MaxMessages)
+			case o.DryRun:
+				result.Skipped = "dry run"
+				applies = true
+			default:
+				applies = true
+				if err := s.applyQueuePolicy(policy, &result); err != nil {
+					result.Error = err.Error()
+					failed++
+				}
+			}
+			if applies {
+				for _, id := range result.Matched {
+					handled[id] = true
+				}
+			}
+		}
+		if o.Audit != nil {
+			if err := json.NewEncoder(o.Audit).Encode(result); err != nil {
+				return results, err
+			}
+		}
+		results = append(results, result)
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d queue policies failed", failed, len(policies))
+	}
+	return results, nil
+}
+
+func (s *ServerConnection) applyQueuePolicy(policy QueuePolicy, result *QueuePolicyResult) error {
+	if policy.Action == QueuePolicyRemove {
+		removed, err := s.QueueRemove(result.Matched)
+		result.Applied = removed
+		return err
+	}
+	if err := s.QueueTryToSend(result.Matched); err != nil {
+		return err
+	}
+	result.Applied = len(result.Matched)
+	return nil
+}
+
+// parseAge parses duration with days, e.g. 5d, 12h or 90m; empty string is zero
+func parseAge(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.ParseFloat(strings.TrimSuffix(value, "d"), 64)
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("invalid age %q", value)
+		}
+		return time.Duration(days * float64(24*time.Hour)), nil
+	}
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("invalid age %q", value)
+	}
+	return age, nil
+}
@@ -8,9 +8,11 @@ import (
 )
 
 type ServerConnection struct {
-	Config *Config
-	Token  *string
-	client *http.Client
+	Config      *Config
+	Token       *string
+	StrictEnums bool // decoding of an unknown enum value from the server fails with UnknownEnumError if set
+	client      *http.Client
+	enums       enumReport
 }
 
 func (c *Config) NewConnection() (*ServerConnection, error) {
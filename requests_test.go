@@ -0,0 +1,100 @@
+package connect
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeHandler - returns the "result" member of JSON-RPC response for given params
+type fakeHandler func(params json.RawMessage) interface{}
+
+// fakeServer - JSON-RPC server answering with registered handlers and recording calls
+type fakeServer struct {
+	mu       sync.Mutex
+	handlers map[string]fakeHandler
+	calls    []string
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	request := struct {
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}{}
+	if r.Method == http.MethodGet {
+		// downloads are answered by the "download" handler returning file content as string for JSON string with the path
+		f.mu.Lock()
+		f.calls = append(f.calls, "download")
+		handler, ok := f.handlers["download"]
+		f.mu.Unlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		path, _ := json.Marshal(r.URL.Path)
+		_, _ = w.Write([]byte(handler(path).(string)))
+		return
+	}
+	if strings.HasSuffix(r.URL.Path, "/upload/") {
+		// uploads are passed to the "upload" handler as JSON string with the file content
+		file, _, err := r.FormFile("newFile")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		content, _ := ioutil.ReadAll(file)
+		request.Method = "upload"
+		request.Params, _ = json.Marshal(string(content))
+	} else if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f.mu.Lock()
+	f.calls = append(f.calls, request.Method)
+	handler, ok := f.handlers[request.Method]
+	f.mu.Unlock()
+	var response interface{}
+	if ok {
+		response = map[string]interface{}{"jsonrpc": "2.0", "result": handler(request.Params)}
+	} else {
+		response = map[string]interface{}{"jsonrpc": "2.0", "error": map[string]interface{}{"code": -32601, "message": "Method not found: " + request.Method}}
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// called returns how many times the method was called
+func (f *fakeServer) called(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	count := 0
+	for _, call := range f.calls {
+		if call == method {
+			count++
+		}
+	}
+	return count
+}
+
+// newFakeConnection starts a fake server with given handlers and returns connection to it
+func newFakeConnection(t *testing.T, handlers map[string]fakeHandler) (*ServerConnection, *fakeServer) {
+	t.Helper()
+	fake := &fakeServer{handlers: handlers}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	client, err := newClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &ServerConnection{Config: &Config{url: server.URL}, client: client}, fake
+}
+
+// result returns a handler with constant result
+func result(value interface{}) fakeHandler {
+	return func(json.RawMessage) interface{} {
+		return value
+	}
+}
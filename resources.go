@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 // PrincipalType - Principal type
 type PrincipalType string
 
@@ -76,7 +74,7 @@ func (s *ServerConnection) ResourcesCreate(resources ResourceList) (ErrorList, C
 			Result CreateResultList `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, errors.Result.Result, err
 }
 
@@ -102,7 +100,7 @@ func (s *ServerConnection) ResourcesGet(query SearchQuery, domainId KId) (Resour
 			TotalItems int          `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -127,7 +125,7 @@ func (s *ServerConnection) ResourcesGetPrincipalList(query SearchQuery, domainId
 			TotalItems int           `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -148,7 +146,7 @@ func (s *ServerConnection) ResourcesRemove(resourceIds KIdList) (ErrorList, erro
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -171,6 +169,6 @@ func (s *ServerConnection) ResourcesSet(resourceIds KIdList, pattern Resource) (
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
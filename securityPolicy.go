@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 type SecurityPolicyMode string
 
 const (
@@ -44,7 +42,7 @@ func (s *ServerConnection) SecurityPolicyGet() (*SecurityPolicyOptions, error) {
 			Options SecurityPolicyOptions `json:"options"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &options)
+	err = s.unmarshal(data, &options)
 	return &options.Result.Options, err
 }
 
@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 type SenderPolicyOptions struct {
 	AuthenticationRequired bool           `json:"authenticationRequired"` // Require sender authentication for local domains
 	AntiSpoofingEnabled    bool           `json:"antiSpoofingEnabled"`    // Is Antispoofing enabled
@@ -21,7 +19,7 @@ func (s *ServerConnection) SenderPolicyGet() (*SenderPolicyOptions, error) {
 			Options SenderPolicyOptions `json:"options"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &options)
+	err = s.unmarshal(data, &options)
 	return &options.Result.Options, err
 }
 
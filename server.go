@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 // Entity - Available entities, entity prefix due to name collision
 type Entity string
 
@@ -151,22 +149,22 @@ type WebSessionList []WebSession
 type Protocol string
 
 const (
-	protocolAdmin      Protocol = "protocolAdmin"
-	protocolSmtp       Protocol = "protocolSmtp"
-	protocolSmtps      Protocol = "protocolSmtps"
-	protocolSubmission Protocol = "protocolSubmission"
-	protocolPop3       Protocol = "protocolPop3"
-	protocolPop3s      Protocol = "protocolPop3s"
-	protocolImap       Protocol = "protocolImap"
-	protocolImaps      Protocol = "protocolImaps"
-	protocolNntp       Protocol = "protocolNntp"
-	protocolNntps      Protocol = "protocolNntps"
-	protocolLdap       Protocol = "protocolLdap"
-	protocolLdaps      Protocol = "protocolLdaps"
-	protocolHttp       Protocol = "protocolHttp"
-	protocolHttps      Protocol = "protocolHttps"
-	protocolXmpp       Protocol = "protocolXmpp"
-	protocolXmpps      Protocol = "protocolXmpps"
+	ProtocolAdmin      Protocol = "protocolAdmin"
+	ProtocolSmtp       Protocol = "protocolSmtp"
+	ProtocolSmtps      Protocol = "protocolSmtps"
+	ProtocolSubmission Protocol = "protocolSubmission"
+	ProtocolPop3       Protocol = "protocolPop3"
+	ProtocolPop3s      Protocol = "protocolPop3s"
+	ProtocolImap       Protocol = "protocolImap"
+	ProtocolImaps      Protocol = "protocolImaps"
+	ProtocolNntp       Protocol = "protocolNntp"
+	ProtocolNntps      Protocol = "protocolNntps"
+	ProtocolLdap       Protocol = "protocolLdap"
+	ProtocolLdaps      Protocol = "protocolLdaps"
+	ProtocolHttp       Protocol = "protocolHttp"
+	ProtocolHttps      Protocol = "protocolHttps"
+	ProtocolXmpp       Protocol = "protocolXmpp"
+	ProtocolXmpps      Protocol = "protocolXmpps"
 )
 
 type HttpExtension string
@@ -233,7 +231,7 @@ func (s *ServerConnection) ServerCreatePath(path string, credentials Credentials
 			Result DirectoryAccessResult `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &result)
+	err = s.unmarshal(data, &result)
 	return &result.Result.Result, err
 }
 
@@ -258,7 +256,7 @@ func (s *ServerConnection) ServerFindEntityByEmail(addresses StringList, updated
 			Entities EntityDuplicateList `json:"entities"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &entities)
+	err = s.unmarshal(data, &entities)
 	return entities.Result.Entities, err
 }
 
@@ -275,7 +273,7 @@ func (s *ServerConnection) ServerGenerateSupportInfo() (*Download, error) {
 			FileDownload Download `json:"fileDownload"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &fileDownload)
+	err = s.unmarshal(data, &fileDownload)
 	return &fileDownload.Result.FileDownload, err
 }
 
@@ -292,7 +290,7 @@ func (s *ServerConnection) ServerGetAboutInfo() (*AboutInfo, error) {
 			AboutInformation AboutInfo `json:"aboutInformation"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &aboutInformation)
+	err = s.unmarshal(data, &aboutInformation)
 	return &aboutInformation.Result.AboutInformation, err
 }
 
@@ -309,7 +307,7 @@ func (s *ServerConnection) ServerGetAlertList() (AlertList, error) {
 			Alerts AlertList `json:"alerts"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &alerts)
+	err = s.unmarshal(data, &alerts)
 	return alerts.Result.Alerts, err
 }
 
@@ -326,7 +324,7 @@ func (s *ServerConnection) ServerGetBrowserLanguages() (StringList, error) {
 			CalculatedLanguage StringList `json:"calculatedLanguage"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &calculatedLanguage)
+	err = s.unmarshal(data, &calculatedLanguage)
 	return calculatedLanguage.Result.CalculatedLanguage, err
 }
 
@@ -341,7 +339,7 @@ func (s *ServerConnection) ServerGetClientStatistics() (bool, error) {
 			IsEnabled bool `json:"isEnabled"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &isEnabled)
+	err = s.unmarshal(data, &isEnabled)
 	return isEnabled.Result.IsEnabled, err
 }
 
@@ -364,7 +362,7 @@ func (s *ServerConnection) ServerGetColumnList(objectName string, methodName str
 			Columns StringList `json:"columns"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &columns)
+	err = s.unmarshal(data, &columns)
 	return columns.Result.Columns, err
 }
 
@@ -388,7 +386,7 @@ func (s *ServerConnection) ServerGetConnections(query SearchQuery) (ConnectionLi
 			TotalItems int            `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -409,7 +407,7 @@ func (s *ServerConnection) ServerGetDirs(fullPath string) (DirectoryList, error)
 			DirList DirectoryList `json:"dirList"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &dirList)
+	err = s.unmarshal(data, &dirList)
 	return dirList.Result.DirList, err
 }
 
@@ -426,7 +424,7 @@ func (s *ServerConnection) ServerGetLicenseExtensionsList() (StringList, error)
 			Extensions StringList `json:"extensions"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &extensions)
+	err = s.unmarshal(data, &extensions)
 	return extensions.Result.Extensions, err
 }
 
@@ -443,7 +441,7 @@ func (s *ServerConnection) ServerGetNamedConstantList() (NamedConstantList, erro
 			Constants NamedConstantList `json:"constants"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &constants)
+	err = s.unmarshal(data, &constants)
 	return constants.Result.Constants, err
 }
 
@@ -467,7 +465,7 @@ func (s *ServerConnection) ServerGetOpenedFoldersInfo(query SearchQuery) (Folder
 			TotalItems int            `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -484,7 +482,7 @@ func (s *ServerConnection) ServerGetProductInfo() (*ProductInfo, error) {
 			Info ProductInfo `json:"info"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &info)
+	err = s.unmarshal(data, &info)
 	return &info.Result.Info, err
 }
 
@@ -501,7 +499,7 @@ func (s *ServerConnection) ServerGetRemoteAdministration() (*Administration, err
 			Setting Administration `json:"setting"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &setting)
+	err = s.unmarshal(data, &setting)
 	return &setting.Result.Setting, err
 }
 
@@ -518,7 +516,7 @@ func (s *ServerConnection) ServerGetServerHash() (string, error) {
 			ServerHash string `json:"serverHash"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &serverHash)
+	err = s.unmarshal(data, &serverHash)
 	return serverHash.Result.ServerHash, err
 }
 
@@ -535,7 +533,7 @@ func (s *ServerConnection) ServerGetServerIpAddresses() (StringList, error) {
 			Addresses StringList `json:"addresses"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &addresses)
+	err = s.unmarshal(data, &addresses)
 	return addresses.Result.Addresses, err
 }
 
@@ -552,7 +550,7 @@ func (s *ServerConnection) ServerGetServerTime() (*ServerTimeInfo, error) {
 			Info ServerTimeInfo `json:"info"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &info)
+	err = s.unmarshal(data, &info)
 	return &info.Result.Info, err
 }
 
@@ -574,7 +572,7 @@ func (s *ServerConnection) ServerGetVersion() (*ServerVersion, error) {
 			ServerVersion
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &serverVersion)
+	err = s.unmarshal(data, &serverVersion)
 	return &serverVersion.Result.ServerVersion, err
 }
 
@@ -598,7 +596,7 @@ func (s *ServerConnection) ServerGetWebSessions(query SearchQuery) (WebSessionLi
 			TotalItems int            `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -631,7 +629,7 @@ func (s *ServerConnection) ServerPathExists(path string, credentials Credentials
 			Result DirectoryAccessResult `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &result)
+	err = s.unmarshal(data, &result)
 	return result.Result.Result, err
 }
 
@@ -666,7 +664,7 @@ func (s *ServerConnection) ServerGetDownloadProgress() (int, error) {
 			Progress int `json:"progress"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &progress)
+	err = s.unmarshal(data, &progress)
 	return progress.Result.Progress, err
 }
 
@@ -740,6 +738,6 @@ func (s *ServerConnection) ServerIsBritishPreferred() (bool, error) {
 			Preferred bool `json:"preferred"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &preferred)
+	err = s.unmarshal(data, &preferred)
 	return preferred.Result.Preferred, err
 }
@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 // StartUp - type of start-up
 type StartUp string
 
@@ -66,7 +64,7 @@ func (s *ServerConnection) ServicesGet() (ServiceList, error) {
 			Services ServiceList `json:"services"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &services)
+	err = s.unmarshal(data, &services)
 	return services.Result.Services, err
 }
 
@@ -97,7 +95,7 @@ func (s *ServerConnection) ServicesSet(services ServiceList) (ErrorList, error)
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -138,7 +136,7 @@ func (s *ServerConnection) ServicesGetIPv6() (bool, error) {
 			IsEnabled bool `json:"isEnabled"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &isEnabled)
+	err = s.unmarshal(data, &isEnabled)
 	return isEnabled.Result.IsEnabled, err
 }
 
@@ -1,9 +1,5 @@
 package connect
 
-import (
-	"encoding/json"
-)
-
 type UserDetails struct {
 	ID            string `json:"id"`
 	DomainID      string `json:"domainId"`
@@ -65,7 +61,7 @@ func (s *ServerConnection) Login(user, password string, app *ApiApplication) err
 			Token string `json:"token"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &token)
+	err = s.unmarshal(data, &token)
 	if err != nil {
 		return err
 	}
@@ -91,7 +87,7 @@ func (s *ServerConnection) SessionWhoAmI() (*UserDetails, error) {
 			UserDetails `json:"userDetails"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &userDetails)
+	err = s.unmarshal(data, &userDetails)
 	return &userDetails.Result.UserDetails, err
 }
 
@@ -107,6 +103,6 @@ func (s *ServerConnection) SessionGetDomain() (*Domain, error) {
 			Domain `json:"domain"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &domain)
+	err = s.unmarshal(data, &domain)
 	return &domain.Result.Domain, err
 }
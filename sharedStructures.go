@@ -1,5 +1,7 @@
 package connect
 
+import "time"
+
 // StringList - Type for lists of strings.
 type StringList []string
 
@@ -159,6 +161,22 @@ type ByteValueWithUnits struct {
 	Units ByteUnits `json:"units"`
 }
 
+// ByteCount - Return the value converted to bytes
+func (b ByteValueWithUnits) ByteCount() int64 {
+	multiplier := map[ByteUnits]int64{
+		Bytes:     1,
+		KiloBytes: 1 << 10,
+		MegaBytes: 1 << 20,
+		GigaBytes: 1 << 30,
+		TeraBytes: 1 << 40,
+		PetaBytes: 1 << 50,
+	}[b.Units]
+	if multiplier == 0 {
+		multiplier = 1
+	}
+	return int64(b.Value) * multiplier
+}
+
 // SizeLimit - Settings of size limit
 // Note: all fields must be assigned if used in set methods
 type SizeLimit struct {
@@ -212,6 +230,17 @@ type Date struct {
 	Day   int `json:"day"`   // 1-31 max day is limited by month
 }
 
+// dateTime converts date with zero-based month and time of the server
+func dateTime(d Date, t Time, location *time.Location) time.Time {
+	if location == nil {
+		location = time.UTC
+	}
+	if d.Year == 0 {
+		return time.Time{}
+	}
+	return time.Date(d.Year, time.Month(d.Month+1), d.Day, t.Hour, t.Min, 0, 0, location)
+}
+
 // OptionalString - A string that can be switched on/off. String is meaningful only if switched on.
 // Note: all fields must be assigned if used in set methods
 type OptionalString struct {
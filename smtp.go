@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 type SmtpAuthentication string
 
 const (
@@ -114,7 +112,7 @@ func (s *ServerConnection) SmtpGet() (*SmtpServerSettings, error) {
 			Server SmtpServerSettings `json:"server"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &server)
+	err = s.unmarshal(data, &server)
 	return &server.Result.Server, err
 }
 
@@ -139,7 +137,7 @@ func (s *ServerConnection) SmtpGetRelayDeliveryRuleList() (RelayDeliveryRuleList
 			List RelayDeliveryRuleList `json:"list"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, err
 }
 
@@ -163,7 +161,7 @@ func (s *ServerConnection) SmtpGetIncomingRuleList() (DeliveryRuleList, error) {
 			List DeliveryRuleList `json:"list"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, err
 }
 
@@ -187,7 +185,7 @@ func (s *ServerConnection) SmtpGetOutgoingRuleList() (DeliveryRuleList, error) {
 			List DeliveryRuleList `json:"list"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, err
 }
 
@@ -1,12 +1,10 @@
 package connect
 
-import "encoding/json"
-
 type TypeExpStatistics string
 
 const (
-	expStatShort TypeExpStatistics = "expStatShort"
-	expStatFull  TypeExpStatistics = "expStatFull"
+	ExpStatShort TypeExpStatistics = "expStatShort"
+	ExpStatFull  TypeExpStatistics = "expStatFull"
 )
 
 type OccupiedStorage struct {
@@ -203,7 +201,7 @@ func (s *ServerConnection) StatisticsExportToHtml(typeExpStatistics TypeExpStati
 			FileDownload Download `json:"fileDownload"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &fileDownload)
+	err = s.unmarshal(data, &fileDownload)
 	return &fileDownload.Result.FileDownload, err
 }
 
@@ -218,7 +216,7 @@ func (s *ServerConnection) StatisticsGet() (*ServerStatistics, error) {
 			Statistics ServerStatistics `json:"statistics"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &statistics)
+	err = s.unmarshal(data, &statistics)
 	return &statistics.Result.Statistics, err
 }
 
@@ -235,7 +233,7 @@ func (s *ServerConnection) StatisticsGetCharts() (ChartList, error) {
 			ChartList ChartList `json:"chartList"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &chartList)
+	err = s.unmarshal(data, &chartList)
 	return chartList.Result.ChartList, err
 }
 
@@ -260,7 +258,7 @@ func (s *ServerConnection) StatisticsGetChartData(classname string, name string,
 			ChartData ChartData `json:"chartData"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &chartData)
+	err = s.unmarshal(data, &chartData)
 	return &chartData.Result.ChartData, err
 }
 
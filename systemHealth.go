@@ -1,6 +1,6 @@
 package connect
 
-import "encoding/json"
+import "time"
 
 type HistogramType string
 
@@ -20,6 +20,21 @@ const (
 	HistogramInterval2h  HistogramIntervalType = "HistogramInterval2h"  // Data interval: 2h,  Max samples: 372, Length 1Month
 )
 
+// Duration - Time between two samples of the histogram; 0 for unknown interval
+func (t HistogramIntervalType) Duration() time.Duration {
+	switch t {
+	case HistogramInterval20s:
+		return 20 * time.Second
+	case HistogramInterval5m:
+		return 5 * time.Minute
+	case HistogramInterval30m:
+		return 30 * time.Minute
+	case HistogramInterval2h:
+		return 2 * time.Hour
+	}
+	return 0
+}
+
 // PercentHistogram - 0-100%, sample count and rate depens on requested type and is the same as in ActiveHost histogram
 type PercentHistogram []float64
 
@@ -46,7 +61,7 @@ func (s *ServerConnection) SystemHealthGet(histogramType HistogramType) (*System
 			Data SystemHealthData `json:"data"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &systemHealthData)
+	err = s.unmarshal(data, &systemHealthData)
 	return &systemHealthData.Result.Data, err
 }
 
@@ -66,6 +81,6 @@ func (s *ServerConnection) SystemHealthGetInc(histogramIntervalType HistogramInt
 			SampleTime DateTimeStamp    `json:"sampleTime"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &systemHealthData)
+	err = s.unmarshal(data, &systemHealthData)
 	return &systemHealthData.Result.Data, &systemHealthData.Result.SampleTime, err
 }
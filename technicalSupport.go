@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 // UserInfo - A contact to user
 type UserInfo struct {
 	Name  string `json:"name"`
@@ -45,7 +43,7 @@ func (s *ServerConnection) TechnicalSupportGetInfo() (*UserInfo, *SupportProduct
 			IsUploadServerAvailable bool               `json:"isUploadServerAvailable"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &userInfo)
+	err = s.unmarshal(data, &userInfo)
 	return &userInfo.Result.UserInfo, &userInfo.Result.ProductInfo, &userInfo.Result.SystemInfo, userInfo.Result.IsUploadServerAvailable, err
 }
 
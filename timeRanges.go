@@ -1,6 +1,10 @@
 package connect
 
-import "encoding/json"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // @defgroup SUBGROUP4 Definitions
 
@@ -16,6 +20,17 @@ const (
 
 type UtcDateTime string
 
+// Time - Parse the date and time. The server sends it in UTC, with or without a time zone designator.
+func (t UtcDateTime) Time() (time.Time, error) {
+	value := strings.TrimSpace(string(t))
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"} {
+		if parsed, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date and time %q", value)
+}
+
 type DayType string
 
 const (
@@ -82,7 +97,7 @@ func (s *ServerConnection) TimeRangesCreate(ranges TimeRangeEntryList) (ErrorLis
 			Result CreateResultList `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, errors.Result.Result, err
 }
 
@@ -106,7 +121,7 @@ func (s *ServerConnection) TimeRangesGet(query SearchQuery) (TimeRangeEntryList,
 			TotalItems int                `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -123,7 +138,7 @@ func (s *ServerConnection) TimeRangesGetGroupList() (TimeRangeGroupList, error)
 			Groups TimeRangeGroupList `json:"groups"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &groups)
+	err = s.unmarshal(data, &groups)
 	return groups.Result.Groups, err
 }
 
@@ -144,7 +159,7 @@ func (s *ServerConnection) TimeRangesRemove(rangeIds KIdList) (ErrorList, error)
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -167,6 +182,6 @@ func (s *ServerConnection) TimeRangesSet(rangeIds KIdList, details TimeRangeEntr
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
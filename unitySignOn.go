@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 type SignOn struct {
 	IsEnabled bool   `json:"isEnabled"`
 	HostName  string `json:"hostName"` // Hostname to the Kerio Unity Sign On server. Non default port can be added Eg: example.com:4444
@@ -22,7 +20,7 @@ func (s *ServerConnection) UnitySignOnGet() (*SignOn, error) {
 			Settings SignOn `json:"settings"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &settings)
+	err = s.unmarshal(data, &settings)
 	return &settings.Result.Settings, err
 }
 
@@ -55,6 +53,6 @@ func (s *ServerConnection) UnitySignOnTestConnection(hostNames StringList, crede
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
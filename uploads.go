@@ -1,6 +1,12 @@
 package connect
 
-import "encoding/json"
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+)
 
 // Upload management
 
@@ -31,6 +37,60 @@ func (s *ServerConnection) UploadsRemoveList(ids StringList) (ErrorList, error)
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
+
+// UploadFile - Upload file to the server, e.g. for import of certificate.
+//	name - file name
+//	content - file content
+// Return
+//	id - identifier of uploaded file used by import methods as fileId
+func (s *ServerConnection) UploadFile(name string, content []byte) (string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("newFile", name)
+	if err != nil {
+		return "", err
+	}
+	if _, err = part.Write(content); err != nil {
+		return "", err
+	}
+	if err = writer.Close(); err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest("POST", s.Config.url+"/upload/", body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if s.Token != nil {
+		req.Header.Add("X-Token", *s.Token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if err = checkError(data); err != nil {
+		return "", err
+	}
+	upload := struct {
+		Result struct {
+			FileUpload struct {
+				Id string `json:"id"`
+			} `json:"fileUpload"`
+		} `json:"result"`
+	}{}
+	if err = s.unmarshal(data, &upload); err != nil {
+		return "", err
+	}
+	if upload.Result.FileUpload.Id == "" {
+		return "", fmt.Errorf("upload of %s failed: %s", name, resp.Status)
+	}
+	return upload.Result.FileUpload.Id, nil
+}
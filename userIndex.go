@@ -0,0 +1,198 @@
+package connect
+
+import "strings"
+
+// userIndex - In-memory lookup of domain users by login name and email address
+type userIndex struct {
+	domain  Domain
+	users   UserList
+	byId    map[KId]int
+	byLogin map[string]int
+	byEmail map[string]int
+}
+
+// newUserIndex loads all users of the domain and indexes them by id, login name and every address they receive mail for
+func (s *ServerConnection) newUserIndex(domain Domain) (*userIndex, error) {
+	users, _, err := s.UsersGet(SearchQuery{}, domain.Id)
+	if err != nil {
+		return nil, err
+	}
+	index := &userIndex{
+		domain:  domain,
+		users:   users,
+		byId:    make(map[KId]int, len(users)),
+		byLogin: make(map[string]int, len(users)),
+		byEmail: make(map[string]int, len(users)),
+	}
+	for i, user := range users {
+		index.byId[user.Id] = i
+		index.byLogin[strings.ToLower(user.LoginName)] = i
+		for _, address := range index.addresses(user) {
+			index.byEmail[address] = i
+		}
+	}
+	return index, nil
+}
+
+// domainNames returns the domain name followed by its aliases, all in lower case
+func (x *userIndex) domainNames() StringList {
+	names := StringList{strings.ToLower(x.domain.Name)}
+	for _, alias := range x.domain.AliasList {
+		names = append(names, strings.ToLower(alias))
+	}
+	return names
+}
+
+// isLocal reports whether the domain part of address belongs to the indexed domain
+func (x *userIndex) isLocal(address string) bool {
+	_, domain := splitAddress(address)
+	for _, name := range x.domainNames() {
+		if domain == name {
+			return true
+		}
+	}
+	return false
+}
+
+// addresses returns all lower-cased addresses of the user, including the default one and domain alias variants
+func (x *userIndex) addresses(user User) StringList {
+	locals := StringList{strings.ToLower(user.LoginName)}
+	var result StringList
+	for _, address := range user.EmailAddresses {
+		address = strings.ToLower(address)
+		if strings.Contains(address, "@") {
+			result = append(result, address)
+			continue
+		}
+		locals = append(locals, address)
+	}
+	for _, local := range locals {
+		for _, domain := range x.domainNames() {
+			result = append(result, local+"@"+domain)
+		}
+	}
+	return result
+}
+
+// primaryAddress returns loginName@domain of the user
+func (x *userIndex) primaryAddress(user User) string {
+	return strings.ToLower(user.LoginName + "@" + x.domain.Name)
+}
+
+// user returns the user with given id
+func (x *userIndex) user(id KId) (User, bool) {
+	i, ok := x.byId[id]
+	if !ok {
+		return User{}, false
+	}
+	return x.users[i], true
+}
+
+// lookup finds the user by login name or by any of his addresses
+func (x *userIndex) lookup(identifier string) (User, bool) {
+	identifier = strings.ToLower(strings.TrimSpace(identifier))
+	i, ok := x.byLogin[identifier]
+	if !ok {
+		i, ok = x.byEmail[identifier]
+	}
+	if !ok {
+		return User{}, false
+	}
+	return x.users[i], true
+}
+
+// resolveUser finds the user by login name or address. Addresses unknown to the index (e.g. aliases)
+// are checked on the server with ServerFindEntityByEmail.
+func (s *ServerConnection) resolveUser(x *userIndex, identifier string) (User, bool, error) {
+	if user, ok := x.lookup(identifier); ok {
+		return user, true, nil
+	}
+	if !strings.Contains(identifier, "@") || !x.isLocal(identifier) {
+		return User{}, false, nil
+	}
+	local, _ := splitAddress(identifier)
+	entities, err := s.ServerFindEntityByEmail(StringList{local}, EntityDetail{Kind: EntityUser}, x.domain.Id)
+	if err != nil {
+		return User{}, false, err
+	}
+	for _, entity := range entities {
+		if entity.Kind != EntityUser || entity.IsPattern {
+			continue
+		}
+		name, _ := splitAddress(entity.Name)
+		if user, ok := x.lookup(name); ok {
+			return user, true, nil
+		}
+	}
+	return User{}, false, nil
+}
+
+// splitAddress splits the lower-cased address into local and domain parts
+func splitAddress(address string) (string, string) {
+	address = strings.ToLower(strings.TrimSpace(address))
+	i := strings.LastIndex(address, "@")
+	if i < 0 {
+		return address, ""
+	}
+	return address[:i], address[i+1:]
+}
+
+// userIndexList - User indexes of several domains
+type userIndexList []*userIndex
+
+// newUserIndexList indexes users of all domains
+func (s *ServerConnection) newUserIndexList() (userIndexList, error) {
+	domains, _, err := s.DomainsGet(SearchQuery{})
+	if err != nil {
+		return nil, err
+	}
+	list := make(userIndexList, 0, len(domains))
+	for _, domain := range domains {
+		index, err := s.newUserIndex(domain)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, index)
+	}
+	return list, nil
+}
+
+// forAddress returns the index of domain which the address belongs to, or nil for external addresses
+func (l userIndexList) forAddress(address string) *userIndex {
+	for _, index := range l {
+		if index.isLocal(address) {
+			return index
+		}
+	}
+	return nil
+}
+
+// forDomain returns the index of the domain with given id
+func (l userIndexList) forDomain(domainId KId) *userIndex {
+	for _, index := range l {
+		if index.domain.Id == domainId {
+			return index
+		}
+	}
+	return nil
+}
+
+// user finds the user with given id in any domain
+func (l userIndexList) user(id KId) (*userIndex, User, bool) {
+	for _, index := range l {
+		if user, ok := index.user(id); ok {
+			return index, user, true
+		}
+	}
+	return nil, User{}, false
+}
+
+// qualify converts the address to lower case, appends the domain name to a bare local part
+// and replaces domain alias with the domain name
+func (x *userIndex) qualify(address string) string {
+	local, domain := splitAddress(address)
+	if domain == "" || x.isLocal(address) {
+		domain = strings.ToLower(x.domain.Name)
+	}
+	return local + "@" + domain
+}
@@ -0,0 +1,548 @@
+package connect
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OldAddressMode - How to keep the old address of renamed or moved user
+type OldAddressMode string
+
+const (
+	KeepAsAlias        OldAddressMode = "KeepAsAlias"        // create alias in the old domain delivering to the new address
+	KeepAsEmailAddress OldAddressMode = "KeepAsEmailAddress" // add old login name to User.EmailAddresses; renames within domain only, moves fall back to alias
+	DropOldAddress     OldAddressMode = "DropOldAddress"     // old address stops receiving mail
+)
+
+// UserMoveActionType - Side effect of user rename or domain move
+type UserMoveActionType string
+
+const (
+	MoveRenameUser        UserMoveActionType = "MoveRenameUser"        // change login name of the user
+	MoveCreateUser        UserMoveActionType = "MoveCreateUser"        // create copy of the user in the target domain
+	MoveAddGroupMember    UserMoveActionType = "MoveAddGroupMember"    // add the new user to a group of the target domain
+	MoveReplaceMlMember   UserMoveActionType = "MoveReplaceMlMember"   // replace the old user by the new one in a mailing list
+	MoveSetCompanyContact UserMoveActionType = "MoveSetCompanyContact" // link the new user with a company contact
+	MoveMoveMailbox       UserMoveActionType = "MoveMoveMailbox"       // remove the old user and move his mailbox to the new one
+	MoveAddAlias          UserMoveActionType = "MoveAddAlias"          // create alias for an old address
+	MoveAddEmailAddress   UserMoveActionType = "MoveAddEmailAddress"   // keep an old address in User.EmailAddresses
+)
+
+// UserMoveRequest - Login name change and/or move of a user to another domain
+type UserMoveRequest struct {
+	UserId       KId    `json:"userId"`       // global user identification
+	NewLoginName string `json:"newLoginName"` // empty keeps current login name
+	NewDomainId  KId    `json:"newDomainId"`  // empty keeps current domain
+}
+
+// UserMoveRequestList - List of user move requests
+type UserMoveRequestList []UserMoveRequest
+
+// UserMoveOptions - Options of user renames and moves
+type UserMoveOptions struct {
+	OldAddress OldAddressMode // how to keep old addresses; empty means KeepAsAlias
+	Password   string         // password of users recreated in another domain; empty means generated by DomainsGeneratePassword
+}
+
+// UserMoveAction - Planned side effect
+type UserMoveAction struct {
+	Type        UserMoveActionType `json:"type"`
+	Description string             `json:"description"`       // human readable description
+	Id          KId                `json:"id,omitempty"`      // group, ML or company contact the action works with
+	Address     string             `json:"address,omitempty"` // alias name or email address
+	Member      *UserOrEmail       `json:"member,omitempty"`  // ML membership being replaced
+}
+
+// UserMoveActionList - List of planned side effects in order of execution
+type UserMoveActionList []UserMoveAction
+
+// UserMovePlan - All side effects of one rename or move. The plan holds everything ApplyUserMoves needs,
+// so it can be saved as JSON for review and applied later.
+type UserMovePlan struct {
+	Request    UserMoveRequest    `json:"request"`
+	OldAddress string             `json:"oldAddress"` // primary address before the change
+	NewAddress string             `json:"newAddress"` // primary address after the change
+	Actions    UserMoveActionList `json:"actions"`    // side effects in order of execution
+	Conflicts  StringList         `json:"conflicts"`  // addresses already used by other entities, the plan cannot be applied
+	Warnings   StringList         `json:"warnings"`   // things which cannot be preserved, e.g. group missing in target domain
+	User       User               `json:"user"`       // the user at the time of planning
+	Domain     Domain             `json:"domain"`     // current domain of the user
+	Target     Domain             `json:"target"`     // domain of the user after the change
+}
+
+// UserMovePlanList - List of user move plans
+type UserMovePlanList []UserMovePlan
+
+// IsMove returns true if the user changes domain
+func (p *UserMovePlan) IsMove() bool {
+	return p.Target.Id != p.Domain.Id
+}
+
+// UserMoveResult - Result of applied plan
+type UserMoveResult struct {
+	UserId    KId        `json:"userId"`             // ID of the user after the change; differs from original one for moves
+	Address   string     `json:"address"`            // new primary address
+	Password  string     `json:"password,omitempty"` // generated password of recreated user
+	Done      StringList `json:"done"`               // descriptions of applied actions
+	Undone    StringList `json:"undone"`             // descriptions of applied actions reverted after the failure, in order of reverting
+	Errors    ErrorList  `json:"errors"`             // errors returned by the server
+	Failure   string     `json:"failure,omitempty"`  // reason why the plan was not finished
+	IsSkipped bool       `json:"isSkipped"`          // plan was not applied because of conflicts
+}
+
+// UserMoveResultList - List of results in the same order as plans
+type UserMoveResultList []UserMoveResult
+
+// PlanUserMoves - Compute side effects of renames and domain moves without changing anything.
+// Group and mailing list membership, role and company contact are preserved, old addresses are kept
+// according to options and new addresses are checked for conflicts with ServerFindEntityByEmail.
+//	requests - users to rename or move
+//	options - nil means keep old addresses as aliases and generate passwords
+// Return
+//	plans - one plan per request in the same order
+func (s *ServerConnection) PlanUserMoves(requests UserMoveRequestList, options *UserMoveOptions) (UserMovePlanList, error) {
+	if options == nil {
+		options = &UserMoveOptions{}
+	}
+	indexes, err := s.newUserIndexList()
+	if err != nil {
+		return nil, err
+	}
+	contacts, _, err := s.CompanyContactsGet(SearchQuery{})
+	if err != nil {
+		return nil, err
+	}
+	var memberships []mlMembers
+	plans := make(UserMovePlanList, 0, len(requests))
+	planned := map[string]int{}
+	for i, request := range requests {
+		index, user, ok := indexes.user(request.UserId)
+		if !ok {
+			return nil, fmt.Errorf("user %q not found", request.UserId)
+		}
+		plan := UserMovePlan{Request: request, User: user, Domain: index.domain, Target: index.domain}
+		if request.NewDomainId != "" {
+			target := indexes.forDomain(request.NewDomainId)
+			if target == nil {
+				return nil, fmt.Errorf("domain %q not found", request.NewDomainId)
+			}
+			plan.Target = target.domain
+		}
+		loginName := request.NewLoginName
+		if loginName == "" {
+			loginName = user.LoginName
+		}
+		plan.OldAddress = index.primaryAddress(user)
+		plan.NewAddress = strings.ToLower(loginName + "@" + plan.Target.Name)
+		if plan.OldAddress == plan.NewAddress {
+			plan.Warnings = append(plan.Warnings, "nothing to change")
+			plans = append(plans, plan)
+			continue
+		}
+		if j, ok := planned[plan.NewAddress]; ok {
+			plan.Conflicts = append(plan.Conflicts, fmt.Sprintf("%s is also requested for user %s", plan.NewAddress, requests[j].UserId))
+		}
+		planned[plan.NewAddress] = i
+		if err = s.checkMoveConflicts(&plan, loginName); err != nil {
+			return nil, err
+		}
+		if !plan.IsMove() {
+			plan.Actions = append(plan.Actions, UserMoveAction{
+				Type:        MoveRenameUser,
+				Description: fmt.Sprintf("rename user %s to %s", user.LoginName, loginName),
+			})
+			plan.addOldAddressActions(options.OldAddress, index.addresses(user))
+			plans = append(plans, plan)
+			continue
+		}
+		plan.Actions = append(plan.Actions, UserMoveAction{
+			Type:        MoveCreateUser,
+			Description: fmt.Sprintf("create user %s in domain %s with role %s", loginName, plan.Target.Name, user.Role.UserRole),
+		})
+		if err = s.planGroupMembership(&plan); err != nil {
+			return nil, err
+		}
+		if memberships == nil {
+			if memberships, err = s.mailingListMemberships(indexes); err != nil {
+				return nil, err
+			}
+		}
+		plan.planCompanyContact(contacts)
+		for _, ml := range memberships {
+			for _, member := range ml.members {
+				if member.HasId && member.UserId == user.Id {
+					member := member
+					plan.Actions = append(plan.Actions, UserMoveAction{
+						Type:        MoveReplaceMlMember,
+						Description: fmt.Sprintf("replace %s by %s in mailing list %s as %s", plan.OldAddress, plan.NewAddress, ml.address, member.Kind),
+						Id:          ml.id,
+						Member:      &member,
+					})
+				}
+			}
+		}
+		plan.Actions = append(plan.Actions, UserMoveAction{
+			Type:        MoveMoveMailbox,
+			Description: fmt.Sprintf("remove user %s and move his mailbox to %s", plan.OldAddress, plan.NewAddress),
+		})
+		mode := options.OldAddress
+		if mode == KeepAsEmailAddress {
+			mode = KeepAsAlias
+		}
+		plan.addOldAddressActions(mode, index.addresses(user))
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// ApplyUserMoves - Apply plans computed by PlanUserMoves. Plans with conflicts are skipped,
+// a failure of one plan does not stop the others. Actions of the failed plan are reverted in reverse order
+// up to the mailbox move, which cannot be reverted; Done and Undone of the result tell what is left.
+//	plans - plans to apply
+//	options - the same options as used for planning
+// Return
+//	results - one result per plan in the same order
+func (s *ServerConnection) ApplyUserMoves(plans UserMovePlanList, options *UserMoveOptions) (UserMoveResultList, error) {
+	if options == nil {
+		options = &UserMoveOptions{}
+	}
+	results := make(UserMoveResultList, 0, len(plans))
+	failed := 0
+	for _, plan := range plans {
+		result := UserMoveResult{UserId: plan.User.Id, Address: plan.NewAddress}
+		if len(plan.Conflicts) > 0 {
+			result.IsSkipped = true
+		} else if err := s.applyUserMove(plan, options, &result); err != nil {
+			failed++
+			result.Failure = err.Error()
+		}
+		results = append(results, result)
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d user moves failed", failed, len(plans))
+	}
+	return results, nil
+}
+
+// userMoveStep - Applied action and how to revert it; nil undo means the action cannot be reverted
+type userMoveStep struct {
+	description string
+	undo        func() (ErrorList, error)
+}
+
+func (s *ServerConnection) applyUserMove(plan UserMovePlan, options *UserMoveOptions, result *UserMoveResult) error {
+	var applied []userMoveStep
+	err := s.applyUserMoveActions(plan, options, result, &applied)
+	if err == nil {
+		return nil
+	}
+	if rollbackErr := s.rollbackUserMove(applied, result); rollbackErr != nil {
+		return fmt.Errorf("%v; rollback stopped: %v", err, rollbackErr)
+	}
+	return err
+}
+
+func (s *ServerConnection) applyUserMoveActions(plan UserMovePlan, options *UserMoveOptions, result *UserMoveResult, applied *[]userMoveStep) error {
+	user := plan.User
+	loginName, _ := splitAddress(plan.NewAddress)
+	if plan.Request.NewLoginName != "" {
+		loginName = plan.Request.NewLoginName
+	}
+	var extraAddresses UserEmailAddressList
+	var contactId KId
+	for _, action := range plan.Actions {
+		switch action.Type {
+		case MoveAddEmailAddress:
+			extraAddresses = append(extraAddresses, action.Address)
+		case MoveSetCompanyContact:
+			contactId = action.Id
+		}
+	}
+	for _, action := range plan.Actions {
+		action := action
+		var errors ErrorList
+		var err error
+		var undo func() (ErrorList, error)
+		final := false // the action succeeded and cannot be reverted
+		switch action.Type {
+		case MoveRenameUser:
+			addresses := append(append(UserEmailAddressList{}, user.EmailAddresses...), extraAddresses...)
+			if errors, err = s.setUserFields(user.Id, map[string]interface{}{"loginName": loginName, "emailAddresses": addresses}); err == nil && len(errors) == 0 {
+				undo = func() (ErrorList, error) {
+					return s.setUserFields(user.Id, map[string]interface{}{"loginName": user.LoginName, "emailAddresses": user.EmailAddresses})
+				}
+			}
+		case MoveCreateUser:
+			pattern := user
+			pattern.Id = ""
+			pattern.DomainId = plan.Target.Id
+			pattern.LoginName = loginName
+			pattern.CompanyContactId = contactId
+			pattern.UserGroups = nil
+			pattern.EmailAddresses = UserEmailAddressList{}
+			for _, address := range user.EmailAddresses {
+				if !strings.Contains(address, "@") {
+					pattern.EmailAddresses = append(pattern.EmailAddresses, address)
+				}
+			}
+			pattern.Password = options.Password
+			if pattern.Password == "" {
+				if pattern.Password, err = s.DomainsGeneratePassword(plan.Target.Id); err != nil {
+					return err
+				}
+				result.Password = pattern.Password
+			}
+			var created CreateResultList
+			errors, created, err = s.UsersCreate(UserList{pattern})
+			if err == nil && len(errors) == 0 && len(created) == 1 {
+				result.UserId = created[0].Id
+				undo = func() (ErrorList, error) {
+					errors, err := s.UsersRemove(RemovalRequestList{{UserId: created[0].Id, Method: UDeleteFolder, Mode: DSModeDelete}})
+					if err == nil && len(errors) == 0 {
+						result.UserId = user.Id
+					}
+					return errors, err
+				}
+			}
+		case MoveAddGroupMember:
+			newId := result.UserId
+			if errors, err = s.GroupsAddMemberList(action.Id, KIdList{newId}); err == nil && len(errors) == 0 {
+				undo = func() (ErrorList, error) {
+					return s.GroupsRemoveMemberList(action.Id, KIdList{newId})
+				}
+			}
+		case MoveReplaceMlMember:
+			member := *action.Member
+			member.UserId = result.UserId
+			if errors, err = s.MailingListsAddMlUserList(UserOrEmailList{member}, action.Id); err == nil && len(errors) == 0 {
+				// the new member is added even if removal of the old one fails
+				undo = func() (ErrorList, error) {
+					return s.MailingListsRemoveMlUserList(UserOrEmailList{member}, action.Id)
+				}
+				if errors, err = s.MailingListsRemoveMlUserList(UserOrEmailList{*action.Member}, action.Id); err == nil && len(errors) == 0 {
+					undo = func() (ErrorList, error) {
+						errors, err := s.MailingListsAddMlUserList(UserOrEmailList{*action.Member}, action.Id)
+						if err != nil || len(errors) > 0 {
+							return errors, err
+						}
+						return s.MailingListsRemoveMlUserList(UserOrEmailList{member}, action.Id)
+					}
+				}
+			}
+		case MoveMoveMailbox:
+			errors, err = s.UsersRemove(RemovalRequestList{{
+				UserId:       user.Id,
+				Method:       UMoveFolder,
+				TargetUserId: result.UserId,
+				Mode:         DSModeDelete,
+			}})
+			final = err == nil && len(errors) == 0
+		case MoveAddAlias:
+			local, _ := splitAddress(action.Address)
+			var created CreateResultList
+			errors, created, err = s.AliasesCreate(AliasList{{
+				DomainId:    plan.Domain.Id,
+				Name:        local,
+				DeliverTo:   plan.NewAddress,
+				Type:        TypeEmailAddress,
+				Description: "old address of " + plan.NewAddress,
+			}})
+			if err == nil && len(errors) == 0 && len(created) == 1 {
+				undo = func() (ErrorList, error) {
+					return s.AliasesRemove(KIdList{created[0].Id})
+				}
+			}
+		case MoveAddEmailAddress, MoveSetCompanyContact:
+			// applied and reverted together with MoveRenameUser or MoveCreateUser
+			result.Done = append(result.Done, action.Description)
+			continue
+		}
+		if err == nil && len(errors) == 0 && action.Type == MoveCreateUser && result.UserId == user.Id {
+			return fmt.Errorf("%s: server did not return ID of the new user", action.Description)
+		}
+		if undo != nil || final {
+			*applied = append(*applied, userMoveStep{description: action.Description, undo: undo})
+		}
+		if err != nil {
+			return err
+		}
+		result.Errors = append(result.Errors, errors...)
+		if len(errors) > 0 {
+			return fmt.Errorf("%s: %s", action.Description, errors[0].Message)
+		}
+		result.Done = append(result.Done, action.Description)
+	}
+	return nil
+}
+
+// rollbackUserMove reverts applied actions in reverse order, it stops at an action which cannot be reverted
+func (s *ServerConnection) rollbackUserMove(applied []userMoveStep, result *UserMoveResult) error {
+	for i := len(applied) - 1; i >= 0; i-- {
+		step := applied[i]
+		if step.undo == nil {
+			return fmt.Errorf("%s cannot be reverted", step.description)
+		}
+		errors, err := step.undo()
+		if err == nil && len(errors) > 0 {
+			err = fmt.Errorf("%s", errors[0].Message)
+		}
+		if err != nil {
+			return fmt.Errorf("revert %s: %v", step.description, err)
+		}
+		result.Undone = append(result.Undone, step.description)
+	}
+	return nil
+}
+
+// setUserFields changes only the given fields of the user. UsersSet sends the whole pattern,
+// which would also write back fields read at planning time.
+func (s *ServerConnection) setUserFields(userId KId, fields map[string]interface{}) (ErrorList, error) {
+	params := struct {
+		UserIds KIdList                `json:"userIds"`
+		Pattern map[string]interface{} `json:"pattern"`
+	}{KIdList{userId}, fields}
+	data, err := s.CallRaw("Users.set", params)
+	if err != nil {
+		return nil, err
+	}
+	errors := struct {
+		Result struct {
+			Errors ErrorList `json:"errors"`
+		} `json:"result"`
+	}{}
+	err = s.unmarshal(data, &errors)
+	return errors.Result.Errors, err
+}
+
+// checkMoveConflicts asks the server which entities already use new addresses of the user
+func (s *ServerConnection) checkMoveConflicts(plan *UserMovePlan, loginName string) error {
+	addresses := StringList{loginName}
+	if plan.IsMove() {
+		for _, address := range plan.User.EmailAddresses {
+			if !strings.Contains(address, "@") {
+				addresses = append(addresses, address)
+			}
+		}
+	}
+	self := EntityDetail{Kind: EntityUser}
+	if !plan.IsMove() {
+		self.Id = plan.User.Id
+	}
+	duplicates, err := s.ServerFindEntityByEmail(addresses, self, plan.Target.Id)
+	if err != nil {
+		return err
+	}
+	for _, duplicate := range duplicates {
+		if duplicate.IsPattern {
+			continue
+		}
+		plan.Conflicts = append(plan.Conflicts, fmt.Sprintf("%s is used by %s %s", duplicate.CollisionAddress, strings.TrimPrefix(string(duplicate.Kind), "Entity"), duplicate.Name))
+	}
+	return nil
+}
+
+// planGroupMembership maps groups of the user to groups with the same name in the target domain
+func (s *ServerConnection) planGroupMembership(plan *UserMovePlan) error {
+	if len(plan.User.UserGroups) == 0 {
+		return nil
+	}
+	groups, _, err := s.GroupsGet(SearchQuery{}, plan.Target.Id)
+	if err != nil {
+		return err
+	}
+	for _, membership := range plan.User.UserGroups {
+		found := false
+		for _, group := range groups {
+			if strings.EqualFold(group.Name, membership.Name) {
+				plan.Actions = append(plan.Actions, UserMoveAction{
+					Type:        MoveAddGroupMember,
+					Description: fmt.Sprintf("add %s to group %s", plan.NewAddress, group.Name),
+					Id:          group.Id,
+				})
+				found = true
+				break
+			}
+		}
+		if !found {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("group %s does not exist in domain %s, membership will be lost", membership.Name, plan.Target.Name))
+		}
+	}
+	return nil
+}
+
+// planCompanyContact keeps a global company contact or maps a domain one to the contact with the same name in the target domain
+func (p *UserMovePlan) planCompanyContact(contacts CompanyContactList) {
+	if p.User.CompanyContactId == "" {
+		return
+	}
+	var current *CompanyContact
+	for i := range contacts {
+		if contacts[i].Id == p.User.CompanyContactId {
+			current = &contacts[i]
+		}
+	}
+	if current == nil {
+		p.Warnings = append(p.Warnings, fmt.Sprintf("company contact %s does not exist", p.User.CompanyContactId))
+		return
+	}
+	if current.DomainId == "" {
+		p.Actions = append(p.Actions, UserMoveAction{Type: MoveSetCompanyContact, Description: "link global company contact " + current.Name, Id: current.Id})
+		return
+	}
+	for _, contact := range contacts {
+		if contact.DomainId == p.Target.Id && strings.EqualFold(contact.Name, current.Name) {
+			p.Actions = append(p.Actions, UserMoveAction{Type: MoveSetCompanyContact, Description: "link company contact " + contact.Name + " of domain " + p.Target.Name, Id: contact.Id})
+			return
+		}
+	}
+	p.Warnings = append(p.Warnings, fmt.Sprintf("company contact %s does not exist in domain %s, link will be lost", current.Name, p.Target.Name))
+}
+
+// addOldAddressActions plans how addresses of the user in the old domain keep receiving mail
+func (p *UserMovePlan) addOldAddressActions(mode OldAddressMode, addresses StringList) {
+	if mode == DropOldAddress {
+		p.Warnings = append(p.Warnings, p.OldAddress+" will stop receiving mail")
+		return
+	}
+	oldDomain := strings.ToLower(p.Domain.Name)
+	for _, address := range addresses {
+		local, domain := splitAddress(address)
+		if domain != oldDomain || address == p.NewAddress {
+			continue
+		}
+		if address != p.OldAddress && !p.IsMove() {
+			continue
+		}
+		if mode == KeepAsEmailAddress {
+			p.Actions = append(p.Actions, UserMoveAction{Type: MoveAddEmailAddress, Description: fmt.Sprintf("keep %s as email address of the user", address), Address: local})
+			continue
+		}
+		p.Actions = append(p.Actions, UserMoveAction{Type: MoveAddAlias, Description: fmt.Sprintf("create alias %s delivering to %s", address, p.NewAddress), Address: address})
+	}
+}
+
+// mlMembers - Members of one mailing list
+type mlMembers struct {
+	id      KId
+	address string
+	members UserOrEmailList
+}
+
+// mailingListMemberships loads members of all mailing lists
+func (s *ServerConnection) mailingListMemberships(indexes userIndexList) ([]mlMembers, error) {
+	var memberships []mlMembers
+	for _, index := range indexes {
+		mls, _, err := s.MailingListsGet(SearchQuery{}, index.domain.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, ml := range mls {
+			members, _, err := s.MailingListsGetMlUserList(SearchQuery{}, ml.Id)
+			if err != nil {
+				return nil, err
+			}
+			memberships = append(memberships, mlMembers{id: ml.Id, address: index.qualify(ml.Name), members: members})
+		}
+	}
+	return memberships, nil
+}
@@ -0,0 +1,179 @@
+package connect
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPlanAndApplyUserMoves(t *testing.T) {
+	var created UserList
+	var removed RemovalRequestList
+	var aliases AliasList
+	conn, fake := newFakeConnection(t, map[string]fakeHandler{
+		"Domains.get": result(map[string]interface{}{"list": DomainList{{Id: "d1", Name: "old.com"}, {Id: "d2", Name: "new.com"}}}),
+		"Users.get": func(params json.RawMessage) interface{} {
+			p := struct {
+				DomainId KId `json:"domainId"`
+			}{}
+			_ = json.Unmarshal(params, &p)
+			if p.DomainId == "d2" {
+				return map[string]interface{}{"list": UserList{{Id: "n1", LoginName: "taken"}}}
+			}
+			return map[string]interface{}{"list": UserList{
+				{Id: "u1", LoginName: "jdoe", CompanyContactId: "c1", EmailAddresses: UserEmailAddressList{"john"},
+					UserGroups: UserGroupList{{Id: "g1", Name: "Sales"}, {Id: "g2", Name: "Board"}}},
+				{Id: "u2", LoginName: "mary"},
+			}}
+		},
+		"CompanyContacts.get": result(map[string]interface{}{"list": CompanyContactList{
+			{Id: "c1", Name: "HQ", DomainId: "d1"}, {Id: "c2", Name: "HQ", DomainId: "d2"},
+		}}),
+		"Groups.get":       result(map[string]interface{}{"list": GroupList{{Id: "g9", Name: "sales", DomainId: "d2"}}}),
+		"MailingLists.get": result(map[string]interface{}{"list": MlList{}}),
+		"Server.findEntityByEmail": func(params json.RawMessage) interface{} {
+			p := struct {
+				Addresses StringList `json:"addresses"`
+			}{}
+			_ = json.Unmarshal(params, &p)
+			if p.Addresses[0] == "taken" {
+				return map[string]interface{}{"entities": EntityDuplicateList{{Kind: EntityUser, Name: "taken", CollisionAddress: "taken@new.com"}}}
+			}
+			return map[string]interface{}{"entities": EntityDuplicateList{}}
+		},
+		"Domains.generatePassword": result(map[string]interface{}{"password": "S3cret!"}),
+		"Users.create": func(params json.RawMessage) interface{} {
+			p := struct {
+				Users UserList `json:"users"`
+			}{}
+			_ = json.Unmarshal(params, &p)
+			created = append(created, p.Users...)
+			return map[string]interface{}{"errors": ErrorList{}, "result": CreateResultList{{Id: "n2"}}}
+		},
+		"Groups.addMemberList": result(map[string]interface{}{"errors": ErrorList{}}),
+		"Users.remove": func(params json.RawMessage) interface{} {
+			p := struct {
+				Requests RemovalRequestList `json:"requests"`
+			}{}
+			_ = json.Unmarshal(params, &p)
+			removed = append(removed, p.Requests...)
+			return map[string]interface{}{"errors": ErrorList{}}
+		},
+		"Aliases.create": func(params json.RawMessage) interface{} {
+			p := struct {
+				Aliases AliasList `json:"aliases"`
+			}{}
+			_ = json.Unmarshal(params, &p)
+			aliases = append(aliases, p.Aliases...)
+			return map[string]interface{}{"errors": ErrorList{}, "result": CreateResultList{{Id: "a1"}}}
+		},
+	})
+	requests := UserMoveRequestList{
+		{UserId: "u1", NewLoginName: "john.doe", NewDomainId: "d2"},
+		{UserId: "u2", NewLoginName: "taken", NewDomainId: "d2"},
+	}
+	plans, err := conn.PlanUserMoves(requests, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plans[0].Conflicts) != 0 || len(plans[1].Conflicts) != 1 {
+		t.Fatalf("unexpected conflicts: %v / %v", plans[0].Conflicts, plans[1].Conflicts)
+	}
+	var types []UserMoveActionType
+	for _, action := range plans[0].Actions {
+		types = append(types, action.Type)
+	}
+	expected := []UserMoveActionType{MoveCreateUser, MoveAddGroupMember, MoveSetCompanyContact, MoveMoveMailbox, MoveAddAlias, MoveAddAlias}
+	if len(types) != len(expected) {
+		t.Fatalf("unexpected actions: %v", types)
+	}
+	for i := range expected {
+		if types[i] != expected[i] {
+			t.Fatalf("unexpected actions: %v", types)
+		}
+	}
+	if len(plans[0].Warnings) != 1 {
+		t.Errorf("missing group should be reported: %v", plans[0].Warnings)
+	}
+	if fake.called("Users.create") != 0 {
+		t.Fatal("planning must not change anything")
+	}
+	data, err := json.Marshal(plans)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reviewed UserMovePlanList
+	if err = json.Unmarshal(data, &reviewed); err != nil {
+		t.Fatal(err)
+	}
+	results, err := conn.ApplyUserMoves(reviewed, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[1].IsSkipped || results[0].UserId != "n2" || results[0].Password != "S3cret!" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+	if len(created) != 1 || created[0].DomainId != "d2" || created[0].LoginName != "john.doe" || created[0].CompanyContactId != "c2" {
+		t.Errorf("unexpected created user: %+v", created)
+	}
+	if len(removed) != 1 || removed[0].Method != UMoveFolder || removed[0].TargetUserId != "n2" {
+		t.Errorf("unexpected removal: %+v", removed)
+	}
+	if len(aliases) != 2 || aliases[0].Name != "jdoe" || aliases[0].DeliverTo != "john.doe@new.com" || aliases[1].Name != "john" {
+		t.Errorf("unexpected aliases: %+v", aliases)
+	}
+}
+
+func TestApplyUserMovesRollback(t *testing.T) {
+	var patterns []map[string]interface{}
+	var removed RemovalRequestList
+	conn, fake := newFakeConnection(t, map[string]fakeHandler{
+		"Users.set": func(params json.RawMessage) interface{} {
+			p := struct {
+				Pattern map[string]interface{} `json:"pattern"`
+			}{}
+			_ = json.Unmarshal(params, &p)
+			patterns = append(patterns, p.Pattern)
+			return map[string]interface{}{"errors": ErrorList{}}
+		},
+		"Aliases.create":       result(map[string]interface{}{"errors": ErrorList{{Message: "alias exists"}}, "result": CreateResultList{}}),
+		"Users.create":         result(map[string]interface{}{"errors": ErrorList{}, "result": CreateResultList{{Id: "n2"}}}),
+		"Groups.addMemberList": result(map[string]interface{}{"errors": ErrorList{{Message: "group is read-only"}}}),
+		"Users.remove": func(params json.RawMessage) interface{} {
+			p := struct {
+				Requests RemovalRequestList `json:"requests"`
+			}{}
+			_ = json.Unmarshal(params, &p)
+			removed = append(removed, p.Requests...)
+			return map[string]interface{}{"errors": ErrorList{}}
+		},
+	})
+	user := User{Id: "u1", LoginName: "jdoe", FullName: "John Doe", EmailAddresses: UserEmailAddressList{"john"}}
+	plans := UserMovePlanList{
+		{
+			OldAddress: "jdoe@old.com", NewAddress: "john.doe@old.com", Request: UserMoveRequest{UserId: "u1", NewLoginName: "john.doe"},
+			User: user, Domain: Domain{Id: "d1", Name: "old.com"}, Target: Domain{Id: "d1", Name: "old.com"},
+			Actions: UserMoveActionList{{Type: MoveRenameUser, Description: "rename"}, {Type: MoveAddAlias, Description: "alias", Address: "jdoe@old.com"}},
+		},
+		{
+			OldAddress: "jdoe@old.com", NewAddress: "jdoe@new.com", Request: UserMoveRequest{UserId: "u1", NewDomainId: "d2"},
+			User: user, Domain: Domain{Id: "d1", Name: "old.com"}, Target: Domain{Id: "d2", Name: "new.com"},
+			Actions: UserMoveActionList{{Type: MoveCreateUser, Description: "create"}, {Type: MoveAddGroupMember, Description: "group", Id: "g9"}, {Type: MoveMoveMailbox, Description: "mailbox"}},
+		},
+	}
+	results, err := conn.ApplyUserMoves(plans, &UserMoveOptions{Password: "S3cret!"})
+	if err == nil || err.Error() != "2 of 2 user moves failed" {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(patterns) != 2 || len(patterns[0]) != 2 || patterns[0]["loginName"] != "john.doe" || patterns[1]["loginName"] != "jdoe" {
+		t.Errorf("unexpected patterns %+v", patterns)
+	}
+	if results[0].Failure != "alias: alias exists" || len(results[0].Undone) != 1 || results[0].Undone[0] != "rename" {
+		t.Errorf("unexpected rename result %+v", results[0])
+	}
+	if len(removed) != 1 || removed[0].UserId != "n2" || removed[0].Method != UDeleteFolder || fake.called("Users.remove") != 1 {
+		t.Errorf("unexpected removal %+v", removed)
+	}
+	if results[1].UserId != "u1" || len(results[1].Done) != 1 || len(results[1].Undone) != 1 {
+		t.Errorf("unexpected move result %+v", results[1])
+	}
+}
@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 // ValidFor - User Template Scope
 type ValidFor string
 
@@ -57,7 +55,7 @@ func (s *ServerConnection) UserTemplatesCreate(userTemplates UserTemplateList) (
 			Result CreateResultList `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, errors.Result.Result, err
 }
 
@@ -81,7 +79,7 @@ func (s *ServerConnection) UserTemplatesGet(query SearchQuery) (UserTemplateList
 			TotalItems       int              `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &userTemplateList)
+	err = s.unmarshal(data, &userTemplateList)
 	return userTemplateList.Result.UserTemplateList, userTemplateList.Result.TotalItems, err
 }
 
@@ -102,7 +100,7 @@ func (s *ServerConnection) UserTemplatesGetAvailable(domainId KId) (UserTemplate
 			UserTemplateList UserTemplateList `json:"userTemplateList"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &userTemplateList)
+	err = s.unmarshal(data, &userTemplateList)
 	return userTemplateList.Result.UserTemplateList, err
 }
 
@@ -123,7 +121,7 @@ func (s *ServerConnection) UserTemplatesRemove(idList KIdList) (ErrorList, error
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -146,6 +144,6 @@ func (s *ServerConnection) UserTemplatesSet(idList KIdList, pattern UserTemplate
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 // UserVoiceSettings - Settings of UserVoice
 type UserVoiceSettings struct {
 	Name  string `json:"name"`
@@ -23,7 +21,7 @@ func (s *ServerConnection) UserVoiceGet() (*UserVoiceSettings, error) {
 			Settings UserVoiceSettings `json:"settings"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &settings)
+	err = s.unmarshal(data, &settings)
 	return &settings.Result.Settings, err
 }
 
@@ -40,7 +38,7 @@ func (s *ServerConnection) UserVoiceGetStatus() (bool, error) {
 			IsSet bool `json:"isSet"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &isSet)
+	err = s.unmarshal(data, &isSet)
 	return isSet.Result.IsSet, err
 }
 
@@ -63,7 +61,7 @@ func (s *ServerConnection) UserVoiceGetUrl(name string, email string) (string, e
 			Url string `json:"url"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &url)
+	err = s.unmarshal(data, &url)
 	return url.Result.Url, err
 }
 
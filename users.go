@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 type PublicFolder struct {
 	Id   KId    `json:"id"`
 	Name string `json:"name"`
@@ -268,7 +266,7 @@ func (s *ServerConnection) UsersActivate(userIds KIdList) (ErrorList, error) {
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -291,7 +289,7 @@ func (s *ServerConnection) UsersActivateOnServer(userIds KIdList, homeServerId K
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -317,7 +315,7 @@ func (s *ServerConnection) UsersConnectFromExternalService(service string, conne
 			Result bool `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &result)
+	err = s.unmarshal(data, &result)
 	return result.Result.Result, err
 }
 
@@ -345,7 +343,7 @@ func (s *ServerConnection) UsersAuthenticateConnectionFromExternalService(userNa
 			Result AuthResult `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &result)
+	err = s.unmarshal(data, &result)
 	return &result.Result.Result, err
 }
 
@@ -402,7 +400,7 @@ func (s *ServerConnection) UsersCreate(users UserList) (ErrorList, CreateResultL
 			Result CreateResultList `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, errors.Result.Result, err
 }
 
@@ -425,7 +423,7 @@ func (s *ServerConnection) UsersCreateLdap(users UserList) (ErrorList, CreateRes
 			Result CreateResultList `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, errors.Result.Result, err
 }
 
@@ -448,7 +446,7 @@ func (s *ServerConnection) UsersExportStatistics(userIds KIdList, format FileFor
 			FileDownload Download `json:"fileDownload"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &fileDownload)
+	err = s.unmarshal(data, &fileDownload)
 	return &fileDownload.Result.FileDownload, err
 }
 
@@ -474,7 +472,7 @@ func (s *ServerConnection) UsersExportToCsv(filename string, query SearchQuery,
 			FileDownload Download `json:"fileDownload"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &fileDownload)
+	err = s.unmarshal(data, &fileDownload)
 	return &fileDownload.Result.FileDownload, err
 }
 
@@ -500,7 +498,7 @@ func (s *ServerConnection) UsersGet(query SearchQuery, domainId KId) (UserList,
 			TotalItems int      `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -519,7 +517,7 @@ func (s *ServerConnection) UsersGetContactPublicFolderList(domainId KId) (Public
 			PublicFolders PublicFolderList `json:"publicFolders"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &publicFolders)
+	err = s.unmarshal(data, &publicFolders)
 	return publicFolders.Result.PublicFolders, err
 }
 
@@ -542,7 +540,7 @@ func (s *ServerConnection) UsersGetFromServer(importServer ImportServer, domainT
 			NewUsers ImporteeList `json:"newUsers"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &newUsers)
+	err = s.unmarshal(data, &newUsers)
 	return newUsers.Result.NewUsers, err
 }
 
@@ -559,7 +557,7 @@ func (s *ServerConnection) UsersGetMailboxCount() (*MailboxCount, error) {
 			Count MailboxCount `json:"count"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &count)
+	err = s.unmarshal(data, &count)
 	return &count.Result.Count, err
 }
 
@@ -585,7 +583,7 @@ func (s *ServerConnection) UsersGetMobileDeviceList(userId KId, query SearchQuer
 			TotalItems int              `json:"totalItems"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, list.Result.TotalItems, err
 }
 
@@ -606,7 +604,7 @@ func (s *ServerConnection) UsersGetNotActivated(domainId KId) (ImporteeList, err
 			NewUsers ImporteeList `json:"newUsers"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &newUsers)
+	err = s.unmarshal(data, &newUsers)
 	return newUsers.Result.NewUsers, err
 }
 
@@ -628,7 +626,7 @@ func (s *ServerConnection) UsersGetRecoveryDeletedItemsSize(userIds KIdList) (Er
 			SizeList QuotaUsageList `json:"sizeList"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, errors.Result.SizeList, err
 }
 
@@ -652,7 +650,7 @@ func (s *ServerConnection) UsersGetStatistics(userIds KIdList, query SearchQuery
 			List UserStatList `json:"list"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &list)
+	err = s.unmarshal(data, &list)
 	return list.Result.List, err
 }
 
@@ -675,7 +673,7 @@ func (s *ServerConnection) UsersParseFromCsv(fileId string, domainToImport KId)
 			Users ImporteeList `json:"users"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &users)
+	err = s.unmarshal(data, &users)
 	return users.Result.Users, err
 }
 
@@ -697,7 +695,7 @@ func (s *ServerConnection) UsersRecoverDeletedItems(userIds KIdList) (ErrorList,
 			RecoveryMessages ResultTripletList `json:"recoveryMessages"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, errors.Result.RecoveryMessages, err
 }
 
@@ -718,7 +716,7 @@ func (s *ServerConnection) UsersRemove(requests RemovalRequestList) (ErrorList,
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -763,7 +761,7 @@ func (s *ServerConnection) UsersGetEffectiveUserRights(userIds KIdList) (ErrorLi
 			Result EffectiveUserRightsList `json:"result"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, errors.Result.Result, err
 }
 
@@ -786,7 +784,7 @@ func (s *ServerConnection) UsersSet(userIds KIdList, pattern User) (ErrorList, e
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
 
@@ -817,7 +815,7 @@ func (s *ServerConnection) UsersGetPersonalContact(userIds KIdList) (ErrorList,
 			Contacts PersonalContactList `json:"contacts"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, errors.Result.Contacts, err
 }
 
@@ -836,6 +834,6 @@ func (s *ServerConnection) UsersSetPersonalContact(userIds KIdList, contact Pers
 			Errors ErrorList `json:"errors"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &errors)
+	err = s.unmarshal(data, &errors)
 	return errors.Result.Errors, err
 }
@@ -1,7 +1,5 @@
 package connect
 
-import "encoding/json"
-
 type BuildType string
 
 const (
@@ -42,7 +40,7 @@ func (s *ServerConnection) VersionGetProductVersion() (*ProductVersion, error) {
 			ProductVersion ProductVersion `json:"productVersion"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &productVersion)
+	err = s.unmarshal(data, &productVersion)
 	return &productVersion.Result.ProductVersion, err
 }
 
@@ -57,6 +55,6 @@ func (s *ServerConnection) VersionGetApiVersion() (*ApiVersion, error) {
 			ApiVersion ApiVersion `json:"apiVersion"`
 		} `json:"result"`
 	}{}
-	err = json.Unmarshal(data, &apiVersion)
+	err = s.unmarshal(data, &apiVersion)
 	return &apiVersion.Result.ApiVersion, err
 }